@@ -4,11 +4,18 @@ import (
 	"go-project-manager-backend/internal/config"
 	"go-project-manager-backend/internal/domain/services"
 	"go-project-manager-backend/internal/infrastructure/database"
+	"go-project-manager-backend/internal/infrastructure/mail"
 	"go-project-manager-backend/internal/infrastructure/repositories"
 	"go-project-manager-backend/internal/interfaces/http/handlers"
 	"go-project-manager-backend/internal/interfaces/http/middleware"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	// Users' timezones must load in images without a zoneinfo database
+	_ "time/tzdata"
 )
 
 func main() {
@@ -28,35 +35,144 @@ func main() {
 	}
 	defer db.Close()
 
-	// Initialize MongoDB repositories
+	// Users and organizations are shared by the whole deployment; everything
+	// else lives in the database of the organization it belongs to
 	userRepository := repositories.NewMongoUserRepository(db.Database)
-	taskRepository := repositories.NewMongoTaskRepository(db.Database)
+	organizationRepository := repositories.NewMongoOrganizationRepository(db.Database)
+
+	// Send notification emails through SMTP when configured, or else log them
+	var mailer services.Mailer = mail.NewLogMailer()
+	if smtpHost := config.GetEnv("SMTP_HOST", ""); smtpHost != "" {
+		mailer = mail.NewSMTPMailer(
+			smtpHost,
+			config.GetEnv("SMTP_PORT", "587"),
+			config.GetEnv("SMTP_USERNAME", ""),
+			config.GetEnv("SMTP_PASSWORD", ""),
+			config.GetEnv("MAIL_FROM", "notifications@localhost"),
+		)
+	}
+	unsubscribeSecret := config.GetEnv("UNSUBSCRIBE_SECRET", "")
+	if unsubscribeSecret == "" {
+		log.Fatal("UNSUBSCRIBE_SECRET must be set to sign unsubscribe links")
+	}
+	unsubscribeLinks := services.NewUnsubscribeLinks(config.GetEnv("PUBLIC_BASE_URL", "http://localhost:8080"), []byte(unsubscribeSecret))
+	email := emailSetup{
+		mailer:        mailer,
+		links:         unsubscribeLinks,
+		defaultLocale: config.GetEnv("MAIL_DEFAULT_LOCALE", "en"),
+	}
+
+	openDatabase := func(name string) (*workspaceRepositories, error) {
+		return mongoRepositories(db.Client.Database(name))
+	}
+	tenants := newWorkspaces(openDatabase, mongoDB, userRepository, organizationRepository, email)
+	if _, err := tenants.get(""); err != nil {
+		log.Fatalf("Failed to open the default workspace: %v", err)
+	}
 
-	// Initialize services
-	userService := services.NewUserService(userRepository)
-	taskService := services.NewTaskService(taskRepository)
+	// Respread task ranks that have grown long from repeated reordering
+	rebalanceInterval, err := time.ParseDuration(config.GetEnv("RANK_REBALANCE_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatalf("Invalid RANK_REBALANCE_INTERVAL: %v", err)
+	}
+	go func() {
+		for range time.Tick(rebalanceInterval) {
+			tenants.each("Rank rebalance", func(w *workspace) error { return w.taskService.RebalanceAllRanks() })
+		}
+	}()
+
+	// Snapshot active sprints for their burndowns
+	snapshotInterval, err := time.ParseDuration(config.GetEnv("SPRINT_SNAPSHOT_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatalf("Invalid SPRINT_SNAPSHOT_INTERVAL: %v", err)
+	}
+	go func() {
+		for range time.Tick(snapshotInterval) {
+			tenants.each("Sprint snapshot", func(w *workspace) error { return w.burndownService.RecordSnapshots() })
+		}
+	}()
+
+	// Create the tasks of recurring schedules as they come due
+	recurringInterval, err := time.ParseDuration(config.GetEnv("RECURRING_TASK_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid RECURRING_TASK_INTERVAL: %v", err)
+	}
+	go func() {
+		for now := range time.Tick(recurringInterval) {
+			tenants.each("Recurring task run", func(w *workspace) error { return w.recurringTaskService.RunDue(now) })
+		}
+	}()
+
+	// Purge the trash of what was deleted more than the retention period ago
+	retentionDays, err := strconv.Atoi(config.GetEnv("TRASH_RETENTION_DAYS", "30"))
+	if err != nil {
+		log.Fatalf("Invalid TRASH_RETENTION_DAYS: %v", err)
+	}
+	purgeInterval, err := time.ParseDuration(config.GetEnv("TRASH_PURGE_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatalf("Invalid TRASH_PURGE_INTERVAL: %v", err)
+	}
+	go func() {
+		for now := range time.Tick(purgeInterval) {
+			tenants.each("Trash purge", func(w *workspace) error {
+				_, _, err := w.trashService.PurgeExpired(now.AddDate(0, 0, -retentionDays))
+				return err
+			})
+		}
+	}()
+
+	// Tell assignees about their tasks coming due
+	dueSoonInterval, err := time.ParseDuration(config.GetEnv("DUE_SOON_INTERVAL", "15m"))
+	if err != nil {
+		log.Fatalf("Invalid DUE_SOON_INTERVAL: %v", err)
+	}
+	dueSoonWindow, err := time.ParseDuration(config.GetEnv("DUE_SOON_WINDOW", "24h"))
+	if err != nil {
+		log.Fatalf("Invalid DUE_SOON_WINDOW: %v", err)
+	}
+	go func() {
+		for now := range time.Tick(dueSoonInterval) {
+			tenants.each("Due soon notification", func(w *workspace) error {
+				return w.notificationService.NotifyDueSoon(now, dueSoonWindow)
+			})
+		}
+	}()
+
+	// Email pending notifications as each user's settings say
+	emailInterval, err := time.ParseDuration(config.GetEnv("EMAIL_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid EMAIL_INTERVAL: %v", err)
+	}
+	go func() {
+		for now := range time.Tick(emailInterval) {
+			tenants.each("Notification email", func(w *workspace) error { return w.emailService.SendDue(now) })
+		}
+	}()
+
+	// Operators are listed by user ID; admins of the default workspace are not
+	// operators unless listed.
+	operatorIDs := strings.FieldsFunc(config.GetEnv("OPERATOR_IDS", ""), func(r rune) bool { return r == ',' || r == ' ' })
 
 	// Initialize handlers
-	userHandler := handlers.NewUserHandler(userService)
-	taskHandler := handlers.NewTaskHandler(taskService)
+	userHandler := handlers.NewUserHandler(services.NewUserService(userRepository))
+	organizationHandler := handlers.NewOrganizationHandler(services.NewOrganizationService(organizationRepository, userRepository), operatorIDs)
+	unsubscribeHandler := handlers.NewUnsubscribeHandler(services.NewUnsubscribeService(userRepository, unsubscribeLinks))
 
 	mux := http.NewServeMux()
 
 	// Public routes
 	mux.HandleFunc("POST /register", userHandler.Register)
 	mux.HandleFunc("POST /login", userHandler.Login)
+	mux.HandleFunc("GET /unsubscribe", unsubscribeHandler.ConfirmUnsubscribe)
+	mux.HandleFunc("POST /unsubscribe", unsubscribeHandler.Unsubscribe)
 
 	// Protected routes
-	mux.HandleFunc("GET /users/profile", middleware.AuthMiddleware(userHandler.GetProfile))
-
-	mux.HandleFunc("POST /tasks", middleware.AuthMiddleware(taskHandler.CreateTask))
-	mux.HandleFunc("GET /tasks", middleware.AuthMiddleware(taskHandler.GetTask))
-	mux.HandleFunc("PUT /tasks", middleware.AuthMiddleware(taskHandler.UpdateTask))
-	mux.HandleFunc("DELETE /tasks", middleware.AuthMiddleware(taskHandler.DeleteTask))
-	mux.HandleFunc("POST /tasks/assign", middleware.AuthMiddleware(taskHandler.AssignToSprint))
-	mux.HandleFunc("POST /tasks/backlog", middleware.AuthMiddleware(taskHandler.MoveToBacklog))
-	mux.HandleFunc("GET /tasks/list", middleware.AuthMiddleware(taskHandler.ListTasks))
-	mux.HandleFunc("GET /tasks/backlog", middleware.AuthMiddleware(taskHandler.ListBacklog))
+	mux.HandleFunc("POST /organizations", middleware.AuthMiddleware(organizationHandler.CreateOrganization))
+	mux.HandleFunc("GET /organizations", middleware.AuthMiddleware(organizationHandler.ListOrganizations))
+	mux.HandleFunc("GET /organizations/current", middleware.AuthMiddleware(organizationHandler.GetCurrentOrganization))
+
+	// Everything else is served by the caller's workspace
+	mux.Handle("/", middleware.AuthMiddleware(tenants.ServeHTTP))
 
 	port := config.GetEnv("PORT", "8080")
 
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go-project-manager-backend/internal/domain/services"
+	"go-project-manager-backend/internal/infrastructure/repositories"
+	"go-project-manager-backend/internal/interfaces/http/handlers"
+	"go-project-manager-backend/internal/interfaces/http/middleware"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// workspace is the service graph of one organization. Its repositories reach
+// only the organization's own database and its users only the organization's
+// users, so nothing served through it can read or change another
+// organization's data.
+type workspace struct {
+	handler              http.Handler
+	taskService          *services.TaskService
+	burndownService      *services.BurndownService
+	recurringTaskService *services.RecurringTaskService
+	trashService         *services.TrashService
+	notificationService  *services.NotificationService
+	emailService         *services.EmailService
+}
+
+// emailSetup is what every workspace sends notification emails with.
+type emailSetup struct {
+	mailer        services.Mailer
+	links         *services.UnsubscribeLinks
+	defaultLocale string
+}
+
+// workspaceRepositories are the stores of one workspace.
+type workspaceRepositories struct {
+	tasks            services.TaskRepository
+	projects         services.ProjectRepository
+	filters          services.FilterRepository
+	comments         services.CommentRepository
+	counters         services.CounterRepository
+	labels           services.LabelRepository
+	sprints          services.SprintRepository
+	activities       services.ActivityRepository
+	snapshots        services.SnapshotRepository
+	worklogs         services.WorklogRepository
+	timers           services.TimerRepository
+	recurringTasks   services.RecurringTaskRepository
+	taskTemplates    services.TaskTemplateRepository
+	projectTemplates services.ProjectTemplateRepository
+	customFields     services.CustomFieldRepository
+	teams            services.TeamRepository
+	notifications    services.NotificationRepository
+}
+
+// mongoRepositories opens the repositories of a workspace in its database and
+// creates their indexes.
+func mongoRepositories(database *mongo.Database) (*workspaceRepositories, error) {
+	taskRepository := repositories.NewMongoTaskRepository(database)
+	projectRepository := repositories.NewMongoProjectRepository(database)
+	filterRepository := repositories.NewMongoFilterRepository(database)
+	commentRepository := repositories.NewMongoCommentRepository(database)
+	counterRepository := repositories.NewMongoCounterRepository(database)
+	labelRepository := repositories.NewMongoLabelRepository(database)
+	sprintRepository := repositories.NewMongoSprintRepository(database)
+	activityRepository := repositories.NewMongoActivityRepository(database)
+	snapshotRepository := repositories.NewMongoSnapshotRepository(database)
+	worklogRepository := repositories.NewMongoWorklogRepository(database)
+	timerRepository := repositories.NewMongoTimerRepository(database)
+	recurringTaskRepository := repositories.NewMongoRecurringTaskRepository(database)
+	taskTemplateRepository := repositories.NewMongoTaskTemplateRepository(database)
+	projectTemplateRepository := repositories.NewMongoProjectTemplateRepository(database)
+	customFieldRepository := repositories.NewMongoCustomFieldRepository(database)
+	teamRepository := repositories.NewMongoTeamRepository(database)
+	notificationRepository := repositories.NewMongoNotificationRepository(database)
+
+	if err := taskRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create task indexes: %w", err)
+	}
+	if err := projectRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create project indexes: %w", err)
+	}
+	if err := commentRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create comment indexes: %w", err)
+	}
+	if err := labelRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create label indexes: %w", err)
+	}
+	if err := sprintRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create sprint indexes: %w", err)
+	}
+	if err := activityRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create activity indexes: %w", err)
+	}
+	if err := snapshotRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot indexes: %w", err)
+	}
+	if err := worklogRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create worklog indexes: %w", err)
+	}
+	if err := recurringTaskRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create recurring task indexes: %w", err)
+	}
+	if err := customFieldRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create custom field indexes: %w", err)
+	}
+	if err := teamRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create team indexes: %w", err)
+	}
+	if err := notificationRepository.EnsureIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create notification indexes: %w", err)
+	}
+
+	return &workspaceRepositories{
+		tasks:            taskRepository,
+		projects:         projectRepository,
+		filters:          filterRepository,
+		comments:         commentRepository,
+		counters:         counterRepository,
+		labels:           labelRepository,
+		sprints:          sprintRepository,
+		activities:       activityRepository,
+		snapshots:        snapshotRepository,
+		worklogs:         worklogRepository,
+		timers:           timerRepository,
+		recurringTasks:   recurringTaskRepository,
+		taskTemplates:    taskTemplateRepository,
+		projectTemplates: projectTemplateRepository,
+		customFields:     customFieldRepository,
+		teams:            teamRepository,
+		notifications:    notificationRepository,
+	}, nil
+}
+
+func newWorkspace(repos *workspaceRepositories, users services.UserRepository, email emailSetup) *workspace {
+	taskRepository := repos.tasks
+	projectRepository := repos.projects
+	filterRepository := repos.filters
+	commentRepository := repos.comments
+	counterRepository := repos.counters
+	labelRepository := repos.labels
+	sprintRepository := repos.sprints
+	activityRepository := repos.activities
+	snapshotRepository := repos.snapshots
+	worklogRepository := repos.worklogs
+	timerRepository := repos.timers
+	recurringTaskRepository := repos.recurringTasks
+	taskTemplateRepository := repos.taskTemplates
+	projectTemplateRepository := repos.projectTemplates
+	customFieldRepository := repos.customFields
+	teamRepository := repos.teams
+	notificationRepository := repos.notifications
+
+	// Keep the tasks and sprints of archived projects read-only
+	guardedSprintRepository := services.GuardArchivedSprints(sprintRepository, projectRepository)
+
+	// Record the history of every task change, for burndowns and audits, and
+	// publish the ones users are notified of
+	events := services.NewEventQueue(1024, time.Second)
+	trackedTaskRepository := services.PublishTaskEvents(services.RecordTaskHistory(services.GuardArchivedTasks(taskRepository, projectRepository), activityRepository), events)
+
+	// Initialize services
+	userService := services.NewUserService(users)
+	taskService := services.NewTaskService(trackedTaskRepository, projectRepository, counterRepository, labelRepository, guardedSprintRepository, users, customFieldRepository, teamRepository)
+	projectService := services.NewProjectService(projectRepository, teamRepository, users)
+	labelService := services.NewLabelService(labelRepository, trackedTaskRepository, projectRepository)
+	filterService := services.NewFilterService(filterRepository, trackedTaskRepository, projectService, labelService)
+	commentService := services.NewCommentService(commentRepository, trackedTaskRepository, projectRepository, events)
+	searchService := services.NewSearchService(trackedTaskRepository, commentRepository, projectService)
+	dependencyService := services.NewDependencyService(trackedTaskRepository)
+	boardService := services.NewBoardService(trackedTaskRepository, projectRepository)
+	activityService := services.NewActivityService(activityRepository, trackedTaskRepository)
+	burndownService := services.NewBurndownService(guardedSprintRepository, trackedTaskRepository, activityRepository, snapshotRepository)
+	sprintService := services.NewSprintService(guardedSprintRepository, projectRepository, trackedTaskRepository, burndownService)
+	velocityService := services.NewVelocityService(guardedSprintRepository, trackedTaskRepository, projectRepository, teamRepository, burndownService)
+	flowService := services.NewFlowService(trackedTaskRepository, activityRepository)
+	workloadService := services.NewWorkloadService(users, projectRepository, guardedSprintRepository, trackedTaskRepository, teamRepository)
+	worklogService := services.NewWorklogService(worklogRepository, timerRepository, trackedTaskRepository, projectRepository)
+	recurringTaskService := services.NewRecurringTaskService(recurringTaskRepository, taskService, projectRepository)
+	customFieldService := services.NewCustomFieldService(customFieldRepository, trackedTaskRepository, users, projectRepository)
+	checklistService := services.NewChecklistService(trackedTaskRepository, projectRepository)
+	bulkService := services.NewBulkService(taskService, filterService, projectService)
+	trashService := services.NewTrashService(trackedTaskRepository, projectRepository, teamRepository)
+	teamService := services.NewTeamService(teamRepository, users, projectRepository, trackedTaskRepository)
+	templateService := services.NewTemplateService(taskTemplateRepository, projectTemplateRepository, projectService, taskService, labelService, boardService, sprintService)
+	notificationService := services.NewNotificationService(notificationRepository, users, trackedTaskRepository, commentRepository, projectRepository, teamRepository)
+	emailService := services.NewEmailService(notificationRepository, users, email.mailer, email.links, email.defaultLocale)
+
+	// Turn published events into notifications away from the requests that
+	// raised them, reporting the ones dropped while the queue was full
+	go func() {
+		var reported int64
+		for event := range events.Events() {
+			if dropped := events.Dropped(); dropped > reported {
+				log.Printf("Dropped %d notification events on a full queue", dropped-reported)
+				reported = dropped
+			}
+			if err := notificationService.Handle(event); err != nil {
+				log.Printf("Failed to notify %s of task %s: %v", event.Type, event.Task.ID, err)
+			}
+		}
+	}()
+
+	// Initialize handlers
+	userHandler := handlers.NewUserHandler(userService)
+	taskHandler := handlers.NewTaskHandler(taskService, customFieldService, projectService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	filterHandler := handlers.NewFilterHandler(filterService)
+	commentHandler := handlers.NewCommentHandler(commentService, taskService, projectService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	labelHandler := handlers.NewLabelHandler(labelService, projectService)
+	dependencyHandler := handlers.NewDependencyHandler(dependencyService, taskService, projectService)
+	boardHandler := handlers.NewBoardHandler(boardService, projectService)
+	sprintHandler := handlers.NewSprintHandler(sprintService, burndownService, taskService, projectService)
+	activityHandler := handlers.NewActivityHandler(activityService, projectService)
+	velocityHandler := handlers.NewVelocityHandler(velocityService, projectService)
+	flowHandler := handlers.NewFlowHandler(flowService, customFieldService, projectService)
+	workloadHandler := handlers.NewWorkloadHandler(workloadService, projectService)
+	worklogHandler := handlers.NewWorklogHandler(worklogService, taskService, projectService)
+	recurringTaskHandler := handlers.NewRecurringTaskHandler(recurringTaskService, projectService)
+	templateHandler := handlers.NewTemplateHandler(templateService, projectService)
+	checklistHandler := handlers.NewChecklistHandler(checklistService, taskService, projectService)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService, projectService)
+	trashHandler := handlers.NewTrashHandler(trashService, projectService)
+	bulkHandler := handlers.NewBulkHandler(bulkService, taskService)
+	teamHandler := handlers.NewTeamHandler(teamService, workloadService, velocityService, projectService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, emailService)
+
+	mux := http.NewServeMux()
+
+	// Protected routes
+	mux.HandleFunc("POST /users", middleware.AuthMiddleware(userHandler.CreateUser))
+	mux.HandleFunc("GET /users/profile", middleware.AuthMiddleware(userHandler.GetProfile))
+	mux.HandleFunc("PUT /users/{id}/capacity", middleware.AuthMiddleware(workloadHandler.SetCapacity))
+	mux.HandleFunc("GET /users/{id}/timesheet", middleware.AuthMiddleware(worklogHandler.GetUserTimesheet))
+
+	mux.HandleFunc("GET /notifications", middleware.AuthMiddleware(notificationHandler.ListNotifications))
+	mux.HandleFunc("GET /notifications/unread-count", middleware.AuthMiddleware(notificationHandler.UnreadCount))
+	mux.HandleFunc("POST /notifications/{id}/read", middleware.AuthMiddleware(notificationHandler.MarkRead))
+	mux.HandleFunc("POST /notifications/read-all", middleware.AuthMiddleware(notificationHandler.MarkAllRead))
+	mux.HandleFunc("GET /notifications/preferences", middleware.AuthMiddleware(notificationHandler.GetPreferences))
+	mux.HandleFunc("PUT /notifications/preferences", middleware.AuthMiddleware(notificationHandler.SetPreferences))
+	mux.HandleFunc("GET /notifications/email", middleware.AuthMiddleware(notificationHandler.GetEmailSettings))
+	mux.HandleFunc("PUT /notifications/email", middleware.AuthMiddleware(notificationHandler.SetEmailSettings))
+
+	mux.HandleFunc("POST /tasks", middleware.AuthMiddleware(taskHandler.CreateTask))
+	mux.HandleFunc("GET /tasks", middleware.AuthMiddleware(taskHandler.GetTask))
+	mux.HandleFunc("PUT /tasks", middleware.AuthMiddleware(taskHandler.UpdateTask))
+	mux.HandleFunc("DELETE /tasks", middleware.AuthMiddleware(taskHandler.DeleteTask))
+	mux.HandleFunc("POST /tasks/assign", middleware.AuthMiddleware(taskHandler.AssignToSprint))
+	mux.HandleFunc("POST /tasks/backlog", middleware.AuthMiddleware(taskHandler.MoveToBacklog))
+	mux.HandleFunc("POST /tasks/rank", middleware.AuthMiddleware(taskHandler.RankTask))
+	mux.HandleFunc("POST /tasks/move", middleware.AuthMiddleware(taskHandler.MoveToProject))
+	mux.HandleFunc("POST /tasks/bulk", middleware.AuthMiddleware(bulkHandler.ApplyBulk))
+	mux.HandleFunc("GET /tasks/list", middleware.AuthMiddleware(taskHandler.ListTasks))
+	mux.HandleFunc("GET /tasks/backlog", middleware.AuthMiddleware(taskHandler.ListBacklog))
+	mux.HandleFunc("POST /tasks/{id}/clone", middleware.AuthMiddleware(taskHandler.CloneTask))
+	mux.HandleFunc("GET /tasks/{id}/children", middleware.AuthMiddleware(taskHandler.ListChildren))
+	mux.HandleFunc("GET /tasks/{id}/history", middleware.AuthMiddleware(activityHandler.TaskHistory))
+	mux.HandleFunc("GET /tasks/search", middleware.AuthMiddleware(searchHandler.SearchTasks))
+
+	mux.HandleFunc("POST /tasks/{id}/worklogs", middleware.AuthMiddleware(worklogHandler.LogWork))
+	mux.HandleFunc("GET /tasks/{id}/worklogs", middleware.AuthMiddleware(worklogHandler.ListWorklogs))
+	mux.HandleFunc("PUT /worklogs/{id}", middleware.AuthMiddleware(worklogHandler.UpdateWorklog))
+	mux.HandleFunc("DELETE /worklogs/{id}", middleware.AuthMiddleware(worklogHandler.DeleteWorklog))
+	mux.HandleFunc("POST /tasks/{id}/timer/start", middleware.AuthMiddleware(worklogHandler.StartTimer))
+	mux.HandleFunc("POST /timer/stop", middleware.AuthMiddleware(worklogHandler.StopTimer))
+	mux.HandleFunc("GET /timer", middleware.AuthMiddleware(worklogHandler.GetTimer))
+	mux.HandleFunc("DELETE /timer", middleware.AuthMiddleware(worklogHandler.DiscardTimer))
+
+	mux.HandleFunc("POST /tasks/{id}/checklist", middleware.AuthMiddleware(checklistHandler.AddItem))
+	mux.HandleFunc("PUT /tasks/{id}/checklist/{itemId}", middleware.AuthMiddleware(checklistHandler.UpdateItem))
+	mux.HandleFunc("DELETE /tasks/{id}/checklist/{itemId}", middleware.AuthMiddleware(checklistHandler.DeleteItem))
+	mux.HandleFunc("POST /tasks/{id}/checklist/{itemId}/move", middleware.AuthMiddleware(checklistHandler.MoveItem))
+	mux.HandleFunc("POST /tasks/{id}/checklist/{itemId}/check", middleware.AuthMiddleware(checklistHandler.CheckItem))
+	mux.HandleFunc("POST /tasks/{id}/checklist/{itemId}/uncheck", middleware.AuthMiddleware(checklistHandler.UncheckItem))
+
+	mux.HandleFunc("POST /recurring-tasks", middleware.AuthMiddleware(recurringTaskHandler.CreateRecurringTask))
+	mux.HandleFunc("GET /recurring-tasks/{id}", middleware.AuthMiddleware(recurringTaskHandler.GetRecurringTask))
+	mux.HandleFunc("DELETE /recurring-tasks/{id}", middleware.AuthMiddleware(recurringTaskHandler.DeleteRecurringTask))
+	mux.HandleFunc("GET /recurring-tasks/{id}/preview", middleware.AuthMiddleware(recurringTaskHandler.PreviewOccurrences))
+	mux.HandleFunc("POST /recurring-tasks/{id}/pause", middleware.AuthMiddleware(recurringTaskHandler.PauseRecurringTask))
+	mux.HandleFunc("POST /recurring-tasks/{id}/resume", middleware.AuthMiddleware(recurringTaskHandler.ResumeRecurringTask))
+
+	mux.HandleFunc("POST /tasks/links", middleware.AuthMiddleware(dependencyHandler.LinkTasks))
+	mux.HandleFunc("DELETE /tasks/links", middleware.AuthMiddleware(dependencyHandler.UnlinkTasks))
+
+	mux.HandleFunc("POST /tasks/comments", middleware.AuthMiddleware(commentHandler.AddComment))
+	mux.HandleFunc("GET /tasks/comments", middleware.AuthMiddleware(commentHandler.ListComments))
+	mux.HandleFunc("PUT /tasks/comments", middleware.AuthMiddleware(commentHandler.UpdateComment))
+	mux.HandleFunc("DELETE /tasks/comments", middleware.AuthMiddleware(commentHandler.DeleteComment))
+
+	mux.HandleFunc("POST /projects", middleware.AuthMiddleware(projectHandler.CreateProject))
+	mux.HandleFunc("GET /projects", middleware.AuthMiddleware(projectHandler.GetProject))
+	mux.HandleFunc("GET /projects/list", middleware.AuthMiddleware(projectHandler.ListProjects))
+	mux.HandleFunc("POST /projects/members", middleware.AuthMiddleware(projectHandler.AddMember))
+	mux.HandleFunc("DELETE /projects/members", middleware.AuthMiddleware(projectHandler.RemoveMember))
+	mux.HandleFunc("POST /projects/{id}/teams", middleware.AuthMiddleware(projectHandler.AddTeam))
+	mux.HandleFunc("DELETE /projects/{id}/teams/{teamId}", middleware.AuthMiddleware(projectHandler.RemoveTeam))
+	mux.HandleFunc("POST /projects/{id}/archive", middleware.AuthMiddleware(projectHandler.ArchiveProject))
+	mux.HandleFunc("POST /projects/{id}/unarchive", middleware.AuthMiddleware(projectHandler.UnarchiveProject))
+	mux.HandleFunc("DELETE /projects/{id}", middleware.AuthMiddleware(trashHandler.DeleteProject))
+	mux.HandleFunc("GET /projects/{id}/trash", middleware.AuthMiddleware(trashHandler.ListTaskTrash))
+	mux.HandleFunc("GET /trash/projects", middleware.AuthMiddleware(trashHandler.ListProjectTrash))
+	mux.HandleFunc("POST /trash/tasks/{id}/restore", middleware.AuthMiddleware(trashHandler.RestoreTask))
+	mux.HandleFunc("POST /trash/projects/{id}/restore", middleware.AuthMiddleware(trashHandler.RestoreProject))
+	mux.HandleFunc("GET /projects/{id}/dependencies", middleware.AuthMiddleware(dependencyHandler.GetGraph))
+	mux.HandleFunc("GET /projects/{id}/board", middleware.AuthMiddleware(boardHandler.GetBoard))
+	mux.HandleFunc("PUT /projects/{id}/board", middleware.AuthMiddleware(boardHandler.ConfigureBoard))
+	mux.HandleFunc("POST /projects/{id}/board/move", middleware.AuthMiddleware(boardHandler.MoveCard))
+	mux.HandleFunc("GET /projects/{id}/velocity", middleware.AuthMiddleware(velocityHandler.GetVelocity))
+	mux.HandleFunc("GET /projects/{id}/forecast", middleware.AuthMiddleware(velocityHandler.GetForecast))
+	mux.HandleFunc("GET /projects/{id}/flow/tasks", middleware.AuthMiddleware(flowHandler.GetTaskFlows))
+	mux.HandleFunc("GET /projects/{id}/flow/throughput", middleware.AuthMiddleware(flowHandler.GetThroughput))
+	mux.HandleFunc("GET /projects/{id}/flow/cumulative", middleware.AuthMiddleware(flowHandler.GetCumulativeFlow))
+	mux.HandleFunc("GET /projects/{id}/workload", middleware.AuthMiddleware(workloadHandler.GetWorkload))
+	mux.HandleFunc("PUT /projects/{id}/capacity-policy", middleware.AuthMiddleware(workloadHandler.SetCapacityPolicy))
+	mux.HandleFunc("GET /projects/{id}/timesheet", middleware.AuthMiddleware(worklogHandler.GetProjectTimesheet))
+	mux.HandleFunc("PUT /projects/{id}/timesheet-lock", middleware.AuthMiddleware(worklogHandler.LockTimesheets))
+	mux.HandleFunc("POST /projects/{id}/custom-fields", middleware.AuthMiddleware(customFieldHandler.CreateField))
+	mux.HandleFunc("GET /projects/{id}/custom-fields", middleware.AuthMiddleware(customFieldHandler.ListFields))
+	mux.HandleFunc("PUT /projects/{id}/checklist-policy", middleware.AuthMiddleware(checklistHandler.SetChecklistPolicy))
+	mux.HandleFunc("GET /projects/{id}/recurring-tasks", middleware.AuthMiddleware(recurringTaskHandler.ListRecurringTasks))
+
+	mux.HandleFunc("POST /teams", middleware.AuthMiddleware(teamHandler.CreateTeam))
+	mux.HandleFunc("GET /teams", middleware.AuthMiddleware(teamHandler.ListTeams))
+	mux.HandleFunc("GET /teams/{id}", middleware.AuthMiddleware(teamHandler.GetTeam))
+	mux.HandleFunc("PUT /teams/{id}", middleware.AuthMiddleware(teamHandler.UpdateTeam))
+	mux.HandleFunc("DELETE /teams/{id}", middleware.AuthMiddleware(teamHandler.DeleteTeam))
+	mux.HandleFunc("POST /teams/{id}/members", middleware.AuthMiddleware(teamHandler.AddMember))
+	mux.HandleFunc("DELETE /teams/{id}/members/{userId}", middleware.AuthMiddleware(teamHandler.RemoveMember))
+	mux.HandleFunc("GET /teams/{id}/workload", middleware.AuthMiddleware(teamHandler.GetWorkload))
+	mux.HandleFunc("GET /teams/{id}/velocity", middleware.AuthMiddleware(teamHandler.GetVelocity))
+
+	mux.HandleFunc("PUT /custom-fields/{id}", middleware.AuthMiddleware(customFieldHandler.UpdateField))
+	mux.HandleFunc("DELETE /custom-fields/{id}", middleware.AuthMiddleware(customFieldHandler.DeleteField))
+
+	mux.HandleFunc("POST /sprints", middleware.AuthMiddleware(sprintHandler.CreateSprint))
+	mux.HandleFunc("GET /sprints/list", middleware.AuthMiddleware(sprintHandler.ListSprints))
+	mux.HandleFunc("GET /sprints/{id}", middleware.AuthMiddleware(sprintHandler.GetSprint))
+	mux.HandleFunc("DELETE /sprints/{id}", middleware.AuthMiddleware(sprintHandler.DeleteSprint))
+	mux.HandleFunc("POST /sprints/{id}/start", middleware.AuthMiddleware(sprintHandler.StartSprint))
+	mux.HandleFunc("POST /sprints/{id}/close", middleware.AuthMiddleware(sprintHandler.CloseSprint))
+	mux.HandleFunc("GET /sprints/{id}/tasks", middleware.AuthMiddleware(sprintHandler.ListTasks))
+	mux.HandleFunc("GET /sprints/{id}/burndown", middleware.AuthMiddleware(sprintHandler.GetBurndown))
+	mux.HandleFunc("POST /sprints/{id}/burndown/rebuild", middleware.AuthMiddleware(sprintHandler.RebuildBurndown))
+
+	mux.HandleFunc("POST /task-templates", middleware.AuthMiddleware(templateHandler.CreateTaskTemplate))
+	mux.HandleFunc("GET /task-templates/list", middleware.AuthMiddleware(templateHandler.ListTaskTemplates))
+	mux.HandleFunc("GET /task-templates/{id}", middleware.AuthMiddleware(templateHandler.GetTaskTemplate))
+	mux.HandleFunc("PUT /task-templates/{id}", middleware.AuthMiddleware(templateHandler.UpdateTaskTemplate))
+	mux.HandleFunc("DELETE /task-templates/{id}", middleware.AuthMiddleware(templateHandler.DeleteTaskTemplate))
+	mux.HandleFunc("POST /task-templates/{id}/tasks", middleware.AuthMiddleware(templateHandler.CreateTaskFromTemplate))
+	mux.HandleFunc("POST /project-templates", middleware.AuthMiddleware(templateHandler.CreateProjectTemplate))
+	mux.HandleFunc("GET /project-templates/list", middleware.AuthMiddleware(templateHandler.ListProjectTemplates))
+	mux.HandleFunc("GET /project-templates/{id}", middleware.AuthMiddleware(templateHandler.GetProjectTemplate))
+	mux.HandleFunc("PUT /project-templates/{id}", middleware.AuthMiddleware(templateHandler.UpdateProjectTemplate))
+	mux.HandleFunc("DELETE /project-templates/{id}", middleware.AuthMiddleware(templateHandler.DeleteProjectTemplate))
+	mux.HandleFunc("POST /project-templates/{id}/projects", middleware.AuthMiddleware(templateHandler.CreateProjectFromTemplate))
+
+	mux.HandleFunc("POST /labels", middleware.AuthMiddleware(labelHandler.CreateLabel))
+	mux.HandleFunc("PUT /labels", middleware.AuthMiddleware(labelHandler.UpdateLabel))
+	mux.HandleFunc("DELETE /labels", middleware.AuthMiddleware(labelHandler.DeleteLabel))
+	mux.HandleFunc("GET /labels/list", middleware.AuthMiddleware(labelHandler.ListLabels))
+
+	mux.HandleFunc("GET /search", middleware.AuthMiddleware(filterHandler.Search))
+	mux.HandleFunc("POST /filters", middleware.AuthMiddleware(filterHandler.CreateFilter))
+	mux.HandleFunc("PUT /filters", middleware.AuthMiddleware(filterHandler.UpdateFilter))
+	mux.HandleFunc("DELETE /filters", middleware.AuthMiddleware(filterHandler.DeleteFilter))
+	mux.HandleFunc("GET /filters/list", middleware.AuthMiddleware(filterHandler.ListFilters))
+	mux.HandleFunc("GET /filters/run", middleware.AuthMiddleware(filterHandler.RunFilter))
+
+	return &workspace{
+		handler:              mux,
+		taskService:          taskService,
+		burndownService:      burndownService,
+		recurringTaskService: recurringTaskService,
+		trashService:         trashService,
+		notificationService:  notificationService,
+		emailService:         emailService,
+	}
+}
+
+var errUnknownTenant = errors.New("unknown organization")
+
+// workspaces builds the workspace of each organization on first use and keeps
+// it for the life of the process.
+type workspaces struct {
+	open          func(database string) (*workspaceRepositories, error)
+	database      string
+	users         services.UserRepository
+	organizations services.OrganizationRepository
+	email         emailSetup
+	mu            sync.RWMutex
+	builds        map[string]*workspaceBuild
+}
+
+// workspaceBuild is a workspace being built or already built; done closes
+// once workspace or err is set.
+type workspaceBuild struct {
+	done      chan struct{}
+	workspace *workspace
+	err       error
+}
+
+// newWorkspaces creates the registry. open opens the repositories in the
+// named database; the default workspace uses database itself.
+func newWorkspaces(open func(database string) (*workspaceRepositories, error), database string, users services.UserRepository, organizations services.OrganizationRepository, email emailSetup) *workspaces {
+	return &workspaces{
+		open:          open,
+		database:      database,
+		users:         users,
+		organizations: organizations,
+		email:         email,
+		builds:        make(map[string]*workspaceBuild),
+	}
+}
+
+// get returns the workspace of a tenant, building it on first use. Each tenant
+// is built once, outside the lock, so a slow build only holds up requests to
+// that tenant; failed builds are retried on the next call.
+func (ws *workspaces) get(tenantID string) (*workspace, error) {
+	ws.mu.RLock()
+	b, ok := ws.builds[tenantID]
+	ws.mu.RUnlock()
+
+	if !ok {
+		ws.mu.Lock()
+		b, ok = ws.builds[tenantID]
+		if !ok {
+			b = &workspaceBuild{done: make(chan struct{})}
+			ws.builds[tenantID] = b
+		}
+		ws.mu.Unlock()
+
+		if !ok {
+			b.workspace, b.err = ws.build(tenantID)
+			if b.err != nil {
+				ws.mu.Lock()
+				delete(ws.builds, tenantID)
+				ws.mu.Unlock()
+			}
+			close(b.done)
+		}
+	}
+
+	<-b.done
+	return b.workspace, b.err
+}
+
+// build opens the workspace of a tenant. The default workspace, "", keeps
+// using the configured database, with the data from before organizations.
+func (ws *workspaces) build(tenantID string) (*workspace, error) {
+	name := ws.database
+	if tenantID != "" {
+		if _, err := ws.organizations.GetByID(tenantID); err != nil {
+			return nil, errUnknownTenant
+		}
+		name = tenantDatabase(ws.database, tenantID)
+	}
+	repos, err := ws.open(name)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkspace(repos, services.ScopeUsers(ws.users, tenantID), ws.email), nil
+}
+
+// ServeHTTP routes an authenticated request to the workspace of the tenant in
+// its token.
+func (ws *workspaces) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	workspace, err := ws.get(middleware.GetTenantIDFromContext(req.Context()))
+	if errors.Is(err, errUnknownTenant) {
+		http.Error(w, "Unknown organization", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open workspace: %v", err)
+		http.Error(w, "Workspace unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	workspace.handler.ServeHTTP(w, req)
+}
+
+// each runs a background job in every workspace, logging the ones it fails
+// in.
+func (ws *workspaces) each(job string, fn func(w *workspace) error) {
+	organizations, err := ws.organizations.List()
+	if err != nil {
+		log.Printf("%s failed: %v", job, err)
+		return
+	}
+	tenantIDs := []string{""}
+	for _, organization := range organizations {
+		tenantIDs = append(tenantIDs, organization.ID)
+	}
+
+	for _, tenantID := range tenantIDs {
+		w, err := ws.get(tenantID)
+		if err == nil {
+			err = fn(w)
+		}
+		if err != nil {
+			log.Printf("%s failed in workspace %q: %v", job, tenantID, err)
+		}
+	}
+}
+
+// tenantDatabase names the database of an organization after the configured
+// one, keeping it within MongoDB's 64 byte limit for short base names.
+func tenantDatabase(base, tenantID string) string {
+	return base + "_" + strings.ReplaceAll(tenantID, "-", "")
+}
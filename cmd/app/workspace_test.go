@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"go-project-manager-backend/internal/infrastructure/database"
+	"go-project-manager-backend/internal/infrastructure/mail"
+	"go-project-manager-backend/internal/infrastructure/repositories"
+	"go-project-manager-backend/internal/interfaces/http/middleware"
+	"go-project-manager-backend/pkg/jwt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTenantIsolationInMemory(t *testing.T) {
+	open := func(string) (*workspaceRepositories, error) {
+		return inMemoryRepositories(), nil
+	}
+	testTenantIsolation(t, open, "project_manager", repositories.NewInMemoryUserRepository(), repositories.NewInMemoryOrganizationRepository())
+}
+
+// TestTenantIsolationMongo runs against the MongoDB at MONGODB_TEST_URI, in
+// databases it drops afterwards.
+func TestTenantIsolationMongo(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+	name := fmt.Sprintf("project_manager_test_%d", time.Now().UnixNano())
+	db, err := database.NewMongoDB(uri, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	organizations := repositories.NewMongoOrganizationRepository(db.Database)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if list, err := organizations.List(); err == nil {
+			for _, organization := range list {
+				db.Client.Database(tenantDatabase(name, organization.ID)).Drop(ctx)
+			}
+		}
+		db.Database.Drop(ctx)
+		db.Close()
+	})
+
+	open := func(name string) (*workspaceRepositories, error) {
+		return mongoRepositories(db.Client.Database(name))
+	}
+	testTenantIsolation(t, open, name, repositories.NewMongoUserRepository(db.Database), organizations)
+}
+
+// testTenantIsolation creates a project, a task and a user in one
+// organization and checks that another organization can neither see nor
+// change them.
+func testTenantIsolation(t *testing.T, open func(string) (*workspaceRepositories, error), name string, users services.UserRepository, organizations services.OrganizationRepository) {
+	organizationService := services.NewOrganizationService(organizations, users)
+	acme, acmeAdmin, err := organizationService.CreateOrganization("Acme", "Ann", "ann@acme.test", "secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	globex, globexAdmin, err := organizationService.CreateOrganization("Globex", "Gus", "gus@globex.test", "secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenants := newWorkspaces(open, name, users, organizations, emailSetup{
+		mailer:        mail.NewLogMailer(),
+		links:         services.NewUnsubscribeLinks("http://localhost:8080", []byte("test-secret")),
+		defaultLocale: "en",
+	})
+	handler := middleware.AuthMiddleware(tenants.ServeHTTP)
+	a := newTenantClient(t, handler, acmeAdmin, acme.ID)
+	b := newTenantClient(t, handler, globexAdmin, globex.ID)
+
+	var developer models.User
+	a.must(http.MethodPost, "/users", map[string]string{"name": "Dev", "email": "dev@acme.test", "password": "secret123", "role": "developer"}, &developer)
+	var project models.Project
+	a.must(http.MethodPost, "/projects", map[string]string{"key": "ACME", "name": "Rockets"}, &project)
+	var task models.Task
+	a.must(http.MethodPost, "/tasks", map[string]string{"title": "Launch", "project_id": project.ID}, &task)
+
+	// Nothing of Acme's is returned to Globex
+	b.refused(http.MethodGet, "/projects?id="+project.ID, nil)
+	b.refused(http.MethodGet, "/tasks?id="+task.ID, nil)
+	for _, target := range []string{
+		"/projects/list",
+		"/tasks/list?project_id=" + project.ID,
+		"/tasks/search?q=Launch",
+		"/tasks/search?q=Launch&project_id=" + project.ID,
+	} {
+		b.empty(target)
+	}
+
+	// Nor can Globex change it
+	b.refused(http.MethodPut, "/tasks?id="+task.ID, map[string]string{"title": "Hijacked"})
+	b.refused(http.MethodDelete, "/tasks?id="+task.ID, nil)
+	b.refused(http.MethodPost, "/projects/members?project_id="+project.ID+"&user_id="+globexAdmin.ID, nil)
+
+	// Globex keeps its own keys, and cannot bring in Acme's users
+	var globexProject models.Project
+	b.must(http.MethodPost, "/projects", map[string]string{"key": "ACME", "name": "Globex rockets"}, &globexProject)
+	b.refused(http.MethodPost, "/projects/members?project_id="+globexProject.ID+"&user_id="+developer.ID, nil)
+	globexUsers := services.ScopeUsers(users, globex.ID)
+	if _, err := globexUsers.GetByID(developer.ID); err == nil {
+		t.Error("Globex reads an Acme user")
+	}
+	if list, err := globexUsers.List(); err != nil || len(list) != 1 || list[0].ID != globexAdmin.ID {
+		t.Errorf("Globex lists users %v (%v), want only its admin", list, err)
+	}
+
+	// Acme's data is untouched
+	var got models.Task
+	a.must(http.MethodGet, "/tasks?id="+task.ID, nil, &got)
+	if got.Title != "Launch" {
+		t.Errorf("task title is %q, want %q", got.Title, "Launch")
+	}
+	a.must(http.MethodGet, "/projects?id="+project.ID, nil, &project)
+	if slices.Contains(project.MemberIDs, globexAdmin.ID) {
+		t.Errorf("project has Globex's admin among members %v", project.MemberIDs)
+	}
+}
+
+// TestProjectMembership checks that a user outside a project of their own
+// organization can neither see nor change its tasks or their comments.
+func TestProjectMembership(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	organizations := repositories.NewInMemoryOrganizationRepository()
+	acme, admin, err := services.NewOrganizationService(organizations, users).CreateOrganization("Acme", "Ann", "ann@acme.test", "secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := func(string) (*workspaceRepositories, error) {
+		return inMemoryRepositories(), nil
+	}
+	tenants := newWorkspaces(open, "project_manager", users, organizations, emailSetup{
+		mailer:        mail.NewLogMailer(),
+		links:         services.NewUnsubscribeLinks("http://localhost:8080", []byte("test-secret")),
+		defaultLocale: "en",
+	})
+	handler := middleware.AuthMiddleware(tenants.ServeHTTP)
+	a := newTenantClient(t, handler, admin, acme.ID)
+
+	var developer models.User
+	a.must(http.MethodPost, "/users", map[string]string{"name": "Dev", "email": "dev@acme.test", "password": "secret123", "role": "developer"}, &developer)
+	var project models.Project
+	a.must(http.MethodPost, "/projects", map[string]string{"key": "ACME", "name": "Rockets"}, &project)
+	var task models.Task
+	a.must(http.MethodPost, "/tasks", map[string]string{"title": "Launch", "project_id": project.ID}, &task)
+	d := newTenantClient(t, handler, &developer, acme.ID)
+
+	d.refused(http.MethodPost, "/tasks", map[string]string{"title": "Sneak", "project_id": project.ID})
+	for _, target := range []string{
+		"/tasks?id=" + task.ID,
+		"/tasks/" + task.ID + "/children",
+		"/tasks/list?project_id=" + project.ID,
+		"/tasks/list?format=csv&project_id=" + project.ID,
+		"/tasks/backlog?project_id=" + project.ID,
+	} {
+		d.refused(http.MethodGet, target, nil)
+	}
+	d.refused(http.MethodPut, "/tasks?id="+task.ID, map[string]string{"title": "Hijacked"})
+	d.refused(http.MethodPost, "/tasks/backlog?task_id="+task.ID, nil)
+	d.refused(http.MethodDelete, "/tasks?id="+task.ID, nil)
+
+	var comment models.Comment
+	a.must(http.MethodPost, "/tasks/comments?task_id="+task.ID, map[string]string{"body": "Countdown"}, &comment)
+	d.refused(http.MethodGet, "/tasks/comments?task_id="+task.ID, nil)
+	d.refused(http.MethodPost, "/tasks/comments?task_id="+task.ID, map[string]string{"body": "Sneak"})
+	d.refused(http.MethodPut, "/tasks/comments?id="+comment.ID, map[string]string{"body": "Hijacked"})
+	d.refused(http.MethodDelete, "/tasks/comments?id="+comment.ID, nil)
+
+	// Linking needs both projects, not only the developer's own
+	var own models.Project
+	a.must(http.MethodPost, "/projects", map[string]string{"key": "OWN", "name": "Own"}, &own)
+	a.must(http.MethodPost, "/projects/members?project_id="+own.ID+"&user_id="+developer.ID, nil, nil)
+	var ownTask models.Task
+	d.must(http.MethodPost, "/tasks", map[string]string{"title": "Mine", "project_id": own.ID}, &ownTask)
+	d.refused(http.MethodPost, "/tasks/links?type=blocks&task_id="+ownTask.ID+"&target_id="+task.ID, nil)
+	d.refused(http.MethodDelete, "/tasks/links?task_id="+ownTask.ID+"&target_id="+task.ID, nil)
+
+	a.must(http.MethodPost, "/projects/members?project_id="+project.ID+"&user_id="+developer.ID, nil, nil)
+	var got models.Task
+	d.must(http.MethodGet, "/tasks?id="+task.ID, nil, &got)
+	if got.Title != "Launch" {
+		t.Errorf("task title is %q, want %q", got.Title, "Launch")
+	}
+}
+
+// tenantClient sends requests to the workspaces as one user.
+type tenantClient struct {
+	t       *testing.T
+	handler http.Handler
+	token   string
+}
+
+func newTenantClient(t *testing.T, handler http.Handler, user *models.User, tenantID string) *tenantClient {
+	token, err := jwt.GenerateToken(user.ID, user.Email, string(user.Role), tenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tenantClient{t: t, handler: handler, token: token}
+}
+
+func (c *tenantClient) do(method, target string, body any) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &payload)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// must sends a request that has to succeed, decoding its response into out.
+func (c *tenantClient) must(method, target string, body, out any) {
+	c.t.Helper()
+	rec := c.do(method, target, body)
+	if rec.Code >= 300 {
+		c.t.Fatalf("%s %s: %d %s", method, target, rec.Code, rec.Body)
+	}
+	if out != nil {
+		if err := json.NewDecoder(rec.Body).Decode(out); err != nil {
+			c.t.Fatalf("%s %s: %v", method, target, err)
+		}
+	}
+}
+
+// refused sends a request that has to fail.
+func (c *tenantClient) refused(method, target string, body any) {
+	c.t.Helper()
+	if rec := c.do(method, target, body); rec.Code < 300 {
+		c.t.Errorf("%s %s was not refused: %d %s", method, target, rec.Code, rec.Body)
+	}
+}
+
+// empty sends a listing request that must not return anything, either by
+// failing or with an empty list.
+func (c *tenantClient) empty(target string) {
+	c.t.Helper()
+	rec := c.do(http.MethodGet, target, nil)
+	if rec.Code >= 300 {
+		return
+	}
+	var items []json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil || len(items) != 0 {
+		c.t.Errorf("GET %s returned %d items across organizations (%v)", target, len(items), err)
+	}
+}
+
+func inMemoryRepositories() *workspaceRepositories {
+	tasks := repositories.NewInMemoryTaskRepository()
+	return &workspaceRepositories{
+		tasks:            tasks,
+		projects:         repositories.NewInMemoryProjectRepository(),
+		filters:          repositories.NewInMemoryFilterRepository(),
+		comments:         repositories.NewInMemoryCommentRepository(tasks),
+		counters:         repositories.NewInMemoryCounterRepository(),
+		labels:           repositories.NewInMemoryLabelRepository(),
+		sprints:          repositories.NewInMemorySprintRepository(),
+		activities:       repositories.NewInMemoryActivityRepository(),
+		snapshots:        repositories.NewInMemorySnapshotRepository(),
+		worklogs:         repositories.NewInMemoryWorklogRepository(),
+		timers:           repositories.NewInMemoryTimerRepository(),
+		recurringTasks:   repositories.NewInMemoryRecurringTaskRepository(),
+		taskTemplates:    repositories.NewInMemoryTaskTemplateRepository(),
+		projectTemplates: repositories.NewInMemoryProjectTemplateRepository(),
+		customFields:     repositories.NewInMemoryCustomFieldRepository(),
+		teams:            repositories.NewInMemoryTeamRepository(),
+		notifications:    repositories.NewInMemoryNotificationRepository(),
+	}
+}
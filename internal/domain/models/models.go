@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Role string
 
@@ -10,12 +13,71 @@ const (
 	ProjectManager Role = "project_manager"
 )
 
+// Organization is a tenant of the deployment. It owns its users, which carry
+// its ID, and its projects, which live in a database of its own. Users
+// without an organization belong to the default workspace.
+type Organization struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	Name      string    `json:"name" bson:"name"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// User is an account. NotificationPreferences holds the events the user
+// turned notifications on or off for; events missing from it are on.
+// EmailSettings, when set, says how the notifications are emailed.
 type User struct {
-	ID             string `json:"id" bson:"_id,omitempty"`
-	Name           string `json:"name" bson:"name"`
-	Email          string `json:"email" bson:"email"`
-	PasswordHashed string `json:"-" bson:"password_hashed"`
-	Role           Role   `json:"role" bson:"role"`
+	ID                      string                     `json:"id" bson:"_id,omitempty"`
+	OrganizationID          string                     `json:"organization_id,omitempty" bson:"organization_id,omitempty"`
+	Name                    string                     `json:"name" bson:"name"`
+	Email                   string                     `json:"email" bson:"email"`
+	PasswordHashed          string                     `json:"-" bson:"password_hashed"`
+	Role                    Role                       `json:"role" bson:"role"`
+	Capacity                *UserCapacity              `json:"capacity,omitempty" bson:"capacity,omitempty"`
+	NotificationPreferences map[NotificationEvent]bool `json:"notification_preferences,omitempty" bson:"notification_preferences,omitempty"`
+	EmailSettings           *EmailSettings             `json:"email_settings,omitempty" bson:"email_settings,omitempty"`
+}
+
+type EmailDelivery string
+
+const (
+	EmailOff     EmailDelivery = "off"
+	EmailInstant EmailDelivery = "instant"
+	EmailDaily   EmailDelivery = "daily"
+	EmailWeekly  EmailDelivery = "weekly"
+)
+
+// EmailSettings is how a user gets notifications by email: each as it comes,
+// or in a digest at DigestHour every day, or on DigestDay every week, in the
+// user's Timezone. Nothing is sent between QuietStart and QuietEnd ("15:04");
+// what comes due then waits for them to end. Only the notifications since
+// Since that are still unread are sent, in the user's Locale.
+type EmailSettings struct {
+	Delivery   EmailDelivery `json:"delivery" bson:"delivery"`
+	Locale     string        `json:"locale,omitempty" bson:"locale,omitempty"`
+	Timezone   string        `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	DigestHour int           `json:"digest_hour" bson:"digest_hour"`
+	DigestDay  time.Weekday  `json:"digest_day" bson:"digest_day"`
+	QuietStart string        `json:"quiet_start,omitempty" bson:"quiet_start,omitempty"`
+	QuietEnd   string        `json:"quiet_end,omitempty" bson:"quiet_end,omitempty"`
+	Since      time.Time     `json:"since" bson:"since"`
+	LastSentAt *time.Time    `json:"last_sent_at,omitempty" bson:"last_sent_at,omitempty"`
+}
+
+// UserCapacity is how much work a user can take on in a full sprint, in
+// story points and in hours, and the periods they are away. A zero amount
+// means it is not planned in that unit.
+type UserCapacity struct {
+	PointsPerSprint int       `json:"points_per_sprint" bson:"points_per_sprint"`
+	HoursPerSprint  float64   `json:"hours_per_sprint" bson:"hours_per_sprint"`
+	TimeOff         []TimeOff `json:"time_off,omitempty" bson:"time_off,omitempty"`
+}
+
+// TimeOff is a period a user is away, from the day of Start to the day of
+// End, both included.
+type TimeOff struct {
+	Start  time.Time `json:"start" bson:"start"`
+	End    time.Time `json:"end" bson:"end"`
+	Reason string    `json:"reason,omitempty" bson:"reason,omitempty"`
 }
 
 type TaskStatus string
@@ -27,25 +89,380 @@ const (
 	Done                   TaskStatus = "done"
 )
 
+func (s TaskStatus) Valid() bool {
+	switch s {
+	case ToDo, InProgress, ReadyForImplementation, Done:
+		return true
+	}
+	return false
+}
+
+type TaskType string
+
+const (
+	TypeEpic    TaskType = "epic"
+	TypeStory   TaskType = "story"
+	TypeTask    TaskType = "task"
+	TypeSubtask TaskType = "subtask"
+)
+
+// LinkType is the kind of a link from one task to another. Every link is
+// stored on both tasks, the other end carrying the inverse type.
+type LinkType string
+
+const (
+	LinkBlocks       LinkType = "blocks"
+	LinkBlockedBy    LinkType = "blocked_by"
+	LinkDuplicates   LinkType = "duplicates"
+	LinkDuplicatedBy LinkType = "duplicated_by"
+	LinkRelatesTo    LinkType = "relates_to"
+)
+
+var inverseLinks = map[LinkType]LinkType{
+	LinkBlocks:       LinkBlockedBy,
+	LinkBlockedBy:    LinkBlocks,
+	LinkDuplicates:   LinkDuplicatedBy,
+	LinkDuplicatedBy: LinkDuplicates,
+	LinkRelatesTo:    LinkRelatesTo,
+}
+
+func (t LinkType) Valid() bool {
+	_, ok := inverseLinks[t]
+	return ok
+}
+
+// Inverse returns the type of the same link seen from the other task.
+func (t LinkType) Inverse() LinkType {
+	return inverseLinks[t]
+}
+
+type TaskLink struct {
+	Type   LinkType `json:"type" bson:"type"`
+	TaskID string   `json:"task_id" bson:"task_id"`
+}
+
+// TaskProgress rolls up a parent task's direct children. It is computed when
+// the task is read and never stored.
+type TaskProgress struct {
+	Children    int     `json:"children"`
+	Done        int     `json:"done"`
+	DoneRatio   float64 `json:"done_ratio"`
+	StoryPoints int     `json:"story_points"`
+	DonePoints  int     `json:"done_points"`
+}
+
+// ChecklistItem is a step of a task's checklist. Required items must be
+// checked before the task is done, in projects that ask for it.
+type ChecklistItem struct {
+	ID        string     `json:"id" bson:"id"`
+	Text      string     `json:"text" bson:"text"`
+	Required  bool       `json:"required,omitempty" bson:"required,omitempty"`
+	Checked   bool       `json:"checked" bson:"checked"`
+	CheckedBy string     `json:"checked_by,omitempty" bson:"checked_by,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty" bson:"checked_at,omitempty"`
+}
+
+// ChecklistProgress counts the checked items of a task's checklist. It is
+// stored with the task and recomputed whenever the checklist changes, so it
+// shows in every task list.
+type ChecklistProgress struct {
+	Items             int     `json:"items" bson:"items"`
+	Checked           int     `json:"checked" bson:"checked"`
+	RequiredUnchecked int     `json:"required_unchecked" bson:"required_unchecked"`
+	DoneRatio         float64 `json:"done_ratio" bson:"done_ratio"`
+}
+
+// TaskPriority is stored as a number so that sorting by priority follows its
+// rank, and marshalled to JSON by name.
+type TaskPriority int
+
+const (
+	PriorityNone TaskPriority = iota
+	PriorityLowest
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityHighest
+)
+
+var priorityNames = map[TaskPriority]string{
+	PriorityNone:    "",
+	PriorityLowest:  "lowest",
+	PriorityLow:     "low",
+	PriorityMedium:  "medium",
+	PriorityHigh:    "high",
+	PriorityHighest: "highest",
+}
+
+func ParsePriority(name string) (TaskPriority, error) {
+	for priority, n := range priorityNames {
+		if n == name {
+			return priority, nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q", name)
+}
+
+func (p TaskPriority) Valid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+func (p TaskPriority) String() string {
+	return priorityNames[p]
+}
+
+func (p TaskPriority) MarshalText() ([]byte, error) {
+	name, ok := priorityNames[p]
+	if !ok {
+		return nil, fmt.Errorf("invalid priority %d", int(p))
+	}
+	return []byte(name), nil
+}
+
+func (p *TaskPriority) UnmarshalText(text []byte) error {
+	priority, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = priority
+	return nil
+}
+
+// Task is a unit of work in a project. Deleted tasks stay in the trash, with
+// DeletedAt set, until restored or purged; TrashedWith names the task or
+// project whose deletion took the task along, and whose restore brings it
+// back. ClonedFrom is the task a copy was made from.
 type Task struct {
-	ID          string     `json:"id" bson:"_id,omitempty"`
-	Title       string     `json:"title" bson:"title"`
-	Description string     `json:"description" bson:"description"`
-	Status      TaskStatus `json:"status" bson:"status"`
-	AssigneeID  string     `json:"assignee_id" bson:"assignee_id"`
-	ProjectID   string     `json:"project_id" bson:"project_id"`
-	SprintID    *string    `json:"sprint_id,omitempty" bson:"sprint_id,omitempty"`
-	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	ID                       string                      `json:"id" bson:"_id,omitempty"`
+	Key                      string                      `json:"key,omitempty" bson:"key,omitempty"`
+	PreviousKeys             []string                    `json:"previous_keys,omitempty" bson:"previous_keys,omitempty"`
+	Type                     TaskType                    `json:"type" bson:"type"`
+	ParentID                 string                      `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Title                    string                      `json:"title" bson:"title"`
+	Description              string                      `json:"description" bson:"description"`
+	Status                   TaskStatus                  `json:"status" bson:"status"`
+	AssigneeID               string                      `json:"assignee_id" bson:"assignee_id"`
+	TeamID                   string                      `json:"team_id,omitempty" bson:"team_id,omitempty"`
+	ProjectID                string                      `json:"project_id" bson:"project_id"`
+	SprintID                 *string                     `json:"sprint_id,omitempty" bson:"sprint_id,omitempty"`
+	Rank                     string                      `json:"rank,omitempty" bson:"rank,omitempty"`
+	Priority                 TaskPriority                `json:"priority,omitempty" bson:"priority,omitempty"`
+	StoryPoints              *int                        `json:"story_points,omitempty" bson:"story_points,omitempty"`
+	OriginalEstimateMinutes  *int                        `json:"original_estimate_minutes,omitempty" bson:"original_estimate_minutes,omitempty"`
+	RemainingEstimateMinutes *int                        `json:"remaining_estimate_minutes,omitempty" bson:"remaining_estimate_minutes,omitempty"`
+	DueDate                  *time.Time                  `json:"due_date,omitempty" bson:"due_date,omitempty"`
+	DueTimezone              string                      `json:"due_timezone,omitempty" bson:"due_timezone,omitempty"`
+	LabelIDs                 []string                    `json:"label_ids,omitempty" bson:"label_ids,omitempty"`
+	Links                    []TaskLink                  `json:"links,omitempty" bson:"links,omitempty"`
+	Checklist                []ChecklistItem             `json:"checklist,omitempty" bson:"checklist,omitempty"`
+	ChecklistProgress        *ChecklistProgress          `json:"checklist_progress,omitempty" bson:"checklist_progress,omitempty"`
+	CustomFields             map[string]CustomFieldValue `json:"custom_fields,omitempty" bson:"custom_fields,omitempty"`
+	ClonedFrom               string                      `json:"cloned_from,omitempty" bson:"cloned_from,omitempty"`
+	DeletedAt                *time.Time                  `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy                string                      `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	TrashedWith              string                      `json:"trashed_with,omitempty" bson:"trashed_with,omitempty"`
+	CreatedAt                time.Time                   `json:"created_at" bson:"created_at"`
+	UpdatedAt                time.Time                   `json:"updated_at" bson:"updated_at"`
+	Progress                 *TaskProgress               `json:"progress,omitempty" bson:"-"`
+}
+
+type CustomFieldType string
+
+const (
+	CustomFieldText         CustomFieldType = "text"
+	CustomFieldNumber       CustomFieldType = "number"
+	CustomFieldDate         CustomFieldType = "date"
+	CustomFieldSingleSelect CustomFieldType = "single_select"
+	CustomFieldMultiSelect  CustomFieldType = "multi_select"
+	CustomFieldUser         CustomFieldType = "user"
+)
+
+// CustomField is a field a project adds to its tasks, such as a customer
+// ticket number. Select fields take one or more of Options.
+type CustomField struct {
+	ID        string            `json:"id" bson:"_id,omitempty"`
+	ProjectID string            `json:"project_id" bson:"project_id"`
+	Name      string            `json:"name" bson:"name"`
+	Type      CustomFieldType   `json:"type" bson:"type"`
+	Required  bool              `json:"required" bson:"required"`
+	Options   []string          `json:"options,omitempty" bson:"options,omitempty"`
+	Default   *CustomFieldValue `json:"default,omitempty" bson:"default,omitempty"`
+	CreatedAt time.Time         `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" bson:"updated_at"`
+}
+
+// CustomFieldValue is the value of a custom field on a task, held in the
+// member that fits the field's type: Text for text, single-select and user
+// fields, Number, Date (a calendar date at midnight UTC) or Options for
+// multi-select fields.
+type CustomFieldValue struct {
+	Text    string     `json:"text,omitempty" bson:"text,omitempty"`
+	Number  *float64   `json:"number,omitempty" bson:"number,omitempty"`
+	Date    *time.Time `json:"date,omitempty" bson:"date,omitempty"`
+	Options []string   `json:"options,omitempty" bson:"options,omitempty"`
+}
+
+type Label struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	ProjectID string    `json:"project_id" bson:"project_id"`
+	Name      string    `json:"name" bson:"name"`
+	Color     string    `json:"color" bson:"color"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Worklog is time a user spent on a task, starting at Start.
+type Worklog struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	TaskID    string    `json:"task_id" bson:"task_id"`
+	ProjectID string    `json:"project_id" bson:"project_id"`
+	UserID    string    `json:"user_id" bson:"user_id"`
+	Start     time.Time `json:"start" bson:"start"`
+	Minutes   int       `json:"minutes" bson:"minutes"`
+	Note      string    `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Timer is the running timer of a user, keyed by the user. Stopping it logs
+// the time since StartedAt.
+type Timer struct {
+	UserID    string    `json:"user_id" bson:"_id"`
+	TaskID    string    `json:"task_id" bson:"task_id"`
+	ProjectID string    `json:"project_id" bson:"project_id"`
+	StartedAt time.Time `json:"started_at" bson:"started_at"`
+	Note      string    `json:"note,omitempty" bson:"note,omitempty"`
+}
+
+// TaskTemplate is a reusable task. Its title, description, checklist and
+// subtasks may hold {{variable}} placeholders, filled in when a task is
+// created from it. Labels are by name, since labels belong to a project.
+type TaskTemplate struct {
+	ID          string            `json:"id" bson:"_id,omitempty"`
+	Name        string            `json:"name" bson:"name"`
+	OwnerID     string            `json:"owner_id" bson:"owner_id"`
+	Title       string            `json:"title" bson:"title"`
+	Description string            `json:"description" bson:"description"`
+	Type        TaskType          `json:"type,omitempty" bson:"type,omitempty"`
+	Priority    TaskPriority      `json:"priority,omitempty" bson:"priority,omitempty"`
+	StoryPoints *int              `json:"story_points,omitempty" bson:"story_points,omitempty"`
+	Labels      []string          `json:"labels,omitempty" bson:"labels,omitempty"`
+	Checklist   []string          `json:"checklist,omitempty" bson:"checklist,omitempty"`
+	Subtasks    []SubtaskTemplate `json:"subtasks,omitempty" bson:"subtasks,omitempty"`
+	CreatedAt   time.Time         `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" bson:"updated_at"`
+}
+
+type SubtaskTemplate struct {
+	Title       string `json:"title" bson:"title"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+}
+
+// ProjectTemplate is the starting point of a new project: its board
+// workflow, labels, starter tasks created from task templates, and sprint
+// cadence.
+type ProjectTemplate struct {
+	ID              string          `json:"id" bson:"_id,omitempty"`
+	Name            string          `json:"name" bson:"name"`
+	OwnerID         string          `json:"owner_id" bson:"owner_id"`
+	Description     string          `json:"description" bson:"description"`
+	BoardColumns    []BoardColumn   `json:"board_columns,omitempty" bson:"board_columns,omitempty"`
+	Labels          []LabelTemplate `json:"labels,omitempty" bson:"labels,omitempty"`
+	TaskTemplateIDs []string        `json:"task_template_ids,omitempty" bson:"task_template_ids,omitempty"`
+	SprintCadence   *SprintCadence  `json:"sprint_cadence,omitempty" bson:"sprint_cadence,omitempty"`
+	CreatedAt       time.Time       `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" bson:"updated_at"`
+}
+
+type LabelTemplate struct {
+	Name  string `json:"name" bson:"name"`
+	Color string `json:"color,omitempty" bson:"color,omitempty"`
+}
+
+// SprintCadence plans Count back-to-back sprints of LengthDays each.
+type SprintCadence struct {
+	LengthDays int `json:"length_days" bson:"length_days"`
+	Count      int `json:"count" bson:"count"`
 }
 
+// RecurringTask creates a task from its template at every occurrence of its
+// iCalendar RRULE, counted from StartAt in Timezone. NextRunAt is the next
+// occurrence to create, nil once the rule has ended.
+type RecurringTask struct {
+	ID                      string       `json:"id" bson:"_id,omitempty"`
+	ProjectID               string       `json:"project_id" bson:"project_id"`
+	Title                   string       `json:"title" bson:"title"`
+	Description             string       `json:"description" bson:"description"`
+	AssigneeID              string       `json:"assignee_id,omitempty" bson:"assignee_id,omitempty"`
+	Type                    TaskType     `json:"type,omitempty" bson:"type,omitempty"`
+	Priority                TaskPriority `json:"priority,omitempty" bson:"priority,omitempty"`
+	StoryPoints             *int         `json:"story_points,omitempty" bson:"story_points,omitempty"`
+	OriginalEstimateMinutes *int         `json:"original_estimate_minutes,omitempty" bson:"original_estimate_minutes,omitempty"`
+	LabelIDs                []string     `json:"label_ids,omitempty" bson:"label_ids,omitempty"`
+	RRule                   string       `json:"rrule" bson:"rrule"`
+	StartAt                 time.Time    `json:"start_at" bson:"start_at"`
+	Timezone                string       `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	Paused                  bool         `json:"paused" bson:"paused"`
+	NextRunAt               *time.Time   `json:"next_run_at,omitempty" bson:"next_run_at,omitempty"`
+	LastRunAt               *time.Time   `json:"last_run_at,omitempty" bson:"last_run_at,omitempty"`
+	CreatedBy               string       `json:"created_by" bson:"created_by"`
+	CreatedAt               time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt               time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+type Comment struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	TaskID    string    `json:"task_id" bson:"task_id"`
+	AuthorID  string    `json:"author_id" bson:"author_id"`
+	Body      string    `json:"body" bson:"body"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Project groups tasks under a key. Its members are the users in MemberIDs
+// and those of the teams in TeamIDs. An archived project, with ArchivedAt
+// set, is kept read-only and out of the default lists until unarchived.
 type Project struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	Name        string    `json:"name" bson:"name"`
-	Description string    `json:"description" bson:"description"`
-	OwnerID     string    `json:"owner_id" bson:"owner_id"`
-	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	ID                   string        `json:"id" bson:"_id,omitempty"`
+	Key                  string        `json:"key" bson:"key,omitempty"`
+	Name                 string        `json:"name" bson:"name"`
+	Description          string        `json:"description" bson:"description"`
+	OwnerID              string        `json:"owner_id" bson:"owner_id"`
+	MemberIDs            []string      `json:"member_ids" bson:"member_ids"`
+	TeamIDs              []string      `json:"team_ids,omitempty" bson:"team_ids,omitempty"`
+	BoardColumns         []BoardColumn `json:"board_columns,omitempty" bson:"board_columns,omitempty"`
+	BlockOverCapacity    bool          `json:"block_over_capacity,omitempty" bson:"block_over_capacity,omitempty"`
+	RequireChecklist     bool          `json:"require_checklist,omitempty" bson:"require_checklist,omitempty"`
+	TimesheetLockedUntil *time.Time    `json:"timesheet_locked_until,omitempty" bson:"timesheet_locked_until,omitempty"`
+	ArchivedAt           *time.Time    `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy           string        `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
+	DeletedAt            *time.Time    `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy            string        `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+	CreatedAt            time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt            time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// Team is a group of users work can be assigned to as a whole. Its lead is
+// one of its members; tasks created for the team without a project go to its
+// default project.
+type Team struct {
+	ID               string    `json:"id" bson:"_id,omitempty"`
+	Name             string    `json:"name" bson:"name"`
+	LeadID           string    `json:"lead_id,omitempty" bson:"lead_id,omitempty"`
+	MemberIDs        []string  `json:"member_ids" bson:"member_ids"`
+	DefaultProjectID string    `json:"default_project_id,omitempty" bson:"default_project_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// BoardColumn is a column of a project's kanban board, holding the tasks in
+// any of its statuses. A WIPLimit of zero means no limit. Projects without
+// columns get one column per status.
+type BoardColumn struct {
+	Name     string       `json:"name" bson:"name"`
+	Statuses []TaskStatus `json:"statuses" bson:"statuses"`
+	WIPLimit int          `json:"wip_limit,omitempty" bson:"wip_limit,omitempty"`
 }
 
 type SprintStatus string
@@ -63,6 +480,86 @@ type Sprint struct {
 	StartDate time.Time    `json:"start_date" bson:"start_date"`
 	EndDate   time.Time    `json:"end_date" bson:"end_date"`
 	Status    SprintStatus `json:"status" bson:"status"`
+	StartedAt *time.Time   `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	ClosedAt  *time.Time   `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
 	CreatedAt time.Time    `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at" bson:"updated_at"`
 }
+
+// SprintSnapshot is the state of a sprint's scope at the end of a day (UTC).
+type SprintSnapshot struct {
+	SprintID        string    `json:"sprint_id" bson:"sprint_id"`
+	Date            time.Time `json:"date" bson:"date"`
+	ScopePoints     int       `json:"scope_points" bson:"scope_points"`
+	CompletedPoints int       `json:"completed_points" bson:"completed_points"`
+	RemainingPoints int       `json:"remaining_points" bson:"remaining_points"`
+	ScopeTasks      int       `json:"scope_tasks" bson:"scope_tasks"`
+	CompletedTasks  int       `json:"completed_tasks" bson:"completed_tasks"`
+	RemainingTasks  int       `json:"remaining_tasks" bson:"remaining_tasks"`
+}
+
+type ActivityAction string
+
+const (
+	ActivityCreated ActivityAction = "created"
+	ActivityUpdated ActivityAction = "updated"
+	ActivityDeleted ActivityAction = "deleted"
+)
+
+// Activity records a change to one field of a task. Creating a task records
+// its initial value for each tracked field, and deleting it records a single
+// activity with no field.
+type Activity struct {
+	ID        string         `json:"id" bson:"_id,omitempty"`
+	TaskID    string         `json:"task_id" bson:"task_id"`
+	ProjectID string         `json:"project_id" bson:"project_id"`
+	Action    ActivityAction `json:"action" bson:"action"`
+	Field     string         `json:"field,omitempty" bson:"field,omitempty"`
+	From      string         `json:"from,omitempty" bson:"from,omitempty"`
+	To        string         `json:"to,omitempty" bson:"to,omitempty"`
+	At        time.Time      `json:"at" bson:"at"`
+}
+
+// SavedFilter is a named task query. Filters with a ProjectID are shared with
+// every member of that project; the rest are private to their owner.
+type SavedFilter struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	Name      string    `json:"name" bson:"name"`
+	Query     string    `json:"query" bson:"query"`
+	OwnerID   string    `json:"owner_id" bson:"owner_id"`
+	ProjectID string    `json:"project_id,omitempty" bson:"project_id,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type NotificationEvent string
+
+const (
+	NotifyAssigned      NotificationEvent = "assigned"
+	NotifyMentioned     NotificationEvent = "mentioned"
+	NotifyStatusChanged NotificationEvent = "status_changed"
+	NotifyCommented     NotificationEvent = "commented"
+	NotifyDueSoon       NotificationEvent = "due_soon"
+)
+
+// NotificationEvents lists every event users can be notified of.
+var NotificationEvents = []NotificationEvent{NotifyAssigned, NotifyMentioned, NotifyStatusChanged, NotifyCommented, NotifyDueSoon}
+
+// Notification tells a user about an event on a task. It keeps what clients
+// need to render it, as the task was when the event happened.
+type Notification struct {
+	ID        string            `json:"id" bson:"_id,omitempty"`
+	UserID    string            `json:"user_id" bson:"user_id"`
+	Event     NotificationEvent `json:"event" bson:"event"`
+	TaskID    string            `json:"task_id" bson:"task_id"`
+	ProjectID string            `json:"project_id" bson:"project_id"`
+	TaskKey   string            `json:"task_key" bson:"task_key"`
+	TaskTitle string            `json:"task_title" bson:"task_title"`
+	ActorID   string            `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	CommentID string            `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	Status    TaskStatus        `json:"status,omitempty" bson:"status,omitempty"`
+	DueDate   *time.Time        `json:"due_date,omitempty" bson:"due_date,omitempty"`
+	ReadAt    *time.Time        `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	EmailedAt *time.Time        `json:"emailed_at,omitempty" bson:"emailed_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at" bson:"created_at"`
+}
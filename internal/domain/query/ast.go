@@ -0,0 +1,84 @@
+package query
+
+import "time"
+
+// Query is a parsed task query: an optional filter expression and sort order.
+type Query struct {
+	Where   Expr
+	OrderBy []OrderTerm
+}
+
+type OrderTerm struct {
+	Field      Field
+	Descending bool
+}
+
+// Expr is a node of the filter expression tree.
+type Expr interface {
+	expr()
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+type Operator string
+
+const (
+	OpEqual          Operator = "="
+	OpNotEqual       Operator = "!="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpContains       Operator = "~"
+	OpNotContains    Operator = "!~"
+	OpIn             Operator = "IN"
+	OpNotIn          Operator = "NOT IN"
+	OpEmpty          Operator = "IS EMPTY"
+	OpNotEmpty       Operator = "IS NOT EMPTY"
+)
+
+// Comparison tests a single task field against one or more literal values.
+type Comparison struct {
+	Field  Field
+	Op     Operator
+	Values []Value
+}
+
+// Value is a literal from the query, resolved against the field it is compared with.
+type Value struct {
+	Raw     string    // literal as written in the query
+	Text    string    // resolved value for string, text and list fields
+	Time    time.Time // resolved instant for time fields
+	Number  int       // resolved value for number fields
+	Decimal float64   // resolved value for decimal fields
+}
+
+func (And) expr()        {}
+func (Or) expr()         {}
+func (Not) expr()        {}
+func (Comparison) expr() {}
+
+// RestrictToProjects narrows q to tasks belonging to one of the given projects.
+func RestrictToProjects(q *Query, projectIDs []string) {
+	values := make([]Value, len(projectIDs))
+	for i, id := range projectIDs {
+		values[i] = Value{Raw: id, Text: id}
+	}
+
+	scope := Comparison{Field: fields["project"], Op: OpIn, Values: values}
+	if q.Where == nil {
+		q.Where = scope
+		return
+	}
+	q.Where = And{Left: scope, Right: q.Where}
+}
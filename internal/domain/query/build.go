@@ -0,0 +1,29 @@
+package query
+
+import "fmt"
+
+// Compare builds a comparison on the named field, for queries assembled in
+// code rather than parsed. It panics if the field does not exist.
+func Compare(field string, op Operator, values ...Value) Comparison {
+	f, ok := lookupField(field)
+	if !ok {
+		panic(fmt.Sprintf("query: unknown field %q", field))
+	}
+	return Comparison{Field: f, Op: op, Values: values}
+}
+
+// All joins expressions with AND, skipping nil ones. It returns nil when no
+// expression remains, which matches every task.
+func All(exprs ...Expr) Expr {
+	var result Expr
+	for _, expr := range exprs {
+		switch {
+		case expr == nil:
+		case result == nil:
+			result = expr
+		default:
+			result = And{Left: result, Right: expr}
+		}
+	}
+	return result
+}
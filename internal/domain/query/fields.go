@@ -0,0 +1,82 @@
+package query
+
+type FieldKind int
+
+const (
+	// KindString fields hold identifiers and enum values compared exactly.
+	KindString FieldKind = iota
+	// KindText fields hold free text and also support the ~ (contains) operator.
+	KindText
+	// KindTime fields hold instants and accept absolute or relative dates.
+	KindTime
+	// KindNumber fields hold integers; named values such as priorities are
+	// resolved to their rank.
+	KindNumber
+	// KindList fields hold a set of IDs; a comparison matches when any of
+	// them matches.
+	KindList
+	// KindDecimal fields hold floating-point numbers.
+	KindDecimal
+)
+
+// Field describes a task attribute that can be filtered and sorted on.
+type Field struct {
+	Name string
+	Key  string // BSON key of the attribute in the tasks collection
+	Kind FieldKind
+	// Custom is the ID of the project custom field this field reads, if any.
+	Custom string
+}
+
+var fields = map[string]Field{
+	"key":         {Name: "key", Key: "key", Kind: KindString},
+	"project":     {Name: "project", Key: "project_id", Kind: KindString},
+	"type":        {Name: "type", Key: "type", Kind: KindString},
+	"parent":      {Name: "parent", Key: "parent_id", Kind: KindString},
+	"status":      {Name: "status", Key: "status", Kind: KindString},
+	"assignee":    {Name: "assignee", Key: "assignee_id", Kind: KindString},
+	"team":        {Name: "team", Key: "team_id", Kind: KindString},
+	"sprint":      {Name: "sprint", Key: "sprint_id", Kind: KindString},
+	"title":       {Name: "title", Key: "title", Kind: KindText},
+	"description": {Name: "description", Key: "description", Kind: KindText},
+	"priority":    {Name: "priority", Key: "priority", Kind: KindNumber},
+	"points":      {Name: "points", Key: "story_points", Kind: KindNumber},
+	"due":         {Name: "due", Key: "due_date", Kind: KindTime},
+	"label":       {Name: "label", Key: "label_ids", Kind: KindList},
+	"created":     {Name: "created", Key: "created_at", Kind: KindTime},
+	"updated":     {Name: "updated", Key: "updated_at", Kind: KindTime},
+}
+
+func lookupField(name string) (Field, bool) {
+	field, ok := fields[name]
+	return field, ok
+}
+
+// CustomField returns the field holding the values of the custom field with
+// the given ID, compared as kind.
+func CustomField(id string, kind FieldKind) Field {
+	member := "text"
+	switch kind {
+	case KindDecimal:
+		member = "number"
+	case KindTime:
+		member = "date"
+	case KindList:
+		member = "options"
+	}
+	return Field{Name: "custom_fields." + id, Key: "custom_fields." + id + "." + member, Kind: kind, Custom: id}
+}
+
+func (k FieldKind) allows(op Operator) bool {
+	switch op {
+	case OpEqual, OpNotEqual, OpEmpty, OpNotEmpty:
+		return true
+	case OpIn, OpNotIn:
+		return k != KindTime
+	case OpContains, OpNotContains:
+		return k == KindText
+	case OpGreater, OpGreaterOrEqual, OpLess, OpLessOrEqual:
+		return k == KindTime || k == KindNumber || k == KindDecimal
+	}
+	return false
+}
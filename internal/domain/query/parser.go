@@ -0,0 +1,382 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// Env carries the context needed to resolve query literals such as "me" or "-7d".
+type Env struct {
+	UserID string
+	Now    time.Time
+	// ResolveProject maps a project key such as "API" to the project ID.
+	ResolveProject func(string) string
+	// ResolveLabel maps a label name to the IDs of every label with that
+	// name, since labels are scoped to their project.
+	ResolveLabel func(string) []string
+}
+
+// Parse parses a query such as
+//
+//	project = API AND status IN (to_do, in_progress) AND assignee = me
+//	AND updated > -7d ORDER BY updated DESC
+//
+// and resolves its literals against env. An empty input matches every task.
+func Parse(input string, env Env) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, env: env}
+	q := &Query{}
+
+	if !p.peek().keyword("ORDER") && p.peek().kind != tokenEOF {
+		q.Where, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek().keyword("ORDER") {
+		p.next()
+		if !p.next().keyword("BY") {
+			return nil, p.errorf("expected BY after ORDER")
+		}
+		q.OrderBy, err = p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return q, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	env    Env
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.peek().pos)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch tok := p.peek(); {
+	case tok.keyword("NOT"):
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: inner}, nil
+	case tok.kind == tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, p.errorf("expected ')'")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	tok := p.next()
+	if tok.kind != tokenWord {
+		return nil, fmt.Errorf("expected field name at position %d", tok.pos)
+	}
+	field, ok := lookupField(strings.ToLower(tok.text))
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q at position %d", tok.text, tok.pos)
+	}
+
+	cmp := Comparison{Field: field}
+	switch op := p.next(); {
+	case op.kind == tokenOperator:
+		cmp.Op = Operator(op.text)
+	case op.keyword("IN"):
+		cmp.Op = OpIn
+	case op.keyword("NOT") && p.peek().keyword("IN"):
+		p.next()
+		cmp.Op = OpNotIn
+	case op.keyword("IS"):
+		cmp.Op = OpEmpty
+		if p.peek().keyword("NOT") {
+			p.next()
+			cmp.Op = OpNotEmpty
+		}
+		if !p.next().keyword("EMPTY") {
+			return nil, p.errorf("expected EMPTY")
+		}
+	default:
+		return nil, fmt.Errorf("expected operator after %q at position %d", tok.text, op.pos)
+	}
+
+	if !field.Kind.allows(cmp.Op) {
+		return nil, fmt.Errorf("operator %s is not supported for field %q", cmp.Op, field.Name)
+	}
+
+	switch cmp.Op {
+	case OpEmpty, OpNotEmpty:
+	case OpIn, OpNotIn:
+		values, err := p.parseList(field)
+		if err != nil {
+			return nil, err
+		}
+		cmp.Values = values
+	default:
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		cmp.Values = []Value{value}
+	}
+
+	if field.Kind == KindList {
+		return p.expandList(cmp), nil
+	}
+	return cmp, nil
+}
+
+// expandList rewrites a comparison on a list field in terms of IN and NOT IN
+// over the IDs its literals resolve to.
+func (p *parser) expandList(cmp Comparison) Comparison {
+	switch cmp.Op {
+	case OpEqual:
+		cmp.Op = OpIn
+	case OpNotEqual:
+		cmp.Op = OpNotIn
+	case OpEmpty, OpNotEmpty:
+		return cmp
+	}
+
+	var ids []Value
+	for _, v := range cmp.Values {
+		if p.env.ResolveLabel == nil {
+			ids = append(ids, v)
+			continue
+		}
+		for _, id := range p.env.ResolveLabel(v.Raw) {
+			ids = append(ids, Value{Raw: v.Raw, Text: id})
+		}
+	}
+	cmp.Values = ids
+	return cmp
+}
+
+func (p *parser) parseList(field Field) ([]Value, error) {
+	if p.next().kind != tokenLParen {
+		return nil, p.errorf("expected '('")
+	}
+	var values []Value
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		switch p.next().kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return values, nil
+		default:
+			return nil, p.errorf("expected ',' or ')'")
+		}
+	}
+}
+
+func (p *parser) parseValue(field Field) (Value, error) {
+	tok := p.next()
+	if tok.kind != tokenWord && tok.kind != tokenString {
+		return Value{}, fmt.Errorf("expected value for %q at position %d", field.Name, tok.pos)
+	}
+
+	value, err := p.resolve(field, tok)
+	if err != nil {
+		return Value{}, fmt.Errorf("%v at position %d", err, tok.pos)
+	}
+	return value, nil
+}
+
+func (p *parser) resolve(field Field, tok token) (Value, error) {
+	value := Value{Raw: tok.text, Text: tok.text}
+
+	switch {
+	case field.Kind == KindTime:
+		t, err := p.resolveTime(tok.text)
+		if err != nil {
+			return Value{}, err
+		}
+		value.Time = t
+	case field.Name == "priority":
+		priority, err := models.ParsePriority(strings.ToLower(tok.text))
+		if err != nil || priority == models.PriorityNone {
+			return Value{}, fmt.Errorf("unknown priority %q", tok.text)
+		}
+		value.Number = int(priority)
+	case field.Kind == KindNumber:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid number %q", tok.text)
+		}
+		value.Number = n
+	case field.Name == "assignee" && tok.kind == tokenWord && strings.EqualFold(tok.text, "me"):
+		value.Text = p.env.UserID
+	case field.Name == "project" && p.env.ResolveProject != nil:
+		value.Text = p.env.ResolveProject(tok.text)
+	case field.Name == "key":
+		value.Text = strings.ToUpper(tok.text)
+	case field.Name == "status":
+		status := models.TaskStatus(strings.ToLower(tok.text))
+		if !validStatuses[status] {
+			return Value{}, fmt.Errorf("unknown status %q", tok.text)
+		}
+		value.Text = string(status)
+	case field.Name == "type":
+		taskType := models.TaskType(strings.ToLower(tok.text))
+		if !validTypes[taskType] {
+			return Value{}, fmt.Errorf("unknown task type %q", tok.text)
+		}
+		value.Text = string(taskType)
+	}
+	return value, nil
+}
+
+var validStatuses = map[models.TaskStatus]bool{
+	models.ToDo:                   true,
+	models.InProgress:             true,
+	models.ReadyForImplementation: true,
+	models.Done:                   true,
+}
+
+var validTypes = map[models.TaskType]bool{
+	models.TypeEpic:    true,
+	models.TypeStory:   true,
+	models.TypeTask:    true,
+	models.TypeSubtask: true,
+}
+
+// resolveTime accepts "now", "today", relative offsets such as -7d, +2w or -12h,
+// calendar dates (2006-01-02) and RFC 3339 timestamps.
+func (p *parser) resolveTime(text string) (time.Time, error) {
+	now := p.env.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	switch strings.ToLower(text) {
+	case "now":
+		return now, nil
+	case "today":
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+	}
+
+	if len(text) >= 3 && (text[0] == '-' || text[0] == '+') {
+		amount, err := strconv.Atoi(text[1 : len(text)-1])
+		if err == nil {
+			if text[0] == '-' {
+				amount = -amount
+			}
+			switch text[len(text)-1] {
+			case 'h':
+				return now.Add(time.Duration(amount) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, amount), nil
+			case 'w':
+				return now.AddDate(0, 0, 7*amount), nil
+			}
+		}
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", text, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", text)
+}
+
+func (p *parser) parseOrderBy() ([]OrderTerm, error) {
+	var terms []OrderTerm
+	for {
+		tok := p.next()
+		field, ok := lookupField(strings.ToLower(tok.text))
+		if tok.kind != tokenWord || !ok {
+			return nil, fmt.Errorf("unknown sort field %q at position %d", tok.text, tok.pos)
+		}
+
+		term := OrderTerm{Field: field}
+		if p.peek().keyword("DESC") {
+			p.next()
+			term.Descending = true
+		} else if p.peek().keyword("ASC") {
+			p.next()
+		}
+		terms = append(terms, term)
+
+		if p.peek().kind != tokenComma {
+			return terms, nil
+		}
+		p.next()
+	}
+}
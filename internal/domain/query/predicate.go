@@ -0,0 +1,307 @@
+package query
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// Predicate compiles expr into a function that reports whether a task matches it.
+// A nil expression matches every task.
+func Predicate(expr Expr) func(*models.Task) bool {
+	switch e := expr.(type) {
+	case nil:
+		return func(*models.Task) bool { return true }
+	case And:
+		left, right := Predicate(e.Left), Predicate(e.Right)
+		return func(t *models.Task) bool { return left(t) && right(t) }
+	case Or:
+		left, right := Predicate(e.Left), Predicate(e.Right)
+		return func(t *models.Task) bool { return left(t) || right(t) }
+	case Not:
+		inner := Predicate(e.Expr)
+		return func(t *models.Task) bool { return !inner(t) }
+	case Comparison:
+		return comparisonPredicate(e)
+	}
+	return func(*models.Task) bool { return false }
+}
+
+func comparisonPredicate(c Comparison) func(*models.Task) bool {
+	switch c.Field.Kind {
+	case KindTime:
+		return func(t *models.Task) bool {
+			got, ok := timeValue(t, c.Field)
+			return matchOrdered(c, ok, got.Compare(firstValue(c).Time))
+		}
+	case KindNumber:
+		return func(t *models.Task) bool {
+			got, ok := numberValue(t, c.Field)
+			in := slices.ContainsFunc(c.Values, func(v Value) bool { return v.Number == got })
+			switch c.Op {
+			case OpIn:
+				return ok && in
+			case OpNotIn:
+				return !ok || !in
+			}
+			return matchOrdered(c, ok, cmpInt(got, firstValue(c).Number))
+		}
+	case KindDecimal:
+		return func(t *models.Task) bool {
+			got, ok := decimalValue(t, c.Field)
+			in := slices.ContainsFunc(c.Values, func(v Value) bool { return v.Decimal == got })
+			switch c.Op {
+			case OpIn:
+				return ok && in
+			case OpNotIn:
+				return !ok || !in
+			}
+			return matchOrdered(c, ok, cmp.Compare(got, firstValue(c).Decimal))
+		}
+	case KindList:
+		return func(t *models.Task) bool {
+			got := listValue(t, c.Field)
+			switch c.Op {
+			case OpIn:
+				return slices.ContainsFunc(got, func(s string) bool { return containsValue(c.Values, s) })
+			case OpNotIn:
+				return !slices.ContainsFunc(got, func(s string) bool { return containsValue(c.Values, s) })
+			case OpEmpty:
+				return len(got) == 0
+			case OpNotEmpty:
+				return len(got) > 0
+			}
+			return false
+		}
+	}
+
+	return func(t *models.Task) bool {
+		got := stringValue(t, c.Field)
+		switch c.Op {
+		case OpEqual:
+			return got == c.Values[0].Text
+		case OpNotEqual:
+			return got != c.Values[0].Text
+		case OpContains:
+			return strings.Contains(strings.ToLower(got), strings.ToLower(c.Values[0].Text))
+		case OpNotContains:
+			return !strings.Contains(strings.ToLower(got), strings.ToLower(c.Values[0].Text))
+		case OpIn:
+			return containsValue(c.Values, got)
+		case OpNotIn:
+			return !containsValue(c.Values, got)
+		case OpEmpty:
+			return got == ""
+		case OpNotEmpty:
+			return got != ""
+		}
+		return false
+	}
+}
+
+// matchOrdered applies an operator to a field that may be unset. cmp is the
+// comparison of the field value against the literal; unset fields only match
+// IS EMPTY and !=, as in Mongo.
+func matchOrdered(c Comparison, set bool, cmp int) bool {
+	switch c.Op {
+	case OpEmpty:
+		return !set
+	case OpNotEmpty:
+		return set
+	case OpNotEqual:
+		return !set || cmp != 0
+	}
+	if !set {
+		return false
+	}
+
+	switch c.Op {
+	case OpEqual:
+		return cmp == 0
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterOrEqual:
+		return cmp >= 0
+	case OpLess:
+		return cmp < 0
+	case OpLessOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+func firstValue(c Comparison) Value {
+	if len(c.Values) == 0 {
+		return Value{}
+	}
+	return c.Values[0]
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func containsValue(values []Value, s string) bool {
+	for _, v := range values {
+		if v.Text == s {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(t *models.Task, f Field) string {
+	if f.Custom != "" {
+		return t.CustomFields[f.Custom].Text
+	}
+	switch f.Name {
+	case "key":
+		return t.Key
+	case "project":
+		return t.ProjectID
+	case "type":
+		return string(t.Type)
+	case "parent":
+		return t.ParentID
+	case "status":
+		return string(t.Status)
+	case "assignee":
+		return t.AssigneeID
+	case "team":
+		return t.TeamID
+	case "sprint":
+		if t.SprintID == nil {
+			return ""
+		}
+		return *t.SprintID
+	case "title":
+		return t.Title
+	case "description":
+		return t.Description
+	}
+	return ""
+}
+
+func timeValue(t *models.Task, f Field) (time.Time, bool) {
+	if f.Custom != "" {
+		date := t.CustomFields[f.Custom].Date
+		if date == nil {
+			return time.Time{}, false
+		}
+		return *date, true
+	}
+	switch f.Name {
+	case "due":
+		if t.DueDate == nil {
+			return time.Time{}, false
+		}
+		return *t.DueDate, true
+	case "created":
+		return t.CreatedAt, true
+	case "updated":
+		return t.UpdatedAt, true
+	}
+	return time.Time{}, false
+}
+
+func numberValue(t *models.Task, f Field) (int, bool) {
+	switch f.Name {
+	case "priority":
+		return int(t.Priority), t.Priority != models.PriorityNone
+	case "points":
+		if t.StoryPoints == nil {
+			return 0, false
+		}
+		return *t.StoryPoints, true
+	}
+	return 0, false
+}
+
+func decimalValue(t *models.Task, f Field) (float64, bool) {
+	number := t.CustomFields[f.Custom].Number
+	if number == nil {
+		return 0, false
+	}
+	return *number, true
+}
+
+func listValue(t *models.Task, f Field) []string {
+	if f.Custom != "" {
+		return t.CustomFields[f.Custom].Options
+	}
+	switch f.Name {
+	case "label":
+		return t.LabelIDs
+	}
+	return nil
+}
+
+// Sort orders tasks in place by the given terms. Unset values sort before
+// set ones, matching Mongo's ordering of missing fields.
+func Sort(tasks []*models.Task, terms []OrderTerm) {
+	if len(terms) == 0 {
+		return
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, term := range terms {
+			c := compare(tasks[i], tasks[j], term.Field)
+			if c == 0 {
+				continue
+			}
+			if term.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+func compare(a, b *models.Task, f Field) int {
+	switch f.Kind {
+	case KindTime:
+		x, xok := timeValue(a, f)
+		y, yok := timeValue(b, f)
+		if xok != yok {
+			return cmpBool(xok, yok)
+		}
+		return x.Compare(y)
+	case KindNumber:
+		x, xok := numberValue(a, f)
+		y, yok := numberValue(b, f)
+		if xok != yok {
+			return cmpBool(xok, yok)
+		}
+		return cmpInt(x, y)
+	case KindDecimal:
+		x, xok := decimalValue(a, f)
+		y, yok := decimalValue(b, f)
+		if xok != yok {
+			return cmpBool(xok, yok)
+		}
+		return cmp.Compare(x, y)
+	case KindList:
+		return slices.Compare(listValue(a, f), listValue(b, f))
+	}
+	return strings.Compare(stringValue(a, f), stringValue(b, f))
+}
+
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,183 @@
+// Package rank implements LexoRank-style ordering keys: strings that sort in
+// the order of the items they rank, such that a key can always be generated
+// between two neighbours without touching any other item.
+//
+// A rank has the form "gen|value". The value is a base-36 fraction that never
+// ends in '0', so there is always room before it. The generation is bumped by
+// every rebalance, which respreads the values of a whole list; since every
+// rank of the new generation sorts after every rank of the old one, a list
+// rebalanced from its last item to its first stays ordered at every step.
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	base   = len(digits)
+
+	genWidth = 3
+	// MaxValueLength is the value length past which a list should be
+	// rebalanced; repeated inserts at one spot grow values by about one
+	// digit every five inserts.
+	MaxValueLength = 16
+)
+
+// ErrNoRoom is returned when the neighbours of a new rank are equal, which
+// only happens when concurrent moves picked the same key, or out of order.
+// Rebalancing the list makes room again.
+var ErrNoRoom = errors.New("no room between equal ranks")
+
+// Between returns a rank that sorts strictly between prev and next. An empty
+// prev or next means the start or end of the list.
+func Between(prev, next string) (string, error) {
+	switch {
+	case prev == "" && next == "":
+		return format(0, midpoint("", "", false)), nil
+	case prev == "":
+		gen, value, err := parse(next)
+		if err != nil {
+			return "", err
+		}
+		return format(gen, midpoint("", value, true)), nil
+	case next == "":
+		gen, value, err := parse(prev)
+		if err != nil {
+			return "", err
+		}
+		return format(gen, midpoint(value, "", false)), nil
+	}
+
+	prevGen, prevValue, err := parse(prev)
+	if err != nil {
+		return "", err
+	}
+	nextGen, nextValue, err := parse(next)
+	if err != nil {
+		return "", err
+	}
+	if prev >= next {
+		return "", ErrNoRoom
+	}
+	if prevGen != nextGen {
+		// A rebalance is under way: anything below next in its generation
+		// also sorts after prev.
+		return format(nextGen, midpoint("", nextValue, true)), nil
+	}
+	return format(nextGen, midpoint(prevValue, nextValue, true)), nil
+}
+
+// Spread returns n evenly spaced, increasing ranks in the generation after
+// the newest of current, which should hold every rank of the list.
+func Spread(current []string, n int) []string {
+	gen := 0
+	for _, r := range current {
+		if g, _, err := parse(r); err == nil && g >= gen {
+			gen = g + 1
+		}
+	}
+
+	width := 2
+	span := base * base
+	for span < 4*(n+1) {
+		width++
+		span *= base
+	}
+	step := span / (n + 1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := []byte(strings.Repeat("0", width))
+		for v, j := (i+1)*step, width-1; v > 0; v, j = v/base, j-1 {
+			value[j] = digits[v%base]
+		}
+		ranks[i] = format(gen, strings.TrimRight(string(value), "0"))
+	}
+	return ranks
+}
+
+// Crowded reports whether r has grown long enough that its list should be
+// rebalanced.
+func Crowded(r string) bool {
+	_, value, err := parse(r)
+	return err != nil || len(value) > MaxValueLength
+}
+
+func format(gen int, value string) string {
+	g := make([]byte, genWidth)
+	for i := genWidth - 1; i >= 0; i-- {
+		g[i] = digits[gen%base]
+		gen /= base
+	}
+	return string(g) + "|" + value
+}
+
+func parse(r string) (int, string, error) {
+	g, value, ok := strings.Cut(r, "|")
+	if !ok || len(g) != genWidth || value == "" {
+		return 0, "", fmt.Errorf("invalid rank %q", r)
+	}
+
+	gen := 0
+	for _, c := range g {
+		d := strings.IndexRune(digits, c)
+		if d < 0 {
+			return 0, "", fmt.Errorf("invalid rank %q", r)
+		}
+		gen = gen*base + d
+	}
+	for _, c := range value {
+		if !strings.ContainsRune(digits, c) {
+			return 0, "", fmt.Errorf("invalid rank %q", r)
+		}
+	}
+	return gen, value, nil
+}
+
+// midpoint returns a value strictly between a and b, where an empty a is the
+// lowest value and b is unbounded unless bounded is set. Neither a nor b may
+// end in '0', and neither does the result.
+func midpoint(a, b string, bounded bool) string {
+	if bounded {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:], true)
+		}
+	}
+
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+	hi := base
+	if bounded {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi+1)/2])
+	}
+	if bounded && len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[lo]) + midpoint(suffix(a, 1), "", false)
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return '0'
+}
+
+func suffix(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
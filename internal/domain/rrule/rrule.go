@@ -0,0 +1,423 @@
+// Package rrule implements the recurrence rules of iCalendar (RFC 5545), such
+// as "FREQ=MONTHLY;BYDAY=1MO" for the first Monday of every month.
+//
+// The supported parts are FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL,
+// COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY, BYHOUR, BYMINUTE, BYSETPOS and
+// WKST. Occurrences are computed in the location of the start time, so a
+// rule keeps its wall-clock time across daylight saving changes.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry: a weekday, and for monthly and yearly rules an
+// optional position such as 2 for the second or -1 for the last of the month
+// or year. Zero means every such weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByMonth    []int
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+	ByHour     []int
+	ByMinute   []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// maxEmptyPeriods bounds the search for the next occurrence of a rule that
+// can never match again, such as the 30th of February.
+const maxEmptyPeriods = 1000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", with or
+// without an "RRULE:" prefix.
+func Parse(input string) (*Rule, error) {
+	input = strings.TrimPrefix(strings.TrimSpace(input), "RRULE:")
+	if input == "" {
+		return nil, errors.New("rrule is empty")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(input, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+			if !slices.Contains([]Frequency{Daily, Weekly, Monthly, Yearly}, rule.Freq) {
+				return nil, fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if err == nil && rule.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+			if err == nil && rule.Count < 1 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYMONTH":
+			rule.ByMonth, err = parseInts(value, 1, 12, false)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(value, 1, 31, true)
+		case "BYHOUR":
+			rule.ByHour, err = parseInts(value, 0, 23, false)
+		case "BYMINUTE":
+			rule.ByMinute, err = parseInts(value, 0, 59, false)
+		case "BYSETPOS":
+			rule.BySetPos, err = parseInts(value, 1, 366, true)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(value)]
+			if !ok {
+				err = errors.New("unknown weekday")
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", strings.ToUpper(name), value, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("rrule has no FREQ")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, errors.New("rrule cannot have both COUNT and UNTIL")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, errors.New("BYDAY positions only apply to MONTHLY and YEARLY rules")
+		}
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("not a date or UTC date-time")
+}
+
+// parseInts parses a comma-separated list of numbers between min and max, or
+// between -max and -min too when negative counts from the end.
+func parseInts(value string, min, max int, negative bool) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		abs := n
+		if negative && n < 0 {
+			abs = -n
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("%d is out of range", n)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, field := range strings.Split(strings.ToUpper(value), ",") {
+		if len(field) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", field)
+		}
+		day, ok := weekdays[field[len(field)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", field)
+		}
+		entry := WeekdayNum{Weekday: day}
+		if prefix := field[:len(field)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday position %q", field)
+			}
+			entry.N = n
+		}
+		days = append(days, entry)
+	}
+	return days, nil
+}
+
+// String formats the rule back into its RRULE form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	ints := func(name string, values []int) {
+		if len(values) == 0 {
+			return
+		}
+		fields := make([]string, len(values))
+		for i, v := range values {
+			fields[i] = strconv.Itoa(v)
+		}
+		parts = append(parts, name+"="+strings.Join(fields, ","))
+	}
+	ints("BYMONTH", r.ByMonth)
+	ints("BYMONTHDAY", r.ByMonthDay)
+	if len(r.ByDay) > 0 {
+		fields := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			fields[i] = strings.ToUpper(day.Weekday.String()[:2])
+			if day.N != 0 {
+				fields[i] = strconv.Itoa(day.N) + fields[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(fields, ","))
+	}
+	ints("BYHOUR", r.ByHour)
+	ints("BYMINUTE", r.ByMinute)
+	ints("BYSETPOS", r.BySetPos)
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.WeekStart.String()[:2]))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence of the rule started at start that falls
+// strictly after after, and false when there is none.
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			next, found = t, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// Occurrences returns up to n occurrences of the rule started at start that
+// fall strictly after after.
+func (r *Rule) Occurrences(start, after time.Time, n int) []time.Time {
+	var occurrences []time.Time
+	if n <= 0 {
+		return occurrences
+	}
+	r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			occurrences = append(occurrences, t)
+		}
+		return len(occurrences) < n
+	})
+	return occurrences
+}
+
+// each calls yield with every occurrence of the rule, in order, starting with
+// start itself when it matches, until yield returns false or the rule ends.
+func (r *Rule) each(start time.Time, yield func(time.Time) bool) {
+	count, empty := 0, 0
+	for period := 0; empty < maxEmptyPeriods; period++ {
+		occurrences := r.expand(start, period)
+		if len(occurrences) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, t := range occurrences {
+			if t.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return
+			}
+			count++
+			if !yield(t) || (r.Count > 0 && count >= r.Count) {
+				return
+			}
+		}
+	}
+}
+
+// expand returns the occurrences in the period'th period of the rule, in
+// order: a day, week, month or year.
+func (r *Rule) expand(start time.Time, period int) []time.Time {
+	loc := start.Location()
+	year, month, day := start.Date()
+	step := period * r.Interval
+
+	var first time.Time
+	var days int
+	switch r.Freq {
+	case Daily:
+		first = time.Date(year, month, day+step, 0, 0, 0, 0, loc)
+		days = 1
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		first = time.Date(year, month, day-offset+7*step, 0, 0, 0, 0, loc)
+		days = 7
+	case Monthly:
+		first = time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, loc)
+		days = daysIn(first.Year(), first.Month())
+	case Yearly:
+		first = time.Date(year+step, time.January, 1, 0, 0, 0, 0, loc)
+		days = time.Date(first.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+
+	var dates []time.Time
+	for i := 0; i < days; i++ {
+		date := time.Date(first.Year(), first.Month(), first.Day()+i, 0, 0, 0, 0, loc)
+		if r.matches(date, start) {
+			dates = append(dates, date)
+		}
+	}
+
+	hours, minutes := r.ByHour, r.ByMinute
+	if len(hours) == 0 {
+		hours = []int{start.Hour()}
+	}
+	if len(minutes) == 0 {
+		minutes = []int{start.Minute()}
+	}
+	hours, minutes = sorted(hours), sorted(minutes)
+
+	var occurrences []time.Time
+	for _, date := range dates {
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				occurrences = append(occurrences, time.Date(date.Year(), date.Month(), date.Day(), hour, minute, start.Second(), 0, loc))
+			}
+		}
+	}
+	return r.selectPositions(occurrences)
+}
+
+// matches reports whether a date of the current period satisfies the BYxxx
+// parts of the rule, or the parts implied by the start when they are absent.
+func (r *Rule) matches(date, start time.Time) bool {
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(date.Month())) {
+		return false
+	}
+	if r.Freq == Yearly && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 &&
+		date.Month() != start.Month() {
+		return false
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		last := daysIn(date.Year(), date.Month())
+		if !slices.ContainsFunc(r.ByMonthDay, func(d int) bool {
+			return d == date.Day() || (d < 0 && last+1+d == date.Day())
+		}) {
+			return false
+		}
+	} else if len(r.ByDay) == 0 && (r.Freq == Monthly || r.Freq == Yearly) && date.Day() != start.Day() {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		return slices.ContainsFunc(r.ByDay, func(day WeekdayNum) bool {
+			return r.matchesWeekday(date, day)
+		})
+	}
+	if r.Freq == Weekly && date.Weekday() != start.Weekday() {
+		return false
+	}
+	return true
+}
+
+// matchesWeekday checks a BYDAY entry. Positions count within the month for
+// monthly rules and yearly rules with BYMONTH, and within the year otherwise.
+func (r *Rule) matchesWeekday(date time.Time, day WeekdayNum) bool {
+	if date.Weekday() != day.Weekday {
+		return false
+	}
+	if day.N == 0 {
+		return true
+	}
+
+	index := (date.Day()-1)/7 + 1
+	total := (daysIn(date.Year(), date.Month())-date.Day())/7 + index
+	if r.Freq == Yearly && len(r.ByMonth) == 0 {
+		yearDays := time.Date(date.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		index = (date.YearDay()-1)/7 + 1
+		total = (yearDays-date.YearDay())/7 + index
+	}
+	if day.N > 0 {
+		return index == day.N
+	}
+	return total+1+day.N == index
+}
+
+// selectPositions applies BYSETPOS to the occurrences of one period.
+func (r *Rule) selectPositions(occurrences []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return occurrences
+	}
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(occurrences) + pos
+		}
+		if i >= 0 && i < len(occurrences) && !slices.ContainsFunc(selected, occurrences[i].Equal) {
+			selected = append(selected, occurrences[i])
+		}
+	}
+	slices.SortFunc(selected, func(a, b time.Time) int { return a.Compare(b) })
+	return selected
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func sorted(values []int) []int {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
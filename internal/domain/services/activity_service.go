@@ -0,0 +1,216 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type ActivityRepository interface {
+	Create(activity *models.Activity) error
+	// ListByTasks returns the activities of the given tasks, oldest first.
+	ListByTasks(taskIDs []string) ([]*models.Activity, error)
+	// TaskIDsForSprint returns the IDs of every task that was ever in a sprint.
+	TaskIDsForSprint(sprintID string) ([]string, error)
+}
+
+// trackedFields are the task attributes whose changes are recorded, each
+// rendered as a string.
+var trackedFields = []struct {
+	name  string
+	value func(*models.Task) string
+}{
+	{"status", func(t *models.Task) string { return string(t.Status) }},
+	{"assignee", func(t *models.Task) string { return t.AssigneeID }},
+	{"team", func(t *models.Task) string { return t.TeamID }},
+	{"sprint", func(t *models.Task) string {
+		if t.SprintID == nil {
+			return ""
+		}
+		return *t.SprintID
+	}},
+	{"story_points", func(t *models.Task) string {
+		if t.StoryPoints == nil {
+			return ""
+		}
+		return strconv.Itoa(*t.StoryPoints)
+	}},
+	{"priority", func(t *models.Task) string { return t.Priority.String() }},
+	{"type", func(t *models.Task) string { return string(t.Type) }},
+	{"parent", func(t *models.Task) string { return t.ParentID }},
+	{"project", func(t *models.Task) string { return t.ProjectID }},
+	{"key", func(t *models.Task) string { return t.Key }},
+	{"cloned_from", func(t *models.Task) string { return t.ClonedFrom }},
+}
+
+// historyRepository is a TaskRepository that records an activity for every
+// change it stores to a tracked field, whichever service made it.
+type historyRepository struct {
+	TaskRepository
+	activities ActivityRepository
+	// pending holds the activities of a transaction until it commits.
+	pending *[]*models.Activity
+}
+
+// RecordTaskHistory wraps a task repository so that changes made through it
+// are recorded in activities.
+func RecordTaskHistory(repository TaskRepository, activities ActivityRepository) TaskRepository {
+	return &historyRepository{TaskRepository: repository, activities: activities}
+}
+
+func (r *historyRepository) Create(task *models.Task) error {
+	if err := r.TaskRepository.Create(task); err != nil {
+		return err
+	}
+	return r.record(&models.Task{}, task, models.ActivityCreated)
+}
+
+// Update records moving a task to the trash as its deletion, and restoring
+// it as its creation, besides the changes to its tracked fields.
+func (r *historyRepository) Update(task *models.Task) error {
+	previous, err := r.previous(task.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.TaskRepository.Update(task); err != nil {
+		return err
+	}
+
+	switch {
+	case previous.DeletedAt == nil && task.DeletedAt != nil:
+		return r.recordLifecycle(task, models.ActivityDeleted)
+	case previous.DeletedAt != nil && task.DeletedAt == nil:
+		if err := r.recordLifecycle(task, models.ActivityCreated); err != nil {
+			return err
+		}
+	}
+	return r.record(previous, task, models.ActivityUpdated)
+}
+
+func (r *historyRepository) SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error) {
+	previous, err := r.TaskRepository.GetByID(taskID)
+	if err != nil {
+		return false, err
+	}
+	ok, err := r.TaskRepository.SetPosition(taskID, expected, status, rank)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	updated := *previous
+	updated.Status = status
+	return true, r.record(previous, &updated, models.ActivityUpdated)
+}
+
+// Delete records the deletion of a task, unless it was recorded when the
+// task went to the trash.
+func (r *historyRepository) Delete(id string) error {
+	previous, err := r.previous(id)
+	if err != nil {
+		return err
+	}
+	if err := r.TaskRepository.Delete(id); err != nil {
+		return err
+	}
+	if previous.DeletedAt != nil {
+		return nil
+	}
+	return r.recordLifecycle(previous, models.ActivityDeleted)
+}
+
+// WithTransaction records the activities of the transaction only once it
+// commits.
+func (r *historyRepository) WithTransaction(fn func(repository TaskRepository) error) error {
+	var pending []*models.Activity
+	err := r.TaskRepository.WithTransaction(func(repository TaskRepository) error {
+		pending = nil
+		return fn(&historyRepository{TaskRepository: repository, activities: r.activities, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+	for _, activity := range pending {
+		if err := r.activities.Create(activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previous returns the stored state of a task, in the trash or not.
+func (r *historyRepository) previous(id string) (*models.Task, error) {
+	if task, err := r.TaskRepository.GetByID(id); err == nil {
+		return task, nil
+	}
+	return r.TaskRepository.GetTrashed(id)
+}
+
+func (r *historyRepository) recordLifecycle(task *models.Task, action models.ActivityAction) error {
+	return r.create(&models.Activity{
+		ID:        generateID(),
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		Action:    action,
+		At:        time.Now(),
+	})
+}
+
+func (r *historyRepository) record(before, after *models.Task, action models.ActivityAction) error {
+	now := time.Now()
+	for _, field := range trackedFields {
+		from, to := field.value(before), field.value(after)
+		if from == to {
+			continue
+		}
+		err := r.create(&models.Activity{
+			ID:        generateID(),
+			TaskID:    after.ID,
+			ProjectID: after.ProjectID,
+			Action:    action,
+			Field:     field.name,
+			From:      from,
+			To:        to,
+			At:        now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *historyRepository) create(activity *models.Activity) error {
+	if r.pending != nil {
+		*r.pending = append(*r.pending, activity)
+		return nil
+	}
+	return r.activities.Create(activity)
+}
+
+type ActivityService struct {
+	repository     ActivityRepository
+	taskRepository TaskRepository
+}
+
+func NewActivityService(repository ActivityRepository, taskRepository TaskRepository) *ActivityService {
+	return &ActivityService{
+		repository:     repository,
+		taskRepository: taskRepository,
+	}
+}
+
+// TaskHistory returns the recorded changes of a task, oldest first, along
+// with the task.
+func (s *ActivityService) TaskHistory(idOrKey string) (*models.Task, []*models.Activity, error) {
+	task, err := findTask(s.taskRepository, idOrKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activities, err := s.repository.ListByTasks([]string{task.ID})
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, activities, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"fmt"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+)
+
+// checkWritable fails with ErrProjectArchived when a project, in the trash or
+// not, is archived.
+func checkWritable(projects ProjectRepository, projectID string) error {
+	project, err := projects.GetByID(projectID)
+	if err != nil {
+		if project, err = projects.GetTrashed(projectID); err != nil {
+			return err
+		}
+	}
+	if project.ArchivedAt != nil {
+		return fmt.Errorf("%w: unarchive %s to change it", ErrProjectArchived, project.Name)
+	}
+	return nil
+}
+
+// archivedTaskGuard is a TaskRepository that refuses to change the tasks of
+// archived projects, whichever service tries. Deletes still go through, so
+// that purging the trash is never blocked.
+type archivedTaskGuard struct {
+	TaskRepository
+	projects ProjectRepository
+}
+
+// GuardArchivedTasks wraps a task repository so that the tasks of archived
+// projects cannot be created, changed or moved through it.
+func GuardArchivedTasks(repository TaskRepository, projects ProjectRepository) TaskRepository {
+	return &archivedTaskGuard{TaskRepository: repository, projects: projects}
+}
+
+func (r *archivedTaskGuard) Create(task *models.Task) error {
+	if err := checkWritable(r.projects, task.ProjectID); err != nil {
+		return err
+	}
+	return r.TaskRepository.Create(task)
+}
+
+// Update checks the project a task is in as well as the one it moves to.
+func (r *archivedTaskGuard) Update(task *models.Task) error {
+	stored, err := r.TaskRepository.GetByID(task.ID)
+	if err != nil {
+		if stored, err = r.TaskRepository.GetTrashed(task.ID); err != nil {
+			return err
+		}
+	}
+	if err := checkWritable(r.projects, stored.ProjectID); err != nil {
+		return err
+	}
+	if task.ProjectID != stored.ProjectID {
+		if err := checkWritable(r.projects, task.ProjectID); err != nil {
+			return err
+		}
+	}
+	return r.TaskRepository.Update(task)
+}
+
+// RemoveLabel, RemoveCustomField and RemoveTeam change every task carrying
+// what they remove, so they fail if any of those tasks is in an archived
+// project.
+func (r *archivedTaskGuard) RemoveLabel(labelID string) error {
+	if err := r.checkMatching(query.Compare("label", query.OpIn, query.Value{Text: labelID})); err != nil {
+		return err
+	}
+	return r.TaskRepository.RemoveLabel(labelID)
+}
+
+func (r *archivedTaskGuard) RemoveCustomField(fieldID string) error {
+	// The field's type is unknown here, so look for a value of any type
+	set := func(kind query.FieldKind) query.Expr {
+		return query.Comparison{Field: query.CustomField(fieldID, kind), Op: query.OpNotEmpty}
+	}
+	where := query.Or{
+		Left:  query.Or{Left: set(query.KindText), Right: set(query.KindDecimal)},
+		Right: query.Or{Left: set(query.KindTime), Right: set(query.KindList)},
+	}
+	if err := r.checkMatching(where); err != nil {
+		return err
+	}
+	return r.TaskRepository.RemoveCustomField(fieldID)
+}
+
+func (r *archivedTaskGuard) RemoveTeam(teamID string) error {
+	if err := r.checkMatching(query.Compare("team", query.OpEqual, query.Value{Text: teamID})); err != nil {
+		return err
+	}
+	return r.TaskRepository.RemoveTeam(teamID)
+}
+
+func (r *archivedTaskGuard) SetRank(taskID, expected, rank string) (bool, error) {
+	if err := r.checkTask(taskID); err != nil {
+		return false, err
+	}
+	return r.TaskRepository.SetRank(taskID, expected, rank)
+}
+
+func (r *archivedTaskGuard) SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error) {
+	if err := r.checkTask(taskID); err != nil {
+		return false, err
+	}
+	return r.TaskRepository.SetPosition(taskID, expected, status, rank)
+}
+
+func (r *archivedTaskGuard) WithTransaction(fn func(repository TaskRepository) error) error {
+	return r.TaskRepository.WithTransaction(func(repository TaskRepository) error {
+		return fn(&archivedTaskGuard{TaskRepository: repository, projects: r.projects})
+	})
+}
+
+func (r *archivedTaskGuard) checkTask(taskID string) error {
+	task, err := r.TaskRepository.GetByID(taskID)
+	if err != nil {
+		return err
+	}
+	return checkWritable(r.projects, task.ProjectID)
+}
+
+// checkMatching checks the projects of the tasks matching where.
+func (r *archivedTaskGuard) checkMatching(where query.Expr) error {
+	tasks, err := r.TaskRepository.ListByQuery(&query.Query{Where: where})
+	if err != nil {
+		return err
+	}
+	checked := make(map[string]bool)
+	for _, task := range tasks {
+		if checked[task.ProjectID] {
+			continue
+		}
+		checked[task.ProjectID] = true
+		if err := checkWritable(r.projects, task.ProjectID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archivedSprintGuard is a SprintRepository that refuses to change the
+// sprints of archived projects.
+type archivedSprintGuard struct {
+	SprintRepository
+	projects ProjectRepository
+}
+
+// GuardArchivedSprints wraps a sprint repository so that the sprints of
+// archived projects cannot be created, changed or deleted through it.
+func GuardArchivedSprints(repository SprintRepository, projects ProjectRepository) SprintRepository {
+	return &archivedSprintGuard{SprintRepository: repository, projects: projects}
+}
+
+func (r *archivedSprintGuard) Create(sprint *models.Sprint) error {
+	if err := checkWritable(r.projects, sprint.ProjectID); err != nil {
+		return err
+	}
+	return r.SprintRepository.Create(sprint)
+}
+
+func (r *archivedSprintGuard) Update(sprint *models.Sprint) error {
+	if err := checkWritable(r.projects, sprint.ProjectID); err != nil {
+		return err
+	}
+	return r.SprintRepository.Update(sprint)
+}
+
+func (r *archivedSprintGuard) Delete(id string) error {
+	sprint, err := r.SprintRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(r.projects, sprint.ProjectID); err != nil {
+		return err
+	}
+	return r.SprintRepository.Delete(id)
+}
@@ -0,0 +1,470 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/rank"
+)
+
+var ErrWIPLimitExceeded = errors.New("column is at its WIP limit")
+
+// defaultBoardColumns is the board of a project that has not configured one:
+// a column per status, in workflow order.
+var defaultBoardColumns = []models.BoardColumn{
+	{Name: "To Do", Statuses: []models.TaskStatus{models.ToDo}},
+	{Name: "Ready for Implementation", Statuses: []models.TaskStatus{models.ReadyForImplementation}},
+	{Name: "In Progress", Statuses: []models.TaskStatus{models.InProgress}},
+	{Name: "Done", Statuses: []models.TaskStatus{models.Done}},
+}
+
+// SwimlaneGrouping chooses how the rows of a board are split.
+type SwimlaneGrouping string
+
+const (
+	SwimlanesNone     SwimlaneGrouping = ""
+	SwimlanesAssignee SwimlaneGrouping = "assignee"
+	SwimlanesPriority SwimlaneGrouping = "priority"
+	SwimlanesEpic     SwimlaneGrouping = "epic"
+)
+
+// Board is a project's kanban board. Without swimlanes each column lists its
+// tasks; with them, the tasks are in the swimlane cells instead and the
+// columns only carry their totals.
+type Board struct {
+	ProjectID string           `json:"project_id"`
+	Columns   []BoardColumn    `json:"columns"`
+	Swimlanes []Swimlane       `json:"swimlanes,omitempty"`
+	GroupedBy SwimlaneGrouping `json:"grouped_by,omitempty"`
+}
+
+type BoardColumn struct {
+	models.BoardColumn
+	Count     int            `json:"count"`
+	OverLimit bool           `json:"over_limit"`
+	Tasks     []*models.Task `json:"tasks,omitempty"`
+}
+
+// Swimlane is a row of the board. Cells holds its tasks column by column, in
+// rank order. The lane of tasks with no assignee, priority or epic has an
+// empty key.
+type Swimlane struct {
+	Key   string           `json:"key"`
+	Name  string           `json:"name"`
+	Cells [][]*models.Task `json:"cells"`
+}
+
+// CardMove says where a task goes on the board: into Column, just before
+// BeforeID or just after AfterID, or at the bottom of the column when neither
+// is given.
+type CardMove struct {
+	Column           string
+	BeforeID         string
+	AfterID          string
+	OverrideBlockers bool
+}
+
+type BoardService struct {
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+}
+
+func NewBoardService(taskRepository TaskRepository, projectRepository ProjectRepository) *BoardService {
+	return &BoardService{
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+func boardColumns(project *models.Project) []models.BoardColumn {
+	if len(project.BoardColumns) == 0 {
+		return defaultBoardColumns
+	}
+	return project.BoardColumns
+}
+
+// ConfigureBoard replaces a project's board columns. Every status must be in
+// exactly one column, so that no task drops off the board.
+func (s *BoardService) ConfigureBoard(projectID string, columns []models.BoardColumn) (*models.Project, error) {
+	if err := checkWritable(s.projectRepository, projectID); err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBoardColumns(columns); err != nil {
+		return nil, err
+	}
+
+	project.BoardColumns = columns
+	if err := s.projectRepository.Update(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func validateBoardColumns(columns []models.BoardColumn) error {
+	names := map[string]bool{}
+	mapped := map[models.TaskStatus]bool{}
+	for _, column := range columns {
+		if column.Name == "" {
+			return errors.New("board columns need a name")
+		}
+		if names[column.Name] {
+			return fmt.Errorf("duplicate column %q", column.Name)
+		}
+		names[column.Name] = true
+
+		if len(column.Statuses) == 0 {
+			return fmt.Errorf("column %q has no statuses", column.Name)
+		}
+		if column.WIPLimit < 0 {
+			return fmt.Errorf("column %q has a negative WIP limit", column.Name)
+		}
+		for _, status := range column.Statuses {
+			if !status.Valid() {
+				return fmt.Errorf("invalid status %q", status)
+			}
+			if mapped[status] {
+				return fmt.Errorf("status %q is in more than one column", status)
+			}
+			mapped[status] = true
+		}
+	}
+	for _, column := range defaultBoardColumns {
+		for _, status := range column.Statuses {
+			if !mapped[status] {
+				return fmt.Errorf("status %q is in no column", status)
+			}
+		}
+	}
+	return nil
+}
+
+// GetBoard returns a project's board, with its tasks ordered by rank within
+// each column. Epics are not shown, only grouped by.
+func (s *BoardService) GetBoard(projectID string, grouping SwimlaneGrouping) (*Board, error) {
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepository.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sortByRank(tasks)
+
+	columns := boardColumns(project)
+	columnOf := map[models.TaskStatus]int{}
+	for i, column := range columns {
+		for _, status := range column.Statuses {
+			columnOf[status] = i
+		}
+	}
+
+	board := &Board{ProjectID: projectID, Columns: make([]BoardColumn, len(columns)), GroupedBy: grouping}
+	for i, column := range columns {
+		board.Columns[i] = BoardColumn{BoardColumn: column}
+		if grouping == SwimlanesNone {
+			board.Columns[i].Tasks = make([]*models.Task, 0)
+		}
+	}
+
+	lanes, err := newSwimlanes(grouping, tasks, len(columns))
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		i, ok := columnOf[task.Status]
+		if !ok || task.Type == models.TypeEpic {
+			continue
+		}
+
+		column := &board.Columns[i]
+		column.Count++
+		if grouping == SwimlanesNone {
+			column.Tasks = append(column.Tasks, task)
+			continue
+		}
+		lane := lanes.laneOf(task)
+		lane.Cells[i] = append(lane.Cells[i], task)
+	}
+	for i := range board.Columns {
+		column := &board.Columns[i]
+		column.OverLimit = column.WIPLimit > 0 && column.Count > column.WIPLimit
+	}
+	if grouping != SwimlanesNone {
+		board.Swimlanes = lanes.ordered()
+	}
+	return board, nil
+}
+
+// swimlanes collects the lanes of a board in the order they should be shown.
+type swimlanes struct {
+	grouping SwimlaneGrouping
+	columns  int
+	tasks    map[string]*models.Task
+	lanes    map[string]*Swimlane
+	order    []string
+}
+
+func newSwimlanes(grouping SwimlaneGrouping, tasks []*models.Task, columns int) (*swimlanes, error) {
+	switch grouping {
+	case SwimlanesNone, SwimlanesAssignee, SwimlanesPriority, SwimlanesEpic:
+	default:
+		return nil, fmt.Errorf("invalid swimlanes %q: use assignee, priority or epic", grouping)
+	}
+
+	l := &swimlanes{
+		grouping: grouping,
+		columns:  columns,
+		tasks:    make(map[string]*models.Task, len(tasks)),
+		lanes:    map[string]*Swimlane{},
+	}
+	for _, task := range tasks {
+		l.tasks[task.ID] = task
+	}
+	if grouping == SwimlanesPriority {
+		for p := models.PriorityHighest; p >= models.PriorityLowest; p-- {
+			l.lane(p.String(), p.String())
+		}
+	}
+	return l, nil
+}
+
+func (l *swimlanes) lane(key, name string) *Swimlane {
+	if lane, ok := l.lanes[key]; ok {
+		return lane
+	}
+	lane := &Swimlane{Key: key, Name: name, Cells: make([][]*models.Task, l.columns)}
+	for i := range lane.Cells {
+		lane.Cells[i] = make([]*models.Task, 0)
+	}
+	l.lanes[key] = lane
+	l.order = append(l.order, key)
+	return lane
+}
+
+func (l *swimlanes) laneOf(task *models.Task) *Swimlane {
+	switch l.grouping {
+	case SwimlanesAssignee:
+		if task.AssigneeID == "" {
+			return l.lane("", "Unassigned")
+		}
+		return l.lane(task.AssigneeID, task.AssigneeID)
+	case SwimlanesPriority:
+		if task.Priority == models.PriorityNone {
+			return l.lane("", "No priority")
+		}
+		return l.lane(task.Priority.String(), task.Priority.String())
+	}
+
+	if epic := l.epicOf(task); epic != nil {
+		return l.lane(epic.ID, epic.Title)
+	}
+	return l.lane("", "No epic")
+}
+
+// epicOf walks up from task to the epic it belongs to, if any.
+func (l *swimlanes) epicOf(task *models.Task) *models.Task {
+	seen := map[string]bool{}
+	for current := l.tasks[task.ParentID]; current != nil && !seen[current.ID]; current = l.tasks[current.ParentID] {
+		if current.Type == models.TypeEpic {
+			return current
+		}
+		seen[current.ID] = true
+	}
+	return nil
+}
+
+// ordered returns the lanes that hold tasks, the lane without a key last.
+func (l *swimlanes) ordered() []Swimlane {
+	keys := slices.DeleteFunc(slices.Clone(l.order), func(key string) bool { return key == "" })
+	keys = append(keys, "")
+
+	result := make([]Swimlane, 0, len(keys))
+	for _, key := range keys {
+		lane, ok := l.lanes[key]
+		if ok && slices.ContainsFunc(lane.Cells, func(c []*models.Task) bool { return len(c) > 0 }) {
+			result = append(result, *lane)
+		}
+	}
+	return result
+}
+
+// MoveCard moves a task into a column and to a position within it, changing
+// its status and rank in a single write. A task entering a column keeps its
+// status if the column holds it and otherwise takes the column's first
+// status. Moves that would take a column over its WIP limit are rejected;
+// the limit is checked again after the write and the move undone if a
+// concurrent move got there first.
+func (s *BoardService) MoveCard(projectID, taskID string, move CardMove) (*models.Task, error) {
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID != projectID {
+		return nil, errors.New("task belongs to another project")
+	}
+
+	columns := boardColumns(project)
+	i := slices.IndexFunc(columns, func(c models.BoardColumn) bool { return c.Name == move.Column })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown column %q", move.Column)
+	}
+	column := columns[i]
+
+	var anchor *models.Task
+	if move.BeforeID != "" || move.AfterID != "" {
+		if move.BeforeID != "" && move.AfterID != "" {
+			return nil, errors.New("give either a task to move before or a task to move after")
+		}
+		anchor, err = findTask(s.taskRepository, move.BeforeID+move.AfterID)
+		if err != nil {
+			return nil, errors.New("anchor task not found")
+		}
+		if anchor.ID == task.ID {
+			return nil, errors.New("a task cannot be moved relative to itself")
+		}
+		if anchor.ProjectID != projectID {
+			return nil, errors.New("anchor task belongs to another project")
+		}
+	}
+
+	previous := task.Status
+	status := previous
+	entering := !slices.Contains(column.Statuses, previous)
+	if entering {
+		status = column.Statuses[0]
+	}
+	if status == models.InProgress && previous != models.InProgress && !move.OverrideBlockers {
+		if err := checkBlockers(s.taskRepository, task); err != nil {
+			return nil, err
+		}
+	}
+	if status == models.Done && previous != models.Done {
+		if err := checkChecklist(s.projectRepository, task); err != nil {
+			return nil, err
+		}
+	}
+	if entering && column.WIPLimit > 0 {
+		count, err := s.columnCount(projectID, column)
+		if err != nil {
+			return nil, err
+		}
+		if count >= column.WIPLimit {
+			return nil, fmt.Errorf("%w: %s allows %d", ErrWIPLimitExceeded, column.Name, column.WIPLimit)
+		}
+	}
+
+	limitChecked := !entering || column.WIPLimit == 0
+	for range maxRankAttempts {
+		current, err := s.taskRepository.GetByID(task.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := s.cardRank(current, column, anchor, move.BeforeID != "")
+		if errors.Is(err, rank.ErrNoRoom) {
+			if err := rebalanceRanks(s.taskRepository, projectID); err != nil && !errors.Is(err, ErrRankConflict) {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := s.taskRepository.SetPosition(current.ID, current.Rank, status, r)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		previousRank := current.Rank
+		current.Status, current.Rank = status, r
+
+		if !limitChecked {
+			count, err := s.columnCount(projectID, column)
+			if err != nil {
+				return nil, err
+			}
+			if count > column.WIPLimit {
+				if _, err := s.taskRepository.SetPosition(current.ID, r, previous, previousRank); err != nil {
+					return nil, err
+				}
+				return nil, fmt.Errorf("%w: %s allows %d", ErrWIPLimitExceeded, column.Name, column.WIPLimit)
+			}
+			limitChecked = true
+		}
+
+		if anchor == nil {
+			return current, nil
+		}
+		moved, err := anchorMoved(s.taskRepository, anchor)
+		if err != nil {
+			return nil, err
+		}
+		if !moved {
+			return current, nil
+		}
+		if anchor, err = s.taskRepository.GetByID(anchor.ID); err != nil {
+			return nil, errors.New("anchor task not found")
+		}
+	}
+	return nil, ErrRankConflict
+}
+
+// cardRank returns the rank a task should take in column: next to anchor if
+// given, otherwise after the column's last task. A task dropped into an
+// empty column keeps its rank.
+func (s *BoardService) cardRank(task *models.Task, column models.BoardColumn, anchor *models.Task, before bool) (string, error) {
+	if anchor != nil {
+		return rankBeside(s.taskRepository, anchor, before)
+	}
+
+	tasks, err := s.taskRepository.ListByProject(task.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	sortByRank(tasks)
+
+	var last *models.Task
+	for _, t := range tasks {
+		if t.ID != task.ID && t.Type != models.TypeEpic && slices.Contains(column.Statuses, t.Status) {
+			last = t
+		}
+	}
+	if last == nil {
+		if task.Rank == "" {
+			return "", rank.ErrNoRoom
+		}
+		return task.Rank, nil
+	}
+	return rankBeside(s.taskRepository, last, false)
+}
+
+// columnCount counts the tasks shown in a column.
+func (s *BoardService) columnCount(projectID string, column models.BoardColumn) (int, error) {
+	tasks, err := s.taskRepository.ListByProject(projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, task := range tasks {
+		if task.Type != models.TypeEpic && slices.Contains(column.Statuses, task.Status) {
+			count++
+		}
+	}
+	return count, nil
+}
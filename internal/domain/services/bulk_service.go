@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// maxBulkTasks bounds the tasks a single bulk request may change.
+const maxBulkTasks = 500
+
+// errBulkRolledBack aborts the transaction of an atomic bulk request once one
+// of its tasks fails.
+var errBulkRolledBack = errors.New("rolled back because other tasks failed")
+
+type BulkAction string
+
+const (
+	BulkUpdate        BulkAction = "update"
+	BulkSetStatus     BulkAction = "set_status"
+	BulkAssignSprint  BulkAction = "assign_sprint"
+	BulkMoveToBacklog BulkAction = "move_to_backlog"
+	BulkAddLabel      BulkAction = "add_label"
+	BulkDelete        BulkAction = "delete"
+)
+
+// BulkOperation is one change a bulk request makes to each of its tasks.
+// Update applies the field changes of a BulkUpdate to a task.
+type BulkOperation struct {
+	Action   BulkAction
+	Update   func(task *models.Task) error
+	Status   models.TaskStatus
+	SprintID string
+	LabelID  string
+}
+
+// BulkRequest picks tasks by ID or key, or with a query, and the operations
+// to apply to each of them, in order. An atomic request changes every task or
+// none of them.
+type BulkRequest struct {
+	TaskIDs          []string
+	Query            string
+	Operations       []BulkOperation
+	Atomic           bool
+	OverrideBlockers bool
+}
+
+// BulkResult is the outcome of a bulk request for one task: the task as it
+// was left, unless deleted, or why it could not be changed.
+type BulkResult struct {
+	TaskID  string       `json:"task_id"`
+	Task    *models.Task `json:"task,omitempty"`
+	Deleted bool         `json:"deleted,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Results    []BulkResult `json:"results"`
+	Succeeded  int          `json:"succeeded"`
+	Failed     int          `json:"failed"`
+	RolledBack bool         `json:"rolled_back,omitempty"`
+}
+
+// BulkService applies the same operations to many tasks at once.
+type BulkService struct {
+	taskService    *TaskService
+	filterService  *FilterService
+	projectService *ProjectService
+}
+
+func NewBulkService(taskService *TaskService, filterService *FilterService, projectService *ProjectService) *BulkService {
+	return &BulkService{
+		taskService:    taskService,
+		filterService:  filterService,
+		projectService: projectService,
+	}
+}
+
+// Apply runs a bulk request on behalf of the caller, who must have access to
+// the project of every task it changes. Tasks are changed one after another,
+// each stopping at its first failing operation; with an atomic request, one
+// failing task rolls back the changes to all of them.
+func (s *BulkService) Apply(caller Caller, request BulkRequest) (*BulkResponse, error) {
+	if err := validateBulkOperations(request.Operations); err != nil {
+		return nil, err
+	}
+	taskIDs, err := s.targets(caller, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *BulkResponse
+	run := func(tx *TaskService) error {
+		response = &BulkResponse{Results: make([]BulkResult, 0, len(taskIDs))}
+		for _, id := range taskIDs {
+			result := BulkResult{TaskID: id}
+			task, err := s.applyOne(tx, caller, id, request)
+			if err != nil {
+				result.Error = err.Error()
+				response.Failed++
+			} else {
+				result.Task = task
+				result.Deleted = task == nil
+				response.Succeeded++
+			}
+			response.Results = append(response.Results, result)
+		}
+		if request.Atomic && response.Failed > 0 {
+			return errBulkRolledBack
+		}
+		return nil
+	}
+
+	if !request.Atomic {
+		return response, run(s.taskService)
+	}
+
+	err = s.taskService.InTransaction(run)
+	if errors.Is(err, errBulkRolledBack) {
+		response.RolledBack = true
+		for i, result := range response.Results {
+			if result.Error == "" {
+				response.Results[i] = BulkResult{TaskID: result.TaskID, Error: errBulkRolledBack.Error()}
+			}
+		}
+		response.Failed += response.Succeeded
+		response.Succeeded = 0
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// targets returns the IDs or keys of the tasks a request applies to.
+func (s *BulkService) targets(caller Caller, request BulkRequest) ([]string, error) {
+	if (len(request.TaskIDs) == 0) == (request.Query == "") {
+		return nil, errors.New("give either task IDs or a query")
+	}
+
+	taskIDs := request.TaskIDs
+	if request.Query != "" {
+		tasks, err := s.filterService.Search(caller, request.Query)
+		if err != nil {
+			return nil, err
+		}
+		taskIDs = make([]string, len(tasks))
+		for i, task := range tasks {
+			taskIDs[i] = task.ID
+		}
+	}
+
+	seen := make(map[string]bool, len(taskIDs))
+	taskIDs = slices.DeleteFunc(slices.Clone(taskIDs), func(id string) bool {
+		duplicate := seen[id]
+		seen[id] = true
+		return duplicate
+	})
+	if len(taskIDs) > maxBulkTasks {
+		return nil, fmt.Errorf("a bulk request can change at most %d tasks, not %d", maxBulkTasks, len(taskIDs))
+	}
+	return taskIDs, nil
+}
+
+// applyOne applies the operations of a request to one task, and returns the
+// task as they left it, or nil once deleted.
+func (s *BulkService) applyOne(tx *TaskService, caller Caller, id string, request BulkRequest) (*models.Task, error) {
+	task, err := tx.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projectService.CheckAccess(caller, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	opts := UpdateOptions{OverrideBlockers: request.OverrideBlockers}
+	for _, op := range request.Operations {
+		task, err = tx.GetTask(task.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Action {
+		case BulkUpdate:
+			if err := op.Update(task); err != nil {
+				return nil, err
+			}
+			err = tx.UpdateTask(task, opts)
+		case BulkSetStatus:
+			task.Status = op.Status
+			err = tx.UpdateTask(task, opts)
+		case BulkAssignSprint:
+			_, err = tx.AssignToSprint(task.ID, op.SprintID)
+		case BulkMoveToBacklog:
+			err = tx.MoveToBacklog(task.ID)
+		case BulkAddLabel:
+			task.LabelIDs = append(task.LabelIDs, op.LabelID)
+			err = tx.UpdateTask(task, opts)
+		case BulkDelete:
+			return nil, tx.DeleteTask(caller.UserID, task.ID, OrphansRejected, "")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op.Action, err)
+		}
+	}
+	return tx.GetTask(task.ID)
+}
+
+func validateBulkOperations(operations []BulkOperation) error {
+	if len(operations) == 0 {
+		return errors.New("at least one operation is required")
+	}
+	for i, op := range operations {
+		switch op.Action {
+		case BulkUpdate:
+			if op.Update == nil {
+				return errors.New("update needs the fields to change")
+			}
+		case BulkSetStatus:
+			if !op.Status.Valid() {
+				return fmt.Errorf("invalid status %q", op.Status)
+			}
+		case BulkAssignSprint:
+			if op.SprintID == "" {
+				return errors.New("assign_sprint needs a sprint ID")
+			}
+		case BulkAddLabel:
+			if op.LabelID == "" {
+				return errors.New("add_label needs a label ID")
+			}
+		case BulkMoveToBacklog:
+		case BulkDelete:
+			if i != len(operations)-1 {
+				return errors.New("delete must be the last operation")
+			}
+		default:
+			return fmt.Errorf("unknown operation %q", op.Action)
+		}
+	}
+	return nil
+}
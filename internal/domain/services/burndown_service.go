@@ -0,0 +1,383 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// SnapshotRepository stores one snapshot per sprint and day.
+type SnapshotRepository interface {
+	// Upsert stores a snapshot, replacing any for the same sprint and day.
+	Upsert(snapshot *models.SprintSnapshot) error
+	ListBySprint(sprintID string) ([]*models.SprintSnapshot, error)
+}
+
+// Burndown is the day-by-day progress of a sprint against an ideal line.
+type Burndown struct {
+	SprintID  string        `json:"sprint_id"`
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	Days      []BurndownDay `json:"days"`
+}
+
+// BurndownDay holds the ideal remaining work at the end of a day and, for
+// days that have passed, the actual snapshot. The ideal line starts from the
+// scope at the start of the sprint and absorbs scope changes: work added or
+// removed on a day is spread evenly over the days left.
+type BurndownDay struct {
+	Date        time.Time              `json:"date"`
+	IdealPoints float64                `json:"ideal_points"`
+	IdealTasks  float64                `json:"ideal_tasks"`
+	Actual      *models.SprintSnapshot `json:"actual,omitempty"`
+}
+
+type BurndownService struct {
+	sprintRepository   SprintRepository
+	taskRepository     TaskRepository
+	activityRepository ActivityRepository
+	snapshotRepository SnapshotRepository
+}
+
+func NewBurndownService(sprintRepository SprintRepository, taskRepository TaskRepository, activityRepository ActivityRepository, snapshotRepository SnapshotRepository) *BurndownService {
+	return &BurndownService{
+		sprintRepository:   sprintRepository,
+		taskRepository:     taskRepository,
+		activityRepository: activityRepository,
+		snapshotRepository: snapshotRepository,
+	}
+}
+
+// RecordSnapshots stores today's snapshot of every active sprint. It is meant
+// to run periodically; the last run of a day leaves that day's snapshot.
+func (s *BurndownService) RecordSnapshots() error {
+	sprints, err := s.sprintRepository.ListByStatus(models.SprintActive)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sprint := range sprints {
+		if err := s.recordSnapshot(sprint, time.Now()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recordSnapshot stores the current state of a sprint's tasks as the
+// snapshot of the day of at.
+func (s *BurndownService) recordSnapshot(sprint *models.Sprint, at time.Time) error {
+	tasks, err := s.taskRepository.ListBySprint(sprint.ID)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &models.SprintSnapshot{SprintID: sprint.ID, Date: day(at)}
+	for _, task := range tasks {
+		points := 0
+		if task.StoryPoints != nil {
+			points = *task.StoryPoints
+		}
+		countTask(snapshot, points, task.Status == models.Done)
+	}
+	return s.snapshotRepository.Upsert(snapshot)
+}
+
+// Burndown returns the burndown of a sprint. Days without a stored snapshot
+// are computed from the task activity history.
+func (s *BurndownService) Burndown(sprintID string) (*Burndown, error) {
+	sprint, err := s.sprintRepository.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.loadHistory(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.snapshotRepository.ListBySprint(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[time.Time]*models.SprintSnapshot, len(stored))
+	for _, snapshot := range stored {
+		byDate[day(snapshot.Date)] = snapshot
+	}
+
+	return buildBurndown(sprint, history, func(date, cutoff time.Time) *models.SprintSnapshot {
+		if snapshot, ok := byDate[date]; ok {
+			return snapshot
+		}
+		return history.snapshot(sprint.ID, date, cutoff)
+	}), nil
+}
+
+// RebuildBurndown recomputes every past snapshot of a sprint from the task
+// activity history, replacing the stored ones, and returns the result.
+func (s *BurndownService) RebuildBurndown(sprintID string) (*Burndown, error) {
+	sprint, err := s.sprintRepository.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.loadHistory(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	burndown := buildBurndown(sprint, history, func(date, cutoff time.Time) *models.SprintSnapshot {
+		return history.snapshot(sprint.ID, date, cutoff)
+	})
+	for _, d := range burndown.Days {
+		if d.Actual == nil {
+			continue
+		}
+		if err := s.snapshotRepository.Upsert(d.Actual); err != nil {
+			return nil, err
+		}
+	}
+	return burndown, nil
+}
+
+// sprintTotals returns the snapshots of a sprint's scope when it started
+// and when it closed, replayed from the task activity history. With a
+// teamID, only the tasks assigned to that team at each point are counted.
+func (s *BurndownService) sprintTotals(sprint *models.Sprint, teamID string) (start, end *models.SprintSnapshot, err error) {
+	history, err := s.loadHistory(sprint.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closedAt := time.Now()
+	if sprint.ClosedAt != nil {
+		closedAt = *sprint.ClosedAt
+	}
+	committedAt := commitmentTime(sprint)
+	if teamID != "" {
+		start = history.ofTeam(teamID, committedAt).snapshot(sprint.ID, day(sprint.StartDate), committedAt)
+		end = history.ofTeam(teamID, closedAt).snapshot(sprint.ID, day(closedAt), closedAt)
+		return start, end, nil
+	}
+	start = history.snapshot(sprint.ID, day(sprint.StartDate), committedAt)
+	end = history.snapshot(sprint.ID, day(closedAt), closedAt)
+	return start, end, nil
+}
+
+// buildBurndown lays out the days of a sprint, taking the actual snapshot of
+// each day that has passed from actual, given the day and the moment its
+// state should be read at.
+func buildBurndown(sprint *models.Sprint, history sprintHistory, actual func(date, cutoff time.Time) *models.SprintSnapshot) *Burndown {
+	burndown := &Burndown{
+		SprintID:  sprint.ID,
+		StartDate: sprint.StartDate,
+		EndDate:   sprint.EndDate,
+	}
+
+	end := time.Now()
+	if sprint.ClosedAt != nil && sprint.ClosedAt.Before(end) {
+		end = *sprint.ClosedAt
+	}
+	if sprint.Status == models.SprintCreated {
+		end = sprint.StartDate.Add(-time.Nanosecond)
+	}
+
+	for date := day(sprint.StartDate); !date.After(day(sprint.EndDate)); date = date.AddDate(0, 0, 1) {
+		d := BurndownDay{Date: date}
+		if !date.After(end) {
+			cutoff := date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			if cutoff.After(end) {
+				cutoff = end
+			}
+			d.Actual = actual(date, cutoff)
+		}
+		burndown.Days = append(burndown.Days, d)
+	}
+
+	start := history.snapshot(sprint.ID, day(sprint.StartDate), commitmentTime(sprint))
+	idealPoints, idealTasks := float64(start.ScopePoints), float64(start.ScopeTasks)
+	prev := start
+	n := len(burndown.Days)
+	for i := range burndown.Days {
+		d := &burndown.Days[i]
+		if d.Actual != nil {
+			idealPoints += float64(d.Actual.ScopePoints - prev.ScopePoints)
+			idealTasks += float64(d.Actual.ScopeTasks - prev.ScopeTasks)
+			prev = d.Actual
+		}
+		idealPoints -= idealPoints / float64(n-i)
+		idealTasks -= idealTasks / float64(n-i)
+		d.IdealPoints, d.IdealTasks = idealPoints, idealTasks
+	}
+	return burndown
+}
+
+// commitmentTime is when a sprint's scope counts as committed: its start
+// date, or when it was actually started if that was later.
+func commitmentTime(sprint *models.Sprint) time.Time {
+	if sprint.StartedAt != nil && sprint.StartedAt.After(sprint.StartDate) {
+		return *sprint.StartedAt
+	}
+	return sprint.StartDate
+}
+
+// loadHistory collects every task that is or ever was in a sprint, with its
+// activities.
+func (s *BurndownService) loadHistory(sprintID string) (sprintHistory, error) {
+	tasks, err := s.taskRepository.ListBySprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		current[task.ID] = task
+	}
+
+	ids, err := s.activityRepository.TaskIDsForSprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		task, err := s.taskRepository.GetByID(id)
+		if err != nil {
+			task = nil
+		}
+		current[id] = task
+	}
+
+	taskIDs := make([]string, 0, len(current))
+	for id := range current {
+		taskIDs = append(taskIDs, id)
+	}
+	activities, err := s.activityRepository.ListByTasks(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(sprintHistory, len(current))
+	for id, task := range current {
+		history[id] = &taskHistory{current: task}
+	}
+	for _, activity := range activities {
+		if h, ok := history[activity.TaskID]; ok {
+			h.activities = append(h.activities, activity)
+		}
+	}
+	for _, h := range history {
+		slices.SortStableFunc(h.activities, func(a, b *models.Activity) int {
+			return a.At.Compare(b.At)
+		})
+	}
+	return history, nil
+}
+
+// sprintHistory maps task IDs to their history.
+type sprintHistory map[string]*taskHistory
+
+// snapshot replays the history up to cutoff, as the snapshot of date.
+func (h sprintHistory) snapshot(sprintID string, date, cutoff time.Time) *models.SprintSnapshot {
+	snapshot := &models.SprintSnapshot{SprintID: sprintID, Date: date}
+	for _, task := range h {
+		if !task.existsAt(cutoff) || task.valueAt("sprint", cutoff) != sprintID {
+			continue
+		}
+		points, _ := strconv.Atoi(task.valueAt("story_points", cutoff))
+		countTask(snapshot, points, task.valueAt("status", cutoff) == string(models.Done))
+	}
+	return snapshot
+}
+
+// ofTeam returns the history of the tasks that were assigned to a team at t.
+func (h sprintHistory) ofTeam(teamID string, t time.Time) sprintHistory {
+	team := make(sprintHistory)
+	for id, task := range h {
+		if task.valueAt("team", t) == teamID {
+			team[id] = task
+		}
+	}
+	return team
+}
+
+type taskHistory struct {
+	// current is nil once the task is deleted.
+	current    *models.Task
+	activities []*models.Activity
+}
+
+// existsAt reports whether the task existed at t: as the last creation or
+// deletion by then left it, or else the opposite of the first later one, or
+// else as it is now. Tasks restored from the trash are created again.
+func (h *taskHistory) existsAt(t time.Time) bool {
+	var last *models.Activity
+	for _, activity := range h.activities {
+		if activity.Action != models.ActivityCreated && activity.Action != models.ActivityDeleted {
+			continue
+		}
+		if activity.At.After(t) {
+			if last == nil {
+				return activity.Action == models.ActivityDeleted
+			}
+			break
+		}
+		last = activity
+	}
+	if last != nil {
+		return last.Action == models.ActivityCreated
+	}
+	return h.current != nil
+}
+
+// valueAt returns the value of a tracked field at t: the last change made by
+// then, or else the value the first later change started from, or else the
+// current value, for tasks that predate the history.
+func (h *taskHistory) valueAt(field string, t time.Time) string {
+	var last *models.Activity
+	for _, activity := range h.activities {
+		if activity.Field != field {
+			continue
+		}
+		if activity.At.After(t) {
+			if last == nil {
+				return activity.From
+			}
+			break
+		}
+		last = activity
+	}
+	if last != nil {
+		return last.To
+	}
+
+	if h.current == nil {
+		return ""
+	}
+	for _, f := range trackedFields {
+		if f.name == field {
+			return f.value(h.current)
+		}
+	}
+	return ""
+}
+
+// countTask adds a task to a snapshot.
+func countTask(snapshot *models.SprintSnapshot, points int, done bool) {
+	snapshot.ScopePoints += points
+	snapshot.ScopeTasks++
+	if done {
+		snapshot.CompletedPoints += points
+		snapshot.CompletedTasks++
+	} else {
+		snapshot.RemainingPoints += points
+		snapshot.RemainingTasks++
+	}
+}
+
+// day returns the UTC day t falls on.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
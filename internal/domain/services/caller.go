@@ -0,0 +1,16 @@
+package services
+
+import "go-project-manager-backend/internal/domain/models"
+
+// Caller identifies the authenticated user a service call is made on behalf of,
+// and the organization they belong to; an empty TenantID is the default
+// workspace.
+type Caller struct {
+	UserID   string
+	Role     models.Role
+	TenantID string
+}
+
+func (c Caller) IsAdmin() bool {
+	return c.Role == models.Admin
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+var (
+	ErrChecklistIncomplete   = errors.New("required checklist items are unchecked")
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+)
+
+// ChecklistItemInput is the text of a checklist item and whether it must be
+// checked before its task is done.
+type ChecklistItemInput struct {
+	Text     string
+	Required bool
+}
+
+type ChecklistService struct {
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+}
+
+func NewChecklistService(taskRepository TaskRepository, projectRepository ProjectRepository) *ChecklistService {
+	return &ChecklistService{
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+// AddItem appends an unchecked item to a task's checklist.
+func (s *ChecklistService) AddItem(taskID string, input ChecklistItemInput) (*models.Task, error) {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := newChecklistItem(input)
+	if err != nil {
+		return nil, err
+	}
+	task.Checklist = append(task.Checklist, item)
+	return s.save(task)
+}
+
+// UpdateItem changes the text of a checklist item and whether it is
+// required. Its checked state is left alone.
+func (s *ChecklistService) UpdateItem(taskID, itemID string, input ChecklistItemInput) (*models.Task, error) {
+	task, i, err := s.findItem(taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(input.Text)
+	if text == "" {
+		return nil, errors.New("checklist item text is required")
+	}
+	task.Checklist[i].Text = text
+	task.Checklist[i].Required = input.Required
+	return s.save(task)
+}
+
+func (s *ChecklistService) DeleteItem(taskID, itemID string) (*models.Task, error) {
+	task, i, err := s.findItem(taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	task.Checklist = slices.Delete(task.Checklist, i, i+1)
+	return s.save(task)
+}
+
+// MoveItem moves a checklist item to position, counted from zero; positions
+// past the end move it last.
+func (s *ChecklistService) MoveItem(taskID, itemID string, position int) (*models.Task, error) {
+	task, i, err := s.findItem(taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if position < 0 {
+		return nil, errors.New("position must not be negative")
+	}
+	item := task.Checklist[i]
+	task.Checklist = slices.Delete(task.Checklist, i, i+1)
+	position = min(position, len(task.Checklist))
+	task.Checklist = slices.Insert(task.Checklist, position, item)
+	return s.save(task)
+}
+
+// CheckItem checks or unchecks a checklist item, recording who checked it
+// and when.
+func (s *ChecklistService) CheckItem(taskID, itemID, userID string, checked bool) (*models.Task, error) {
+	task, i, err := s.findItem(taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	item := &task.Checklist[i]
+	if item.Checked == checked {
+		return task, nil
+	}
+	item.Checked = checked
+	item.CheckedBy = ""
+	item.CheckedAt = nil
+	if checked {
+		now := time.Now()
+		item.CheckedBy = userID
+		item.CheckedAt = &now
+	}
+	return s.save(task)
+}
+
+// SetChecklistPolicy sets whether the tasks of a project can only be done
+// once their required checklist items are checked.
+func (s *ChecklistService) SetChecklistPolicy(projectID string, require bool) (*models.Project, error) {
+	if err := checkWritable(s.projectRepository, projectID); err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	project.RequireChecklist = require
+	project.UpdatedAt = time.Now()
+	if err := s.projectRepository.Update(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *ChecklistService) findItem(taskID, itemID string) (*models.Task, int, error) {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+	i := slices.IndexFunc(task.Checklist, func(item models.ChecklistItem) bool { return item.ID == itemID })
+	if i < 0 {
+		return nil, 0, ErrChecklistItemNotFound
+	}
+	return task, i, nil
+}
+
+func (s *ChecklistService) save(task *models.Task) (*models.Task, error) {
+	task.ChecklistProgress = checklistProgress(task.Checklist)
+	task.UpdatedAt = time.Now()
+	if err := s.taskRepository.Update(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func newChecklistItem(input ChecklistItemInput) (models.ChecklistItem, error) {
+	text := strings.TrimSpace(input.Text)
+	if text == "" {
+		return models.ChecklistItem{}, errors.New("checklist item text is required")
+	}
+	return models.ChecklistItem{ID: generateID(), Text: text, Required: input.Required}, nil
+}
+
+// checklistProgress counts the checked items of a checklist, or returns nil
+// for an empty one.
+func checklistProgress(checklist []models.ChecklistItem) *models.ChecklistProgress {
+	if len(checklist) == 0 {
+		return nil
+	}
+	progress := &models.ChecklistProgress{Items: len(checklist)}
+	for _, item := range checklist {
+		switch {
+		case item.Checked:
+			progress.Checked++
+		case item.Required:
+			progress.RequiredUnchecked++
+		}
+	}
+	progress.DoneRatio = float64(progress.Checked) / float64(progress.Items)
+	return progress
+}
+
+// checkChecklist fails with ErrChecklistIncomplete when task is in a project
+// that requires checklists and has required items unchecked.
+func checkChecklist(projects ProjectRepository, task *models.Task) error {
+	var unchecked []string
+	for _, item := range task.Checklist {
+		if item.Required && !item.Checked {
+			unchecked = append(unchecked, fmt.Sprintf("%q", item.Text))
+		}
+	}
+	if len(unchecked) == 0 {
+		return nil
+	}
+
+	project, err := projects.GetByID(task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !project.RequireChecklist {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrChecklistIncomplete, strings.Join(unchecked, ", "))
+}
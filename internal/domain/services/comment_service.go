@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/textsearch"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	GetByID(id string) (*models.Comment, error)
+	Update(comment *models.Comment) error
+	Delete(id string) error
+	ListByTask(taskID string) ([]*models.Comment, error)
+	// ListByIDs returns the comments with the given IDs, skipping missing
+	// ones, in no particular order.
+	ListByIDs(ids []string) ([]*models.Comment, error)
+	// SearchText matches comments on text, limited to the tasks of projectIDs
+	// unless it is nil.
+	SearchText(text string, projectIDs []string) ([]textsearch.Match, error)
+}
+
+type CommentService struct {
+	repository        CommentRepository
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+	publisher         EventPublisher
+}
+
+func NewCommentService(repository CommentRepository, taskRepository TaskRepository, projectRepository ProjectRepository, publisher EventPublisher) *CommentService {
+	return &CommentService{
+		repository:        repository,
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+		publisher:         publisher,
+	}
+}
+
+// AddComment adds a comment to a task and publishes it, for the users it
+// mentions, the task's assignee and earlier commenters to be notified.
+func (s *CommentService) AddComment(taskID, authorID, body string) (*models.Comment, error) {
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(s.projectRepository, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		ID:        generateID(),
+		TaskID:    task.ID,
+		AuthorID:  authorID,
+		Body:      body,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err = s.repository.Create(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(Event{
+		Type:        models.NotifyCommented,
+		Task:        task,
+		ActorID:     authorID,
+		CommentID:   comment.ID,
+		CommentBody: comment.Body,
+		At:          comment.CreatedAt,
+	})
+	return comment, nil
+}
+
+func (s *CommentService) GetComment(id string) (*models.Comment, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *CommentService) UpdateComment(caller Caller, id, body string) (*models.Comment, error) {
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+
+	comment, err := s.authoredComment(caller, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Body = body
+	comment.UpdatedAt = time.Now()
+	err = s.repository.Update(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (s *CommentService) DeleteComment(caller Caller, id string) error {
+	if _, err := s.authoredComment(caller, id); err != nil {
+		return err
+	}
+	return s.repository.Delete(id)
+}
+
+func (s *CommentService) ListComments(taskID string) ([]*models.Comment, error) {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.ListByTask(task.ID)
+}
+
+func (s *CommentService) authoredComment(caller Caller, id string) (*models.Comment, error) {
+	comment, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != caller.UserID && !caller.IsAdmin() {
+		return nil, errors.New("only the author can change a comment")
+	}
+	task, err := s.taskRepository.GetByID(comment.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(s.projectRepository, task.ProjectID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
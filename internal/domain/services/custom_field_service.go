@@ -0,0 +1,347 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+)
+
+// ErrInvalidCustomField wraps every error about custom field values, so
+// handlers can tell a bad value from a failing repository.
+var ErrInvalidCustomField = errors.New("invalid custom field")
+
+type CustomFieldRepository interface {
+	Create(field *models.CustomField) error
+	GetByID(id string) (*models.CustomField, error)
+	Update(field *models.CustomField) error
+	Delete(id string) error
+	ListByProject(projectID string) ([]*models.CustomField, error)
+}
+
+// CustomFieldDefinition describes a custom field. Default is a raw value, as
+// decoded from JSON, given to new tasks that do not set the field.
+type CustomFieldDefinition struct {
+	Name     string
+	Type     models.CustomFieldType
+	Required bool
+	Options  []string
+	Default  any
+}
+
+type CustomFieldService struct {
+	repository        CustomFieldRepository
+	taskRepository    TaskRepository
+	userRepository    UserRepository
+	projectRepository ProjectRepository
+}
+
+func NewCustomFieldService(repository CustomFieldRepository, taskRepository TaskRepository, userRepository UserRepository, projectRepository ProjectRepository) *CustomFieldService {
+	return &CustomFieldService{
+		repository:        repository,
+		taskRepository:    taskRepository,
+		userRepository:    userRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+func (s *CustomFieldService) CreateField(projectID string, definition CustomFieldDefinition) (*models.CustomField, error) {
+	if err := checkWritable(s.projectRepository, projectID); err != nil {
+		return nil, err
+	}
+	field := &models.CustomField{
+		ID:        generateID(),
+		ProjectID: projectID,
+		Type:      definition.Type,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.define(field, definition); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (s *CustomFieldService) GetField(id string) (*models.CustomField, error) {
+	return s.repository.GetByID(id)
+}
+
+// UpdateField redefines a custom field. Its type cannot change, since tasks
+// already hold values of that type; values no longer among the options of a
+// select field are kept until the task is edited.
+func (s *CustomFieldService) UpdateField(id string, definition CustomFieldDefinition) (*models.CustomField, error) {
+	field, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(s.projectRepository, field.ProjectID); err != nil {
+		return nil, err
+	}
+	if definition.Type != "" && definition.Type != field.Type {
+		return nil, errors.New("the type of a custom field cannot change")
+	}
+	if err := s.define(field, definition); err != nil {
+		return nil, err
+	}
+
+	field.UpdatedAt = time.Now()
+	if err := s.repository.Update(field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// DeleteField deletes a custom field and its values on every task.
+func (s *CustomFieldService) DeleteField(id string) error {
+	field, err := s.repository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(s.projectRepository, field.ProjectID); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(id); err != nil {
+		return err
+	}
+	return s.taskRepository.RemoveCustomField(id)
+}
+
+// ListFields returns the custom fields of a project, by name.
+func (s *CustomFieldService) ListFields(projectID string) ([]*models.CustomField, error) {
+	fields, err := s.repository.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sortCustomFields(fields)
+	return fields, nil
+}
+
+func (s *CustomFieldService) define(field *models.CustomField, definition CustomFieldDefinition) error {
+	field.Name = strings.TrimSpace(definition.Name)
+	if field.Name == "" {
+		return errors.New("custom field name is required")
+	}
+	switch field.Type {
+	case models.CustomFieldText, models.CustomFieldNumber, models.CustomFieldDate, models.CustomFieldUser:
+		if len(definition.Options) > 0 {
+			return errors.New("only select fields have options")
+		}
+	case models.CustomFieldSingleSelect, models.CustomFieldMultiSelect:
+		var options []string
+		for _, option := range definition.Options {
+			option = strings.TrimSpace(option)
+			if option == "" {
+				return errors.New("select options cannot be blank")
+			}
+			if slices.ContainsFunc(options, func(o string) bool { return strings.EqualFold(o, option) }) {
+				return fmt.Errorf("duplicate select option %q", option)
+			}
+			options = append(options, option)
+		}
+		if len(options) == 0 {
+			return errors.New("select fields need at least one option")
+		}
+		field.Options = options
+	default:
+		return fmt.Errorf("invalid custom field type %q", field.Type)
+	}
+	field.Required = definition.Required
+
+	field.Default = nil
+	value, set, err := customFieldValue(s.userRepository, field, definition.Default)
+	if err != nil {
+		return err
+	}
+	if set {
+		field.Default = &value
+	}
+	return nil
+}
+
+// findCustomField looks a custom field up by ID or, ignoring case, by name.
+func findCustomField(fields []*models.CustomField, idOrName string) *models.CustomField {
+	for _, field := range fields {
+		if field.ID == idOrName {
+			return field
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, idOrName) {
+			return field
+		}
+	}
+	return nil
+}
+
+func sortCustomFields(fields []*models.CustomField) {
+	slices.SortFunc(fields, func(a, b *models.CustomField) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+}
+
+// customFieldValue converts a raw value, as decoded from JSON, into a value
+// of field, and reports whether it sets the field: nil, blank text and empty
+// selections clear it instead.
+func customFieldValue(users UserRepository, field *models.CustomField, raw any) (models.CustomFieldValue, bool, error) {
+	var value models.CustomFieldValue
+	if raw == nil {
+		return value, false, nil
+	}
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s %s", ErrInvalidCustomField, field.Name, fmt.Sprintf(format, args...))
+	}
+
+	switch field.Type {
+	case models.CustomFieldText, models.CustomFieldUser, models.CustomFieldSingleSelect:
+		text, ok := raw.(string)
+		if !ok {
+			return value, false, invalid("must be a string")
+		}
+		if text = strings.TrimSpace(text); text == "" {
+			return value, false, nil
+		}
+		switch field.Type {
+		case models.CustomFieldUser:
+			if _, err := users.GetByID(text); err != nil {
+				return value, false, invalid("refers to unknown user %q", text)
+			}
+		case models.CustomFieldSingleSelect:
+			option, ok := selectOption(field, text)
+			if !ok {
+				return value, false, invalid("must be one of %s", strings.Join(field.Options, ", "))
+			}
+			text = option
+		}
+		value.Text = text
+	case models.CustomFieldNumber:
+		var number float64
+		switch n := raw.(type) {
+		case float64:
+			number = n
+		case int:
+			number = float64(n)
+		default:
+			return value, false, invalid("must be a number")
+		}
+		value.Number = &number
+	case models.CustomFieldDate:
+		var date time.Time
+		switch d := raw.(type) {
+		case time.Time:
+			date = d
+		case string:
+			if strings.TrimSpace(d) == "" {
+				return value, false, nil
+			}
+			var err error
+			if date, err = parseCustomDate(d); err != nil {
+				return value, false, invalid("must be a date such as 2006-01-02")
+			}
+		default:
+			return value, false, invalid("must be a date such as 2006-01-02")
+		}
+		date = day(date)
+		value.Date = &date
+	case models.CustomFieldMultiSelect:
+		var items []any
+		switch list := raw.(type) {
+		case []any:
+			items = list
+		case []string:
+			for _, item := range list {
+				items = append(items, item)
+			}
+		default:
+			return value, false, invalid("must be a list of options")
+		}
+		for _, item := range items {
+			text, _ := item.(string)
+			option, ok := selectOption(field, text)
+			if !ok {
+				return value, false, invalid("options must be among %s", strings.Join(field.Options, ", "))
+			}
+			if !slices.Contains(value.Options, option) {
+				value.Options = append(value.Options, option)
+			}
+		}
+		if len(value.Options) == 0 {
+			return value, false, nil
+		}
+	}
+	return value, true, nil
+}
+
+// selectOption returns the option of a select field matching text, ignoring
+// case.
+func selectOption(field *models.CustomField, text string) (string, bool) {
+	i := slices.IndexFunc(field.Options, func(o string) bool { return strings.EqualFold(o, strings.TrimSpace(text)) })
+	if i < 0 {
+		return "", false
+	}
+	return field.Options[i], true
+}
+
+func parseCustomDate(text string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, text); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, text)
+}
+
+// customFieldFilter builds the filter for tasks whose value of field matches
+// raw: text fields contain it, multi-select fields hold any of its
+// comma-separated options, and other fields equal it.
+func customFieldFilter(field *models.CustomField, raw string) (query.Expr, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s filter %s", ErrInvalidCustomField, field.Name, fmt.Sprintf(format, args...))
+	}
+	value := query.Value{Raw: raw, Text: strings.TrimSpace(raw)}
+
+	switch field.Type {
+	case models.CustomFieldText:
+		return query.Comparison{Field: query.CustomField(field.ID, query.KindText), Op: query.OpContains, Values: []query.Value{value}}, nil
+	case models.CustomFieldUser:
+		return query.Comparison{Field: query.CustomField(field.ID, query.KindString), Op: query.OpEqual, Values: []query.Value{value}}, nil
+	case models.CustomFieldSingleSelect:
+		option, ok := selectOption(field, raw)
+		if !ok {
+			return nil, invalid("must be one of %s", strings.Join(field.Options, ", "))
+		}
+		value.Text = option
+		return query.Comparison{Field: query.CustomField(field.ID, query.KindString), Op: query.OpEqual, Values: []query.Value{value}}, nil
+	case models.CustomFieldMultiSelect:
+		var values []query.Value
+		for _, item := range strings.Split(raw, ",") {
+			option, ok := selectOption(field, item)
+			if !ok {
+				return nil, invalid("options must be among %s", strings.Join(field.Options, ", "))
+			}
+			values = append(values, query.Value{Raw: item, Text: option})
+		}
+		return query.Comparison{Field: query.CustomField(field.ID, query.KindList), Op: query.OpIn, Values: values}, nil
+	case models.CustomFieldNumber:
+		number, err := strconv.ParseFloat(value.Text, 64)
+		if err != nil {
+			return nil, invalid("must be a number")
+		}
+		value.Decimal = number
+		return query.Comparison{Field: query.CustomField(field.ID, query.KindDecimal), Op: query.OpEqual, Values: []query.Value{value}}, nil
+	case models.CustomFieldDate:
+		date, err := parseCustomDate(value.Text)
+		if err != nil {
+			return nil, invalid("must be a date such as 2006-01-02")
+		}
+		value.Time = day(date)
+		return query.Comparison{Field: query.CustomField(field.ID, query.KindTime), Op: query.OpEqual, Values: []query.Value{value}}, nil
+	}
+	return nil, invalid("has an unknown type")
+}
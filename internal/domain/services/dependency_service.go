@@ -0,0 +1,487 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+var (
+	ErrTaskBlocked     = errors.New("task is blocked by open tasks")
+	ErrDependencyCycle = errors.New("link would create a dependency cycle")
+)
+
+type DependencyService struct {
+	taskRepository TaskRepository
+}
+
+func NewDependencyService(taskRepository TaskRepository) *DependencyService {
+	return &DependencyService{
+		taskRepository: taskRepository,
+	}
+}
+
+// DependencyGraph is the link graph of a project. Tasks of other projects
+// linked to it are included as external nodes.
+type DependencyGraph struct {
+	Nodes        []GraphNode  `json:"nodes"`
+	Edges        []GraphEdge  `json:"edges"`
+	Cycles       [][]string   `json:"cycles"`
+	CriticalPath CriticalPath `json:"critical_path"`
+}
+
+type GraphNode struct {
+	ID          string            `json:"id"`
+	Key         string            `json:"key,omitempty"`
+	Title       string            `json:"title"`
+	Status      models.TaskStatus `json:"status"`
+	StoryPoints *int              `json:"story_points,omitempty"`
+	External    bool              `json:"external,omitempty"`
+}
+
+// GraphEdge is a link drawn once: from blocker to blocked task, from
+// duplicate to original, and between related tasks in either direction.
+type GraphEdge struct {
+	From string          `json:"from"`
+	To   string          `json:"to"`
+	Type models.LinkType `json:"type"`
+}
+
+// CriticalPath is the chain of blocking links with the most remaining work.
+// Length is in story points.
+type CriticalPath struct {
+	TaskIDs []string `json:"task_ids"`
+	Length  int      `json:"length"`
+}
+
+// LinkTasks links taskID to targetID and stores the inverse link on the
+// target, both or neither. Blocking links that would close a cycle are
+// rejected.
+func (s *DependencyService) LinkTasks(taskID string, linkType models.LinkType, targetID string) (*models.Task, error) {
+	if !linkType.Valid() {
+		return nil, fmt.Errorf("invalid link type %q", linkType)
+	}
+
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+	target, err := findTask(s.taskRepository, targetID)
+	if err != nil {
+		return nil, errors.New("linked task not found")
+	}
+	if task.ID == target.ID {
+		return nil, errors.New("a task cannot be linked to itself")
+	}
+	if slices.ContainsFunc(task.Links, func(l models.TaskLink) bool { return l.TaskID == target.ID }) {
+		return nil, fmt.Errorf("%s is already linked to %s", taskLabel(task), taskLabel(target))
+	}
+
+	switch linkType {
+	case models.LinkBlocks:
+		err = s.checkCycle(task, target)
+	case models.LinkBlockedBy:
+		err = s.checkCycle(target, task)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	task.Links = append(task.Links, models.TaskLink{Type: linkType, TaskID: target.ID})
+	task.UpdatedAt = now
+	target.Links = append(target.Links, models.TaskLink{Type: linkType.Inverse(), TaskID: task.ID})
+	target.UpdatedAt = now
+
+	err = s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+		if err := tx.Update(task); err != nil {
+			return err
+		}
+		return tx.Update(target)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// UnlinkTasks removes the link between two tasks, from both of them or from
+// neither.
+func (s *DependencyService) UnlinkTasks(taskID, targetID string) error {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return errors.New("task not found")
+	}
+	target, err := findTask(s.taskRepository, targetID)
+	if err != nil {
+		return errors.New("linked task not found")
+	}
+	if !slices.ContainsFunc(task.Links, func(l models.TaskLink) bool { return l.TaskID == target.ID }) {
+		return fmt.Errorf("%s is not linked to %s", taskLabel(task), taskLabel(target))
+	}
+
+	return s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+		if err := removeLink(tx, task, target.ID); err != nil {
+			return err
+		}
+		return removeLink(tx, target, task.ID)
+	})
+}
+
+// checkCycle fails if blocked already blocks blocker, directly or through
+// other tasks, so that blocker blocking blocked would close a cycle.
+func (s *DependencyService) checkCycle(blocker, blocked *models.Task) error {
+	path := map[string]string{blocked.ID: ""}
+	queue := []*models.Task{blocked}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, link := range current.Links {
+			if link.Type != models.LinkBlocks {
+				continue
+			}
+			if _, seen := path[link.TaskID]; seen {
+				continue
+			}
+			path[link.TaskID] = current.ID
+			if link.TaskID == blocker.ID {
+				return s.cycleError(path, blocker, blocked)
+			}
+
+			next, err := s.taskRepository.GetByID(link.TaskID)
+			if err != nil {
+				continue
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// cycleError describes the cycle found by checkCycle, starting and ending at
+// blocker.
+func (s *DependencyService) cycleError(path map[string]string, blocker, blocked *models.Task) error {
+	var labels []string
+	for id := blocker.ID; id != ""; id = path[id] {
+		if task, err := s.taskRepository.GetByID(id); err == nil {
+			labels = append(labels, taskLabel(task))
+		}
+	}
+	slices.Reverse(labels)
+	labels = append([]string{taskLabel(blocker)}, labels...)
+	return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(labels, " -> "))
+}
+
+// Graph builds the link graph of a project, with the cycles among its
+// blocking links and its critical path. The critical path is empty while the
+// graph has cycles.
+func (s *DependencyService) Graph(projectID string) (*DependencyGraph, error) {
+	tasks, err := s.taskRepository.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(tasks, func(a, b *models.Task) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	graph := &DependencyGraph{
+		Nodes:        make([]GraphNode, 0, len(tasks)),
+		Edges:        make([]GraphEdge, 0),
+		Cycles:       make([][]string, 0),
+		CriticalPath: CriticalPath{TaskIDs: make([]string, 0)},
+	}
+	byID := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+		graph.Nodes = append(graph.Nodes, graphNode(task, false))
+	}
+
+	seen := map[GraphEdge]bool{}
+	for _, task := range tasks {
+		for _, link := range task.Links {
+			if _, ok := byID[link.TaskID]; !ok {
+				external, err := s.taskRepository.GetByID(link.TaskID)
+				if err != nil {
+					continue
+				}
+				byID[external.ID] = external
+				graph.Nodes = append(graph.Nodes, graphNode(external, true))
+			}
+
+			edge := graphEdge(task.ID, link)
+			if !seen[edge] {
+				seen[edge] = true
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+
+	blocks := map[string][]string{}
+	for _, edge := range graph.Edges {
+		if edge.Type == models.LinkBlocks {
+			blocks[edge.From] = append(blocks[edge.From], edge.To)
+		}
+	}
+
+	graph.Cycles = findCycles(graph.Nodes, blocks)
+	if len(graph.Cycles) == 0 {
+		graph.CriticalPath = criticalPath(graph.Nodes, byID, blocks)
+	}
+	return graph, nil
+}
+
+// DOT renders the graph in Graphviz DOT. Blocking links are solid arrows,
+// duplicates dashed and related tasks dotted lines; external and done tasks
+// are drawn dashed and grey, and the critical path in red.
+func (g *DependencyGraph) DOT() string {
+	critical := map[string]bool{}
+	for _, id := range g.CriticalPath.TaskIDs {
+		critical[id] = true
+	}
+	onPath := map[GraphEdge]bool{}
+	for i := 1; i < len(g.CriticalPath.TaskIDs); i++ {
+		ids := g.CriticalPath.TaskIDs
+		onPath[GraphEdge{From: ids[i-1], To: ids[i], Type: models.LinkBlocks}] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		label := node.Title
+		if node.Key != "" {
+			label = node.Key + "\n" + node.Title
+		}
+		attrs := []string{"label=" + dotQuote(label)}
+		if node.External {
+			attrs = append(attrs, "style=dashed")
+		}
+		if node.Status == models.Done {
+			attrs = append(attrs, "color=grey", "fontcolor=grey")
+		} else if critical[node.ID] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", dotQuote(node.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		var attrs []string
+		switch edge.Type {
+		case models.LinkDuplicates:
+			attrs = append(attrs, "style=dashed", `label="duplicates"`)
+		case models.LinkRelatesTo:
+			attrs = append(attrs, "style=dotted", "dir=none")
+		}
+		if onPath[edge] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+
+		fmt.Fprintf(&b, "\t%s -> %s", dotQuote(edge.From), dotQuote(edge.To))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes s as a DOT string, turning line breaks into DOT's \n.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+func graphNode(task *models.Task, external bool) GraphNode {
+	return GraphNode{
+		ID:          task.ID,
+		Key:         task.Key,
+		Title:       task.Title,
+		Status:      task.Status,
+		StoryPoints: task.StoryPoints,
+		External:    external,
+	}
+}
+
+// graphEdge turns a link stored on taskID into its canonical edge, so that
+// both ends of a link yield the same edge.
+func graphEdge(taskID string, link models.TaskLink) GraphEdge {
+	switch link.Type {
+	case models.LinkBlockedBy, models.LinkDuplicatedBy:
+		return GraphEdge{From: link.TaskID, To: taskID, Type: link.Type.Inverse()}
+	case models.LinkRelatesTo:
+		if link.TaskID < taskID {
+			return GraphEdge{From: link.TaskID, To: taskID, Type: link.Type}
+		}
+	}
+	return GraphEdge{From: taskID, To: link.TaskID, Type: link.Type}
+}
+
+// findCycles returns the strongly connected components of the blocking
+// graph that contain more than one task, using Tarjan's algorithm.
+func findCycles(nodes []GraphNode, blocks map[string][]string) [][]string {
+	var (
+		index   = map[string]int{}
+		lowlink = map[string]int{}
+		onStack = map[string]bool{}
+		stack   []string
+		cycles  = make([][]string, 0)
+	)
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index)
+		lowlink[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range blocks[id] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowlink[id] = min(lowlink[id], lowlink[next])
+			} else if onStack[next] {
+				lowlink[id] = min(lowlink[id], index[next])
+			}
+		}
+
+		if lowlink[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		if len(component) > 1 {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := index[node.ID]; !visited {
+			visit(node.ID)
+		}
+	}
+	return cycles
+}
+
+// criticalPath finds the longest chain of blocking links, weighing each task
+// by its remaining story points. Done tasks weigh nothing and unestimated
+// open tasks count as one point, so that they still lengthen the chain.
+func criticalPath(nodes []GraphNode, tasks map[string]*models.Task, blocks map[string][]string) CriticalPath {
+	indegree := map[string]int{}
+	for _, targets := range blocks {
+		for _, id := range targets {
+			indegree[id]++
+		}
+	}
+
+	var order []string
+	for _, node := range nodes {
+		if indegree[node.ID] == 0 {
+			order = append(order, node.ID)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, next := range blocks[order[i]] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				order = append(order, next)
+			}
+		}
+	}
+
+	length := map[string]int{}
+	previous := map[string]string{}
+	end := ""
+	for _, id := range order {
+		length[id] += remainingWeight(tasks[id])
+		if end == "" || length[id] > length[end] {
+			end = id
+		}
+		for _, next := range blocks[id] {
+			if length[id] > length[next] {
+				length[next] = length[id]
+				previous[next] = id
+			}
+		}
+	}
+
+	path := CriticalPath{TaskIDs: make([]string, 0)}
+	if end == "" {
+		return path
+	}
+	path.Length = length[end]
+	for id := end; id != ""; id = previous[id] {
+		path.TaskIDs = append(path.TaskIDs, id)
+	}
+	slices.Reverse(path.TaskIDs)
+	return path
+}
+
+func remainingWeight(task *models.Task) int {
+	switch {
+	case task == nil || task.Status == models.Done:
+		return 0
+	case task.StoryPoints == nil:
+		return 1
+	}
+	return *task.StoryPoints
+}
+
+// checkBlockers fails with ErrTaskBlocked when any task blocking task is
+// still open.
+func checkBlockers(repository TaskRepository, task *models.Task) error {
+	var open []string
+	for _, link := range task.Links {
+		if link.Type != models.LinkBlockedBy {
+			continue
+		}
+		blocker, err := repository.GetByID(link.TaskID)
+		if err != nil {
+			continue
+		}
+		if blocker.Status != models.Done {
+			open = append(open, taskLabel(blocker))
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("%w: %s", ErrTaskBlocked, strings.Join(open, ", "))
+	}
+	return nil
+}
+
+// unlinkAll removes task from the links of every task it is linked to, before
+// task is deleted. Linked tasks that no longer exist are skipped, and so are
+// those of archived projects, which keep their links.
+func unlinkAll(repository TaskRepository, task *models.Task) error {
+	for _, link := range task.Links {
+		other, err := repository.GetByID(link.TaskID)
+		if err != nil {
+			continue
+		}
+		if err := removeLink(repository, other, task.ID); err != nil && !errors.Is(err, ErrProjectArchived) {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeLink(repository TaskRepository, task *models.Task, linkedID string) error {
+	task.Links = slices.DeleteFunc(task.Links, func(l models.TaskLink) bool { return l.TaskID == linkedID })
+	task.UpdatedAt = time.Now()
+	return repository.Update(task)
+}
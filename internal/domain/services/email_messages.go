@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// emailLocales are the languages notification emails are written in. Every
+// locale has every key of emailMessages.
+var emailLocales = []string{"en", "es"}
+
+// emailMessages holds the text of notification emails by locale and key, as
+// fmt formats.
+var emailMessages = map[string]map[string]string{
+	"en": {
+		"greeting":            "Hi %s,",
+		"intro.one":           "You have a new notification:",
+		"intro.many":          "You have %d new notifications:",
+		"subject.many":        "%d new notifications",
+		"subject.daily":       "Your daily summary: %d notifications",
+		"subject.weekly":      "Your weekly summary: %d notifications",
+		"footer":              "You get this email because of your notification settings.",
+		"unsubscribe":         "Unsubscribe",
+		"unsubscribe.confirm": "Do you want to stop getting notification emails?",
+		"unsubscribed":        "You will not get notification emails anymore. You can turn them back on in your notification settings.",
+		"someone":             "Someone",
+		"date":                "Jan 2, 2006 15:04 MST",
+
+		"event.assigned":       "You were assigned %[1]s: %[2]s",
+		"event.mentioned":      "%[3]s mentioned you on %[1]s: %[2]s",
+		"event.status_changed": "%[1]s: %[2]s moved to %[4]s",
+		"event.commented":      "%[3]s commented on %[1]s: %[2]s",
+		"event.due_soon":       "%[1]s: %[2]s is due %[5]s",
+
+		"status.to_do":                    "To do",
+		"status.in_progress":              "In progress",
+		"status.ready_for_implementation": "Ready for implementation",
+		"status.done":                     "Done",
+	},
+	"es": {
+		"greeting":            "Hola, %s:",
+		"intro.one":           "Tienes una notificación nueva:",
+		"intro.many":          "Tienes %d notificaciones nuevas:",
+		"subject.many":        "%d notificaciones nuevas",
+		"subject.daily":       "Tu resumen diario: %d notificaciones",
+		"subject.weekly":      "Tu resumen semanal: %d notificaciones",
+		"footer":              "Recibes este correo por tu configuración de notificaciones.",
+		"unsubscribe":         "Darse de baja",
+		"unsubscribe.confirm": "¿Quieres dejar de recibir correos de notificaciones?",
+		"unsubscribed":        "Ya no recibirás correos de notificaciones. Puedes volver a activarlos en tu configuración de notificaciones.",
+		"someone":             "Alguien",
+		"date":                "02/01/2006 15:04 MST",
+
+		"event.assigned":       "Se te asignó %[1]s: %[2]s",
+		"event.mentioned":      "%[3]s te mencionó en %[1]s: %[2]s",
+		"event.status_changed": "%[1]s: %[2]s pasó a %[4]s",
+		"event.commented":      "%[3]s comentó en %[1]s: %[2]s",
+		"event.due_soon":       "%[1]s: %[2]s vence el %[5]s",
+
+		"status.to_do":                    "Por hacer",
+		"status.in_progress":              "En curso",
+		"status.ready_for_implementation": "Lista para implementar",
+		"status.done":                     "Hecha",
+	},
+}
+
+// emailText formats the message of a key in a locale, falling back to
+// English for unknown locales.
+func emailText(locale, key string, args ...any) string {
+	messages, ok := emailMessages[locale]
+	if !ok {
+		messages = emailMessages["en"]
+	}
+	if len(args) == 0 {
+		return messages[key]
+	}
+	return fmt.Sprintf(messages[key], args...)
+}
+
+// describeNotification renders a notification as one line of an email, with
+// actor as the name of whoever caused it.
+func describeNotification(locale string, notification *models.Notification, actor string, loc *time.Location) string {
+	if actor == "" {
+		actor = emailText(locale, "someone")
+	}
+	status := emailText(locale, "status."+string(notification.Status))
+	if status == "" {
+		status = string(notification.Status)
+	}
+	due := ""
+	if notification.DueDate != nil {
+		due = notification.DueDate.In(loc).Format(emailText(locale, "date"))
+	}
+	return emailText(locale, "event."+string(notification.Event), notification.TaskKey, notification.TaskTitle, actor, status, due)
+}
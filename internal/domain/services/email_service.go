@@ -0,0 +1,453 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(message *EmailMessage) error
+}
+
+// EmailMessage is an email with a plain text and an HTML body. Headers are
+// added to the ones the mailer sets.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
+
+//go:embed templates
+var emailTemplates embed.FS
+
+var (
+	textTemplate = template.Must(template.ParseFS(emailTemplates, "templates/notifications.txt"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(emailTemplates, "templates/notifications.html"))
+
+	unsubscribeTemplate = htmltemplate.Must(htmltemplate.ParseFS(emailTemplates, "templates/unsubscribe.html"))
+)
+
+// emailData fills the notification email templates.
+type emailData struct {
+	Locale           string
+	Subject          string
+	Greeting         string
+	Intro            string
+	Items            []string
+	Footer           string
+	UnsubscribeLabel string
+	UnsubscribeURL   string
+}
+
+// unsubscribeData fills the unsubscribe page. Without an Action the page only
+// shows its message.
+type unsubscribeData struct {
+	Locale  string
+	Title   string
+	Message string
+	Action  string
+}
+
+// UnsubscribeLinks signs and checks the one-click unsubscribe links of
+// notification emails. A link stays valid until the secret changes.
+type UnsubscribeLinks struct {
+	baseURL string
+	secret  []byte
+}
+
+func NewUnsubscribeLinks(baseURL string, secret []byte) *UnsubscribeLinks {
+	return &UnsubscribeLinks{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  secret,
+	}
+}
+
+// URL returns the unsubscribe link of a user.
+func (l *UnsubscribeLinks) URL(userID string) string {
+	return l.baseURL + "/unsubscribe?token=" + url.QueryEscape(l.token(userID))
+}
+
+func (l *UnsubscribeLinks) token(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID)) + "." + base64.RawURLEncoding.EncodeToString(l.sign(userID))
+}
+
+// userID returns the user a token was signed for.
+func (l *UnsubscribeLinks) userID(token string) (string, error) {
+	encodedID, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", errors.New("invalid unsubscribe token")
+	}
+	userID, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil {
+		return "", errors.New("invalid unsubscribe token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, l.sign(string(userID))) {
+		return "", errors.New("invalid unsubscribe token")
+	}
+	return string(userID), nil
+}
+
+func (l *UnsubscribeLinks) sign(userID string) []byte {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte("unsubscribe:" + userID))
+	return mac.Sum(nil)
+}
+
+// UnsubscribeService turns notification emails off from the links in them.
+// Users are looked up across organizations, as links are followed without
+// signing in.
+type UnsubscribeService struct {
+	userRepository UserRepository
+	links          *UnsubscribeLinks
+}
+
+func NewUnsubscribeService(userRepository UserRepository, links *UnsubscribeLinks) *UnsubscribeService {
+	return &UnsubscribeService{
+		userRepository: userRepository,
+		links:          links,
+	}
+}
+
+// ConfirmUnsubscribe returns the page an unsubscribe link opens, asking the
+// user of the token to confirm in their language. It changes nothing, so
+// link scanners following the link do not unsubscribe anyone.
+func (s *UnsubscribeService) ConfirmUnsubscribe(token string) ([]byte, error) {
+	user, err := s.user(token)
+	if err != nil {
+		return nil, err
+	}
+	locale := unsubscribeLocale(user)
+	return renderUnsubscribePage(unsubscribeData{
+		Locale:  locale,
+		Title:   emailText(locale, "unsubscribe"),
+		Message: emailText(locale, "unsubscribe.confirm"),
+		Action:  s.links.URL(user.ID),
+	})
+}
+
+// Unsubscribe turns notification emails off for the user of a token and
+// returns the page confirming it, in their language.
+func (s *UnsubscribeService) Unsubscribe(token string) ([]byte, error) {
+	user, err := s.user(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings := user.EmailSettings; settings != nil && settings.Delivery != models.EmailOff {
+		settings.Delivery = models.EmailOff
+		if err := s.userRepository.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	locale := unsubscribeLocale(user)
+	return renderUnsubscribePage(unsubscribeData{
+		Locale:  locale,
+		Title:   emailText(locale, "unsubscribe"),
+		Message: emailText(locale, "unsubscribed"),
+	})
+}
+
+func (s *UnsubscribeService) user(token string) (*models.User, error) {
+	userID, err := s.links.userID(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepository.GetByID(userID)
+}
+
+// unsubscribeLocale is the language of a user's emails, English for users who
+// never set one up.
+func unsubscribeLocale(user *models.User) string {
+	if user.EmailSettings == nil {
+		return "en"
+	}
+	return user.EmailSettings.Locale
+}
+
+func renderUnsubscribePage(data unsubscribeData) ([]byte, error) {
+	var page bytes.Buffer
+	if err := unsubscribeTemplate.Execute(&page, data); err != nil {
+		return nil, err
+	}
+	return page.Bytes(), nil
+}
+
+type EmailService struct {
+	notificationRepository NotificationRepository
+	userRepository         UserRepository
+	mailer                 Mailer
+	links                  *UnsubscribeLinks
+	defaultLocale          string
+}
+
+func NewEmailService(notificationRepository NotificationRepository, userRepository UserRepository, mailer Mailer, links *UnsubscribeLinks, defaultLocale string) *EmailService {
+	return &EmailService{
+		notificationRepository: notificationRepository,
+		userRepository:         userRepository,
+		mailer:                 mailer,
+		links:                  links,
+		defaultLocale:          defaultLocale,
+	}
+}
+
+// EmailSettings returns how a user gets notifications by email.
+func (s *EmailService) EmailSettings(userID string) (*models.EmailSettings, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailSettings == nil {
+		return &models.EmailSettings{Delivery: models.EmailOff, Locale: s.defaultLocale, DigestHour: 8, DigestDay: time.Monday}, nil
+	}
+	return user.EmailSettings, nil
+}
+
+// SetEmailSettings replaces how a user gets notifications by email. Turning
+// emails on starts from the notifications that come after.
+func (s *EmailService) SetEmailSettings(userID string, settings *models.EmailSettings) (*models.EmailSettings, error) {
+	if settings.Delivery == "" {
+		settings.Delivery = models.EmailOff
+	}
+	if err := validateEmailSettings(settings); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := user.EmailSettings
+	if previous == nil || previous.Delivery == models.EmailOff {
+		settings.Since = time.Now()
+		settings.LastSentAt = nil
+	} else {
+		settings.Since = previous.Since
+		settings.LastSentAt = previous.LastSentAt
+	}
+	user.EmailSettings = settings
+	if err := s.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SendDue emails every user whose instant email or digest is due at now. It
+// is meant to run periodically, as often as instant emails should go out.
+func (s *EmailService) SendDue(now time.Time) error {
+	users, err := s.userRepository.List()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, user := range users {
+		settings := user.EmailSettings
+		if settings == nil || settings.Delivery == models.EmailOff || !emailDue(settings, now) {
+			continue
+		}
+		if err := s.send(user, now); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", user.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send emails a user their pending notifications, in one email, and records
+// when digests went out even if there was nothing to send.
+func (s *EmailService) send(user *models.User, now time.Time) error {
+	settings := user.EmailSettings
+	pending, err := s.notificationRepository.ListUnemailed(user.ID, settings.Since)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		message, err := s.compose(user, pending)
+		if err != nil {
+			return err
+		}
+		if err := s.mailer.Send(message); err != nil {
+			return err
+		}
+		ids := make([]string, len(pending))
+		for i, notification := range pending {
+			ids[i] = notification.ID
+		}
+		if err := s.notificationRepository.MarkEmailed(ids, now); err != nil {
+			return err
+		}
+	} else if settings.Delivery == models.EmailInstant {
+		return nil
+	}
+
+	settings.LastSentAt = &now
+	return s.userRepository.Update(user)
+}
+
+// compose writes the email of a user's notifications in their language.
+func (s *EmailService) compose(user *models.User, notifications []*models.Notification) (*EmailMessage, error) {
+	settings := user.EmailSettings
+	locale := settings.Locale
+	if locale == "" {
+		locale = s.defaultLocale
+	}
+	loc := emailLocation(settings)
+
+	actors := make(map[string]string)
+	items := make([]string, len(notifications))
+	for i, notification := range notifications {
+		if _, ok := actors[notification.ActorID]; !ok && notification.ActorID != "" {
+			if actor, err := s.userRepository.GetByID(notification.ActorID); err == nil {
+				actors[notification.ActorID] = actor.Name
+			}
+		}
+		items[i] = describeNotification(locale, notification, actors[notification.ActorID], loc)
+	}
+
+	data := emailData{
+		Locale:           locale,
+		Greeting:         emailText(locale, "greeting", user.Name),
+		Intro:            emailText(locale, "intro.many", len(items)),
+		Items:            items,
+		Footer:           emailText(locale, "footer"),
+		UnsubscribeLabel: emailText(locale, "unsubscribe"),
+		UnsubscribeURL:   s.links.URL(user.ID),
+	}
+	switch {
+	case settings.Delivery == models.EmailDaily:
+		data.Subject = emailText(locale, "subject.daily", len(items))
+	case settings.Delivery == models.EmailWeekly:
+		data.Subject = emailText(locale, "subject.weekly", len(items))
+	case len(items) == 1:
+		data.Subject = items[0]
+	default:
+		data.Subject = emailText(locale, "subject.many", len(items))
+	}
+	if len(items) == 1 {
+		data.Intro = emailText(locale, "intro.one")
+	}
+
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &EmailMessage{
+		To:      user.Email,
+		Subject: data.Subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func validateEmailSettings(settings *models.EmailSettings) error {
+	switch settings.Delivery {
+	case models.EmailOff, models.EmailInstant, models.EmailDaily, models.EmailWeekly:
+	default:
+		return fmt.Errorf("invalid email delivery %q", settings.Delivery)
+	}
+	if settings.Locale != "" && !slices.Contains(emailLocales, settings.Locale) {
+		return fmt.Errorf("unsupported locale %q", settings.Locale)
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", settings.Timezone)
+	}
+	if settings.DigestHour < 0 || settings.DigestHour > 23 {
+		return errors.New("digest hour must be between 0 and 23")
+	}
+	if settings.DigestDay < time.Sunday || settings.DigestDay > time.Saturday {
+		return errors.New("digest day must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if (settings.QuietStart == "") != (settings.QuietEnd == "") {
+		return errors.New("quiet hours need both a start and an end")
+	}
+	for _, t := range []string{settings.QuietStart, settings.QuietEnd} {
+		if _, err := time.Parse("15:04", t); t != "" && err != nil {
+			return fmt.Errorf("invalid quiet hours time %q, expected HH:MM", t)
+		}
+	}
+	return nil
+}
+
+// emailDue reports whether a user's emails may go out at now: instant ones
+// whenever it is not quiet hours, digests once their latest slot has passed
+// since the last one went out, or since emails were turned on.
+func emailDue(settings *models.EmailSettings, now time.Time) bool {
+	local := now.In(emailLocation(settings))
+	if inQuietHours(settings, local) {
+		return false
+	}
+	if settings.Delivery == models.EmailInstant {
+		return true
+	}
+
+	slot := time.Date(local.Year(), local.Month(), local.Day(), settings.DigestHour, 0, 0, 0, local.Location())
+	period := 1
+	if settings.Delivery == models.EmailWeekly {
+		period = 7
+		slot = slot.AddDate(0, 0, -((int(local.Weekday()) - int(settings.DigestDay) + 7) % 7))
+	}
+	if slot.After(local) {
+		slot = slot.AddDate(0, 0, -period)
+	}
+
+	last := settings.Since
+	if settings.LastSentAt != nil {
+		last = *settings.LastSentAt
+	}
+	return last.Before(slot)
+}
+
+// inQuietHours reports whether local, in the user's timezone, falls in their
+// quiet hours, which may span midnight.
+func inQuietHours(settings *models.EmailSettings, local time.Time) bool {
+	if settings.QuietStart == "" {
+		return false
+	}
+	start, err := time.Parse("15:04", settings.QuietStart)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", settings.QuietEnd)
+	if err != nil {
+		return false
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	from, to := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	if from <= to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+func emailLocation(settings *models.EmailSettings) *time.Location {
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// Event is something that happened to a task that users may be notified of.
+// Task is the task as it was right after the event.
+type Event struct {
+	Type    models.NotificationEvent
+	Task    *models.Task
+	ActorID string
+	// CommentID and CommentBody are set for comments.
+	CommentID   string
+	CommentBody string
+	At          time.Time
+}
+
+// EventPublisher hands events over to be processed away from the request
+// that raised them.
+type EventPublisher interface {
+	Publish(event Event)
+}
+
+// EventQueue is an EventPublisher whose events are read from Events, in the
+// order they were published.
+type EventQueue struct {
+	events  chan Event
+	wait    time.Duration
+	dropped atomic.Int64
+}
+
+// NewEventQueue creates a queue holding up to size events. Once it is full,
+// publishers wait up to wait for room before dropping their event.
+func NewEventQueue(size int, wait time.Duration) *EventQueue {
+	return &EventQueue{events: make(chan Event, size), wait: wait}
+}
+
+// Publish queues an event, waiting for room when the queue is full. An event
+// that finds no room in time is dropped and counted in Dropped, so a stalled
+// reader slows requests down by at most the wait.
+func (q *EventQueue) Publish(event Event) {
+	select {
+	case q.events <- event:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(q.wait)
+	defer timer.Stop()
+	select {
+	case q.events <- event:
+	case <-timer.C:
+		q.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many events found the queue full and were dropped.
+func (q *EventQueue) Dropped() int64 {
+	return q.dropped.Load()
+}
+
+func (q *EventQueue) Events() <-chan Event {
+	return q.events
+}
+
+// eventRepository is a TaskRepository that publishes the assignments and
+// status changes it stores, whichever service made them. It does not know
+// who made them, so its events have no actor.
+type eventRepository struct {
+	TaskRepository
+	publisher EventPublisher
+	// pending holds the events of a transaction until it commits.
+	pending *[]Event
+}
+
+// PublishTaskEvents wraps a task repository so that assignments and status
+// changes made through it are published.
+func PublishTaskEvents(repository TaskRepository, publisher EventPublisher) TaskRepository {
+	return &eventRepository{TaskRepository: repository, publisher: publisher}
+}
+
+func (r *eventRepository) Create(task *models.Task) error {
+	if err := r.TaskRepository.Create(task); err != nil {
+		return err
+	}
+	r.publishChanges(&models.Task{Status: task.Status}, task)
+	return nil
+}
+
+func (r *eventRepository) Update(task *models.Task) error {
+	previous, err := r.TaskRepository.GetByID(task.ID)
+	if err != nil {
+		// Tasks in the trash have no one to notify.
+		return r.TaskRepository.Update(task)
+	}
+	if err := r.TaskRepository.Update(task); err != nil {
+		return err
+	}
+	if task.DeletedAt == nil {
+		r.publishChanges(previous, task)
+	}
+	return nil
+}
+
+func (r *eventRepository) SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error) {
+	previous, err := r.TaskRepository.GetByID(taskID)
+	if err != nil {
+		return false, err
+	}
+	ok, err := r.TaskRepository.SetPosition(taskID, expected, status, rank)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	updated := *previous
+	updated.Status = status
+	updated.Rank = rank
+	r.publishChanges(previous, &updated)
+	return true, nil
+}
+
+// WithTransaction publishes the events of the transaction only once it
+// commits.
+func (r *eventRepository) WithTransaction(fn func(repository TaskRepository) error) error {
+	var pending []Event
+	err := r.TaskRepository.WithTransaction(func(repository TaskRepository) error {
+		pending = nil
+		return fn(&eventRepository{TaskRepository: repository, publisher: r.publisher, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+	for _, event := range pending {
+		r.publisher.Publish(event)
+	}
+	return nil
+}
+
+func (r *eventRepository) publishChanges(before, after *models.Task) {
+	now := time.Now()
+	task := *after
+	if after.AssigneeID != "" && after.AssigneeID != before.AssigneeID {
+		r.publish(Event{Type: models.NotifyAssigned, Task: &task, At: now})
+	}
+	if after.Status != before.Status {
+		r.publish(Event{Type: models.NotifyStatusChanged, Task: &task, At: now})
+	}
+}
+
+func (r *eventRepository) publish(event Event) {
+	if r.pending != nil {
+		*r.pending = append(*r.pending, event)
+		return
+	}
+	r.publisher.Publish(event)
+}
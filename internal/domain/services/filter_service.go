@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+)
+
+type FilterRepository interface {
+	Create(filter *models.SavedFilter) error
+	GetByID(id string) (*models.SavedFilter, error)
+	Update(filter *models.SavedFilter) error
+	Delete(id string) error
+	ListByOwner(ownerID string) ([]*models.SavedFilter, error)
+	ListByProjects(projectIDs []string) ([]*models.SavedFilter, error)
+}
+
+// FilterService runs task queries and manages the saved filters built on them.
+type FilterService struct {
+	repository     FilterRepository
+	taskRepository TaskRepository
+	projectService *ProjectService
+	labelService   *LabelService
+}
+
+func NewFilterService(repository FilterRepository, taskRepository TaskRepository, projectService *ProjectService, labelService *LabelService) *FilterService {
+	return &FilterService{
+		repository:     repository,
+		taskRepository: taskRepository,
+		projectService: projectService,
+		labelService:   labelService,
+	}
+}
+
+// Search parses and runs a query, limited to the projects the caller can access.
+func (s *FilterService) Search(caller Caller, input string) ([]*models.Task, error) {
+	q, err := query.Parse(input, s.env(caller))
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs, err := s.projectService.AccessibleProjectIDs(caller)
+	if err != nil {
+		return nil, err
+	}
+	if !caller.IsAdmin() {
+		query.RestrictToProjects(q, projectIDs)
+	}
+
+	return s.taskRepository.ListByQuery(q)
+}
+
+func (s *FilterService) SaveFilter(caller Caller, name, input, projectID string) (*models.SavedFilter, error) {
+	if name == "" {
+		return nil, errors.New("filter name is required")
+	}
+	if err := s.validate(caller, input, projectID); err != nil {
+		return nil, err
+	}
+
+	filter := &models.SavedFilter{
+		ID:        generateID(),
+		Name:      name,
+		Query:     input,
+		OwnerID:   caller.UserID,
+		ProjectID: projectID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.repository.Create(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// UpdateFilter replaces the name, query and sharing of a filter. Passing an
+// empty projectID makes the filter private again.
+func (s *FilterService) UpdateFilter(caller Caller, id, name, input, projectID string) (*models.SavedFilter, error) {
+	filter, err := s.ownedFilter(caller, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(caller, input, projectID); err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		filter.Name = name
+	}
+	filter.Query = input
+	filter.ProjectID = projectID
+	filter.UpdatedAt = time.Now()
+
+	err = s.repository.Update(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (s *FilterService) DeleteFilter(caller Caller, id string) error {
+	if _, err := s.ownedFilter(caller, id); err != nil {
+		return err
+	}
+	return s.repository.Delete(id)
+}
+
+// GetFilter returns a filter the caller owns or that is shared with one of
+// the caller's projects.
+func (s *FilterService) GetFilter(caller Caller, id string) (*models.SavedFilter, error) {
+	filter, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.OwnerID == caller.UserID {
+		return filter, nil
+	}
+	if filter.ProjectID == "" {
+		return nil, errors.New("filter not found")
+	}
+	if err := s.projectService.CheckAccess(caller, filter.ProjectID); err != nil {
+		return nil, errors.New("filter not found")
+	}
+	return filter, nil
+}
+
+// ListFilters returns the caller's own filters followed by those shared with
+// projects the caller belongs to.
+func (s *FilterService) ListFilters(caller Caller) ([]*models.SavedFilter, error) {
+	filters, err := s.repository.ListByOwner(caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectService.ListProjects(caller, true)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+
+	shared, err := s.repository.ListByProjects(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range shared {
+		if filter.OwnerID != caller.UserID {
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
+
+// RunFilter runs a saved filter on behalf of the caller, so "me" and relative
+// dates resolve against whoever runs it rather than whoever saved it.
+func (s *FilterService) RunFilter(caller Caller, id string) ([]*models.Task, error) {
+	filter, err := s.GetFilter(caller, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.Search(caller, filter.Query)
+}
+
+func (s *FilterService) validate(caller Caller, input, projectID string) error {
+	if _, err := query.Parse(input, s.env(caller)); err != nil {
+		return err
+	}
+	if projectID != "" {
+		return s.projectService.CheckAccess(caller, projectID)
+	}
+	return nil
+}
+
+func (s *FilterService) env(caller Caller) query.Env {
+	return query.Env{
+		UserID:         caller.UserID,
+		Now:            time.Now(),
+		ResolveProject: s.projectService.ResolveProjectID,
+		ResolveLabel:   s.labelService.LabelIDsByName,
+	}
+}
+
+func (s *FilterService) ownedFilter(caller Caller, id string) (*models.SavedFilter, error) {
+	filter, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if filter.OwnerID != caller.UserID && !caller.IsAdmin() {
+		return nil, errors.New("only the owner can change a filter")
+	}
+	return filter, nil
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// FlowFilter narrows the tasks flow metrics are computed over, by their
+// current attributes, and sets the date range covered. Zero-valued fields are
+// ignored; a zero range defaults to recent weeks.
+type FlowFilter struct {
+	LabelID    string
+	AssigneeID string
+	Type       models.TaskType
+	From       time.Time
+	To         time.Time
+}
+
+// TaskFlow is how a task moved through the workflow. Durations are in hours;
+// cycle time runs from the first time the task went in progress to when it
+// was last done, and lead time from its creation to then.
+type TaskFlow struct {
+	TaskID       string                             `json:"task_id"`
+	Key          string                             `json:"key,omitempty"`
+	Title        string                             `json:"title"`
+	Type         models.TaskType                    `json:"type"`
+	AssigneeID   string                             `json:"assignee_id,omitempty"`
+	Status       models.TaskStatus                  `json:"status"`
+	CreatedAt    time.Time                          `json:"created_at"`
+	StartedAt    *time.Time                         `json:"started_at,omitempty"`
+	DoneAt       *time.Time                         `json:"done_at,omitempty"`
+	CycleTime    *float64                           `json:"cycle_time_hours,omitempty"`
+	LeadTime     *float64                           `json:"lead_time_hours,omitempty"`
+	TimeInStatus map[models.TaskStatus]float64      `json:"time_in_status_hours"`
+	CustomFields map[string]models.CustomFieldValue `json:"custom_fields,omitempty"`
+}
+
+// WeekThroughput counts the tasks done in the week starting on WeekStart, a
+// Monday.
+type WeekThroughput struct {
+	WeekStart time.Time `json:"week_start"`
+	Tasks     int       `json:"tasks"`
+	Points    int       `json:"points"`
+}
+
+// FlowDay counts tasks by status at the end of a day, one point of a
+// cumulative flow diagram.
+type FlowDay struct {
+	Date   time.Time                 `json:"date"`
+	Counts map[models.TaskStatus]int `json:"counts"`
+}
+
+// FlowStatuses are the statuses of a cumulative flow diagram, bottom band
+// first.
+var FlowStatuses = []models.TaskStatus{models.Done, models.InProgress, models.ReadyForImplementation, models.ToDo}
+
+const (
+	defaultFlowDays       = 30
+	defaultThroughputDays = 12 * 7
+)
+
+type FlowService struct {
+	taskRepository     TaskRepository
+	activityRepository ActivityRepository
+}
+
+func NewFlowService(taskRepository TaskRepository, activityRepository ActivityRepository) *FlowService {
+	return &FlowService{
+		taskRepository:     taskRepository,
+		activityRepository: activityRepository,
+	}
+}
+
+// TaskFlows returns the flow of a project's tasks. With a date range, only
+// the tasks done within it are included.
+func (s *FlowService) TaskFlows(projectID string, filter FlowFilter) ([]*TaskFlow, error) {
+	timelines, err := s.timelines(projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]*TaskFlow, 0, len(timelines))
+	for _, timeline := range timelines {
+		flow := timeline.flow()
+		if !filter.From.IsZero() || !filter.To.IsZero() {
+			if flow.DoneAt == nil || !inRange(*flow.DoneAt, filter.From, filter.To) {
+				continue
+			}
+		}
+		flows = append(flows, flow)
+	}
+	return flows, nil
+}
+
+// Throughput returns the tasks done in each week of the date range, which
+// defaults to the last twelve weeks.
+func (s *FlowService) Throughput(projectID string, filter FlowFilter) ([]WeekThroughput, error) {
+	from, to, err := flowRange(filter, defaultThroughputDays)
+	if err != nil {
+		return nil, err
+	}
+	timelines, err := s.timelines(projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var weeks []WeekThroughput
+	for start := weekStart(from); !start.After(to); start = start.AddDate(0, 0, 7) {
+		weeks = append(weeks, WeekThroughput{WeekStart: start})
+	}
+	for _, timeline := range timelines {
+		doneAt := timeline.doneAt()
+		if doneAt == nil || !inRange(*doneAt, from, to) {
+			continue
+		}
+		week := &weeks[int(weekStart(*doneAt).Sub(weekStart(from)).Hours())/(7*24)]
+		week.Tasks++
+		if timeline.task.StoryPoints != nil {
+			week.Points += *timeline.task.StoryPoints
+		}
+	}
+	return weeks, nil
+}
+
+// CumulativeFlow returns the number of tasks in each status at the end of
+// every day of the date range, which defaults to the last thirty days.
+func (s *FlowService) CumulativeFlow(projectID string, filter FlowFilter) ([]FlowDay, error) {
+	from, to, err := flowRange(filter, defaultFlowDays)
+	if err != nil {
+		return nil, err
+	}
+	timelines, err := s.timelines(projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []FlowDay
+	for date := day(from); !date.After(to); date = date.AddDate(0, 0, 1) {
+		cutoff := date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		counts := make(map[models.TaskStatus]int, len(FlowStatuses))
+		for _, status := range FlowStatuses {
+			counts[status] = 0
+		}
+		for _, timeline := range timelines {
+			if status, ok := timeline.statusAt(cutoff); ok {
+				counts[status]++
+			}
+		}
+		days = append(days, FlowDay{Date: date, Counts: counts})
+	}
+	return days, nil
+}
+
+// timelines builds the status timeline of every task of a project that
+// matches the filter.
+func (s *FlowService) timelines(projectID string, filter FlowFilter) ([]*statusTimeline, error) {
+	tasks, err := s.taskRepository.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	tasks = slices.DeleteFunc(tasks, func(task *models.Task) bool {
+		return (filter.LabelID != "" && !slices.Contains(task.LabelIDs, filter.LabelID)) ||
+			(filter.AssigneeID != "" && task.AssigneeID != filter.AssigneeID) ||
+			(filter.Type != "" && task.Type != filter.Type)
+	})
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	activities, err := s.activityRepository.ListByTasks(ids)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string][]*models.Activity)
+	for _, activity := range activities {
+		if activity.Field == "status" {
+			changes[activity.TaskID] = append(changes[activity.TaskID], activity)
+		}
+	}
+
+	timelines := make([]*statusTimeline, len(tasks))
+	for i, task := range tasks {
+		timelines[i] = newStatusTimeline(task, changes[task.ID])
+	}
+	return timelines, nil
+}
+
+// statusTimeline is the sequence of statuses a task went through.
+type statusTimeline struct {
+	task      *models.Task
+	intervals []statusInterval
+}
+
+// statusInterval is a stretch of time a task spent in one status. The last
+// interval has a zero end: the task is still in that status.
+type statusInterval struct {
+	status     models.TaskStatus
+	start, end time.Time
+}
+
+// newStatusTimeline replays a task's status changes, oldest first, from its
+// creation. A task created before its history was recorded starts in the
+// status its first recorded change left.
+func newStatusTimeline(task *models.Task, changes []*models.Activity) *statusTimeline {
+	status := task.Status
+	if len(changes) > 0 {
+		status = models.TaskStatus(changes[0].From)
+	}
+
+	timeline := &statusTimeline{task: task}
+	start := task.CreatedAt
+	for _, change := range changes {
+		if change.Action == models.ActivityCreated {
+			status = models.TaskStatus(change.To)
+			continue
+		}
+		timeline.intervals = append(timeline.intervals, statusInterval{status: status, start: start, end: change.At})
+		status, start = models.TaskStatus(change.To), change.At
+	}
+	timeline.intervals = append(timeline.intervals, statusInterval{status: status, start: start})
+	return timeline
+}
+
+// startedAt returns when the task first went in progress.
+func (t *statusTimeline) startedAt() *time.Time {
+	for _, interval := range t.intervals {
+		if interval.status == models.InProgress {
+			return &interval.start
+		}
+	}
+	return nil
+}
+
+// doneAt returns when the task was last done, if it still is.
+func (t *statusTimeline) doneAt() *time.Time {
+	last := t.intervals[len(t.intervals)-1]
+	if last.status != models.Done {
+		return nil
+	}
+	return &last.start
+}
+
+// statusAt returns the status of the task at time at, if it existed then.
+func (t *statusTimeline) statusAt(at time.Time) (models.TaskStatus, bool) {
+	if at.Before(t.task.CreatedAt) {
+		return "", false
+	}
+	for _, interval := range t.intervals {
+		if interval.end.IsZero() || at.Before(interval.end) {
+			return interval.status, true
+		}
+	}
+	return "", false
+}
+
+func (t *statusTimeline) flow() *TaskFlow {
+	flow := &TaskFlow{
+		TaskID:       t.task.ID,
+		Key:          t.task.Key,
+		Title:        t.task.Title,
+		Type:         t.task.Type,
+		AssigneeID:   t.task.AssigneeID,
+		Status:       t.task.Status,
+		CreatedAt:    t.task.CreatedAt,
+		StartedAt:    t.startedAt(),
+		DoneAt:       t.doneAt(),
+		TimeInStatus: make(map[models.TaskStatus]float64),
+		CustomFields: t.task.CustomFields,
+	}
+
+	now := time.Now()
+	for _, interval := range t.intervals {
+		end := interval.end
+		if end.IsZero() {
+			if interval.status == models.Done {
+				continue
+			}
+			end = now
+		}
+		flow.TimeInStatus[interval.status] += end.Sub(interval.start).Hours()
+	}
+
+	if flow.DoneAt != nil {
+		lead := flow.DoneAt.Sub(flow.CreatedAt).Hours()
+		flow.LeadTime = &lead
+		if flow.StartedAt != nil {
+			cycle := flow.DoneAt.Sub(*flow.StartedAt).Hours()
+			flow.CycleTime = &cycle
+		}
+	}
+	return flow
+}
+
+// flowRange returns the date range of a filter, defaulting to the days up to
+// now.
+func flowRange(filter FlowFilter, defaultDays int) (time.Time, time.Time, error) {
+	from, to := filter.From, filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = day(to).AddDate(0, 0, 1-defaultDays)
+	}
+	if to.Before(from) {
+		return from, to, errors.New("date range ends before it starts")
+	}
+	return from, to, nil
+}
+
+func inRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+// weekStart returns the Monday (UTC) of the week t falls in.
+func weekStart(t time.Time) time.Time {
+	d := day(t)
+	return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+}
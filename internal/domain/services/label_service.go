@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type LabelRepository interface {
+	Create(label *models.Label) error
+	GetByID(id string) (*models.Label, error)
+	Update(label *models.Label) error
+	Delete(id string) error
+	ListByProject(projectID string) ([]*models.Label, error)
+	ListByName(name string) ([]*models.Label, error)
+}
+
+type LabelService struct {
+	repository        LabelRepository
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+}
+
+func NewLabelService(repository LabelRepository, taskRepository TaskRepository, projectRepository ProjectRepository) *LabelService {
+	return &LabelService{
+		repository:        repository,
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+const defaultLabelColor = "#6b778c"
+
+func (s *LabelService) CreateLabel(projectID, name, color string) (*models.Label, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("label name is required")
+	}
+	if color == "" {
+		color = defaultLabelColor
+	}
+	if !labelColorPattern.MatchString(color) {
+		return nil, errors.New("label color must be a hex color such as #ff5630")
+	}
+	if err := checkWritable(s.projectRepository, projectID); err != nil {
+		return nil, err
+	}
+
+	label := &models.Label{
+		ID:        generateID(),
+		ProjectID: projectID,
+		Name:      name,
+		Color:     strings.ToLower(color),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.repository.Create(label)
+	if err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+func (s *LabelService) GetLabel(id string) (*models.Label, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *LabelService) UpdateLabel(id, name, color string) (*models.Label, error) {
+	label, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(s.projectRepository, label.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if name = strings.TrimSpace(name); name != "" {
+		label.Name = name
+	}
+	if color != "" {
+		if !labelColorPattern.MatchString(color) {
+			return nil, errors.New("label color must be a hex color such as #ff5630")
+		}
+		label.Color = strings.ToLower(color)
+	}
+	label.UpdatedAt = time.Now()
+
+	err = s.repository.Update(label)
+	if err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+// DeleteLabel deletes a label and removes it from every task carrying it.
+func (s *LabelService) DeleteLabel(id string) error {
+	label, err := s.repository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(s.projectRepository, label.ProjectID); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(id); err != nil {
+		return err
+	}
+	return s.taskRepository.RemoveLabel(id)
+}
+
+func (s *LabelService) ListLabels(projectID string) ([]*models.Label, error) {
+	return s.repository.ListByProject(projectID)
+}
+
+// LabelIDsByName returns the IDs of every label called name, in any project.
+func (s *LabelService) LabelIDsByName(name string) []string {
+	labels, err := s.repository.ListByName(name)
+	if err != nil {
+		return nil
+	}
+
+	ids := make([]string, len(labels))
+	for i, label := range labels {
+		ids[i] = label.ID
+	}
+	return ids
+}
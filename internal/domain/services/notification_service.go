@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	// ListByUser returns the notifications of a user, newest first, at most
+	// limit of them.
+	ListByUser(userID string, unreadOnly bool, limit int) ([]*models.Notification, error)
+	CountUnread(userID string) (int, error)
+	// MarkRead marks a notification of a user read, unless it already is.
+	MarkRead(userID, id string, at time.Time) error
+	// MarkAllRead marks every unread notification of a user read and returns
+	// how many there were.
+	MarkAllRead(userID string, at time.Time) (int, error)
+	// HasDueSoon reports whether a user was already told a task is due at
+	// dueDate.
+	HasDueSoon(userID, taskID string, dueDate time.Time) (bool, error)
+	// ListUnemailed returns the unread notifications of a user created since
+	// since that were not emailed yet, oldest first.
+	ListUnemailed(userID string, since time.Time) ([]*models.Notification, error)
+	MarkEmailed(ids []string, at time.Time) error
+}
+
+// mentionPattern matches the users mentioned in a comment by their email, as
+// in "@ana@example.com".
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([^\s@]+@[^\s@]+)`)
+
+type NotificationService struct {
+	repository        NotificationRepository
+	userRepository    UserRepository
+	taskRepository    TaskRepository
+	commentRepository CommentRepository
+	projectRepository ProjectRepository
+	teamRepository    TeamRepository
+}
+
+func NewNotificationService(repository NotificationRepository, userRepository UserRepository, taskRepository TaskRepository, commentRepository CommentRepository, projectRepository ProjectRepository, teamRepository TeamRepository) *NotificationService {
+	return &NotificationService{
+		repository:        repository,
+		userRepository:    userRepository,
+		taskRepository:    taskRepository,
+		commentRepository: commentRepository,
+		projectRepository: projectRepository,
+		teamRepository:    teamRepository,
+	}
+}
+
+// Handle turns an event into notifications for the users it concerns: the
+// assignee of the task, and for comments the users mentioned in it and those
+// who commented before. Nobody is notified of their own actions, of events
+// they turned off, or of tasks in projects they cannot access.
+func (s *NotificationService) Handle(event Event) error {
+	type recipient struct {
+		userID string
+		event  models.NotificationEvent
+	}
+	var recipients []recipient
+	seen := map[string]bool{event.ActorID: true, "": true}
+	add := func(userID string, e models.NotificationEvent) {
+		if !seen[userID] {
+			seen[userID] = true
+			recipients = append(recipients, recipient{userID, e})
+		}
+	}
+
+	switch event.Type {
+	case models.NotifyCommented:
+		for _, userID := range s.mentionedUsers(event.CommentBody) {
+			add(userID, models.NotifyMentioned)
+		}
+		add(event.Task.AssigneeID, models.NotifyCommented)
+		comments, err := s.commentRepository.ListByTask(event.Task.ID)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			add(comment.AuthorID, models.NotifyCommented)
+		}
+	default:
+		add(event.Task.AssigneeID, event.Type)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	project, err := s.projectRepository.GetByID(event.Task.ProjectID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range recipients {
+		if err := s.deliver(r.userID, r.event, event, project); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyDueSoon notifies the assignees of unfinished tasks due within window
+// of now, once per due date. It is meant to run periodically.
+func (s *NotificationService) NotifyDueSoon(now time.Time, window time.Duration) error {
+	tasks, err := s.taskRepository.ListByQuery(&query.Query{Where: query.All(
+		query.Compare("due", query.OpGreaterOrEqual, query.Value{Time: now}),
+		query.Compare("due", query.OpLessOrEqual, query.Value{Time: now.Add(window)}),
+		query.Compare("status", query.OpNotEqual, query.Value{Text: string(models.Done)}),
+		query.Compare("assignee", query.OpNotEmpty),
+	)})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, task := range tasks {
+		notified, err := s.repository.HasDueSoon(task.AssigneeID, task.ID, *task.DueDate)
+		if err == nil && !notified {
+			err = s.Handle(Event{Type: models.NotifyDueSoon, Task: task, At: now})
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListNotifications returns the latest notifications of a user, only the
+// unread ones if unreadOnly, along with how many are unread.
+func (s *NotificationService) ListNotifications(userID string, unreadOnly bool, limit int) ([]*models.Notification, int, error) {
+	notifications, err := s.repository.ListByUser(userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.repository.CountUnread(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (s *NotificationService) UnreadCount(userID string) (int, error) {
+	return s.repository.CountUnread(userID)
+}
+
+func (s *NotificationService) MarkRead(userID, id string) error {
+	return s.repository.MarkRead(userID, id, time.Now())
+}
+
+func (s *NotificationService) MarkAllRead(userID string) (int, error) {
+	return s.repository.MarkAllRead(userID, time.Now())
+}
+
+// Preferences returns whether each event is on for a user.
+func (s *NotificationService) Preferences(userID string) (map[models.NotificationEvent]bool, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return preferences(user), nil
+}
+
+// SetPreferences turns the given events on or off for a user, leaving the
+// others as they were, and returns the resulting preferences.
+func (s *NotificationService) SetPreferences(userID string, changes map[models.NotificationEvent]bool) (map[models.NotificationEvent]bool, error) {
+	for event := range changes {
+		if !slices.Contains(models.NotificationEvents, event) {
+			return nil, fmt.Errorf("unknown notification event %q", event)
+		}
+	}
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.NotificationPreferences == nil {
+		user.NotificationPreferences = make(map[models.NotificationEvent]bool, len(changes))
+	}
+	for event, on := range changes {
+		user.NotificationPreferences[event] = on
+	}
+	if err := s.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+	return preferences(user), nil
+}
+
+// deliver stores the notification of an event for a user, if they want it
+// and can see the task.
+func (s *NotificationService) deliver(userID string, kind models.NotificationEvent, event Event, project *models.Project) error {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		// Users deleted since, or outside the organization, are skipped.
+		return nil
+	}
+	if !preferences(user)[kind] {
+		return nil
+	}
+	if user.Role != models.Admin {
+		teamIDs, err := userTeamIDs(s.teamRepository, user.ID)
+		if err != nil {
+			return err
+		}
+		if !isProjectMember(project, user.ID, teamIDs) {
+			return nil
+		}
+	}
+
+	task := event.Task
+	notification := &models.Notification{
+		ID:        generateID(),
+		UserID:    user.ID,
+		Event:     kind,
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		TaskKey:   task.Key,
+		TaskTitle: task.Title,
+		ActorID:   event.ActorID,
+		CommentID: event.CommentID,
+		CreatedAt: event.At,
+	}
+	switch kind {
+	case models.NotifyStatusChanged:
+		notification.Status = task.Status
+	case models.NotifyDueSoon:
+		notification.DueDate = task.DueDate
+	}
+	return s.repository.Create(notification)
+}
+
+// mentionedUsers returns the IDs of the users mentioned in a comment body,
+// ignoring mentions of unknown emails.
+func (s *NotificationService) mentionedUsers(body string) []string {
+	var userIDs []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := strings.TrimRight(match[1], ".,;:!?)")
+		if user, err := s.userRepository.GetByEmail(email); err == nil {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	return userIDs
+}
+
+// preferences returns whether each event is on for a user.
+func preferences(user *models.User) map[models.NotificationEvent]bool {
+	prefs := make(map[models.NotificationEvent]bool, len(models.NotificationEvents))
+	for _, event := range models.NotificationEvents {
+		on, set := user.NotificationPreferences[event]
+		prefs[event] = on || !set
+	}
+	return prefs
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// OrganizationRepository stores the organizations of the deployment. Their
+// projects and everything else they own are kept in stores of their own.
+type OrganizationRepository interface {
+	Create(organization *models.Organization) error
+	GetByID(id string) (*models.Organization, error)
+	List() ([]*models.Organization, error)
+}
+
+type OrganizationService struct {
+	repository     OrganizationRepository
+	userRepository UserRepository
+}
+
+// NewOrganizationService takes the user repository shared by every
+// organization, unscoped.
+func NewOrganizationService(repository OrganizationRepository, userRepository UserRepository) *OrganizationService {
+	return &OrganizationService{
+		repository:     repository,
+		userRepository: userRepository,
+	}
+}
+
+// CreateOrganization creates an organization along with its first admin, who
+// can then add the rest of its users.
+func (s *OrganizationService) CreateOrganization(name, adminName, adminEmail, adminPassword string) (*models.Organization, *models.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil, errors.New("organization name is required")
+	}
+	if adminEmail == "" || adminPassword == "" {
+		return nil, nil, errors.New("admin email and password are required")
+	}
+
+	organization := &models.Organization{
+		ID:        generateID(),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	users := ScopeUsers(s.userRepository, organization.ID)
+	admin, err := NewUserService(users).Register(adminName, adminEmail, adminPassword, models.Admin)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.repository.Create(organization); err != nil {
+		users.Delete(admin.ID)
+		return nil, nil, err
+	}
+	return organization, admin, nil
+}
+
+func (s *OrganizationService) GetOrganization(id string) (*models.Organization, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *OrganizationService) ListOrganizations() ([]*models.Organization, error) {
+	return s.repository.List()
+}
+
+// tenantUsers is the user repository of one organization. The users of other
+// organizations look as if they did not exist, except that emails stay unique
+// across the deployment, since users log in by email alone.
+type tenantUsers struct {
+	UserRepository
+	organizationID string
+}
+
+// ScopeUsers wraps the user repository shared by every organization so that
+// only the users of one of them can be read or changed through it. The
+// default workspace is "".
+func ScopeUsers(repository UserRepository, organizationID string) UserRepository {
+	return &tenantUsers{UserRepository: repository, organizationID: organizationID}
+}
+
+func (r *tenantUsers) Create(user *models.User) error {
+	if _, err := r.UserRepository.GetByEmail(user.Email); err == nil {
+		return errors.New("user already exists")
+	}
+	user.OrganizationID = r.organizationID
+	return r.UserRepository.Create(user)
+}
+
+func (r *tenantUsers) GetByID(id string) (*models.User, error) {
+	return r.own(r.UserRepository.GetByID(id))
+}
+
+func (r *tenantUsers) GetByEmail(email string) (*models.User, error) {
+	return r.own(r.UserRepository.GetByEmail(email))
+}
+
+func (r *tenantUsers) Update(user *models.User) error {
+	if _, err := r.GetByID(user.ID); err != nil {
+		return err
+	}
+	user.OrganizationID = r.organizationID
+	return r.UserRepository.Update(user)
+}
+
+func (r *tenantUsers) Delete(id string) error {
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+	return r.UserRepository.Delete(id)
+}
+
+func (r *tenantUsers) List() ([]*models.User, error) {
+	return r.UserRepository.ListByOrganization(r.organizationID)
+}
+
+func (r *tenantUsers) ListByOrganization(organizationID string) ([]*models.User, error) {
+	if organizationID != r.organizationID {
+		return []*models.User{}, nil
+	}
+	return r.UserRepository.ListByOrganization(organizationID)
+}
+
+// own hides a user of another organization.
+func (r *tenantUsers) own(user *models.User, err error) (*models.User, error) {
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID != r.organizationID {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
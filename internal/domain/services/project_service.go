@@ -0,0 +1,337 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+var ErrProjectAccessDenied = errors.New("project access denied")
+
+// ErrProjectArchived is returned by every change to an archived project or to
+// its tasks, sprints, labels, custom fields, comments and worklogs.
+var ErrProjectArchived = errors.New("project is archived and read-only")
+
+// ProjectRepository stores projects. Projects in the trash are skipped by
+// GetByID and the lists, but not by GetByKey: their keys stay reserved until
+// they are purged, so they can be restored. ListByMember returns the projects
+// userID is a member of, directly or through any of teamIDs.
+type ProjectRepository interface {
+	Create(project *models.Project) error
+	GetByID(id string) (*models.Project, error)
+	GetByKey(key string) (*models.Project, error)
+	Update(project *models.Project) error
+	Delete(id string) error
+	List() ([]*models.Project, error)
+	ListByMember(userID string, teamIDs []string) ([]*models.Project, error)
+	GetTrashed(id string) (*models.Project, error)
+	ListTrash() ([]*models.Project, error)
+	ListTrashedBefore(cutoff time.Time) ([]*models.Project, error)
+}
+
+type ProjectService struct {
+	repository     ProjectRepository
+	teamRepository TeamRepository
+	userRepository UserRepository
+}
+
+func NewProjectService(repository ProjectRepository, teamRepository TeamRepository, userRepository UserRepository) *ProjectService {
+	return &ProjectService{
+		repository:     repository,
+		teamRepository: teamRepository,
+		userRepository: userRepository,
+	}
+}
+
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// CreateProject creates a project owned by ownerID. The key prefixes the
+// project's task keys (API-123); when empty, one is derived from the name.
+func (s *ProjectService) CreateProject(name, key, description, ownerID string) (*models.Project, error) {
+	if name == "" {
+		return nil, errors.New("project name is required")
+	}
+
+	key = strings.ToUpper(key)
+	if key == "" {
+		derived, err := s.deriveKey(name)
+		if err != nil {
+			return nil, err
+		}
+		key = derived
+	}
+	if !projectKeyPattern.MatchString(key) {
+		return nil, errors.New("project key must be 2 to 10 letters or digits, starting with a letter")
+	}
+	if _, err := s.repository.GetByKey(key); err == nil {
+		return nil, errors.New("project key already in use")
+	}
+
+	project := &models.Project{
+		ID:          generateID(),
+		Key:         key,
+		Name:        name,
+		Description: description,
+		OwnerID:     ownerID,
+		MemberIDs:   []string{ownerID},
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err := s.repository.Create(project)
+	if err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+func (s *ProjectService) GetProject(id string) (*models.Project, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *ProjectService) GetProjectByKey(key string) (*models.Project, error) {
+	return s.repository.GetByKey(strings.ToUpper(key))
+}
+
+// ResolveProjectID maps a project key to its ID. Anything that is not a known
+// key is returned unchanged, so IDs pass straight through.
+func (s *ProjectService) ResolveProjectID(idOrKey string) string {
+	if project, err := s.repository.GetByKey(strings.ToUpper(idOrKey)); err == nil {
+		return project.ID
+	}
+	return idOrKey
+}
+
+func (s *ProjectService) UpdateProject(project *models.Project) error {
+	if err := checkWritable(s.repository, project.ID); err != nil {
+		return err
+	}
+	project.UpdatedAt = time.Now()
+	return s.repository.Update(project)
+}
+
+// DeleteProject deletes a project document for good, leaving its tasks
+// alone. Users delete projects through the trash instead.
+func (s *ProjectService) DeleteProject(id string) error {
+	return s.repository.Delete(id)
+}
+
+// ListProjects returns every project the caller can see: all of them for
+// admins, otherwise the ones the caller is a member of, directly or through a
+// team. Archived projects are left out unless includeArchived is set.
+func (s *ProjectService) ListProjects(caller Caller, includeArchived bool) ([]*models.Project, error) {
+	var projects []*models.Project
+	var err error
+	if caller.IsAdmin() {
+		projects, err = s.repository.List()
+	} else {
+		projects, err = s.memberProjects(caller.UserID)
+	}
+	if err != nil || includeArchived {
+		return projects, err
+	}
+	return slices.DeleteFunc(projects, func(p *models.Project) bool { return p.ArchivedAt != nil }), nil
+}
+
+// ArchiveProject makes a project read-only and hides it from the default
+// project lists.
+func (s *ProjectService) ArchiveProject(archivedBy, id string) (*models.Project, error) {
+	project, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt != nil {
+		return nil, errors.New("project is already archived")
+	}
+
+	now := time.Now()
+	project.ArchivedAt = &now
+	project.ArchivedBy = archivedBy
+	project.UpdatedAt = now
+	if err := s.repository.Update(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *ProjectService) UnarchiveProject(id string) (*models.Project, error) {
+	project, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt == nil {
+		return nil, errors.New("project is not archived")
+	}
+
+	project.ArchivedAt = nil
+	project.ArchivedBy = ""
+	project.UpdatedAt = time.Now()
+	if err := s.repository.Update(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *ProjectService) AddMember(projectID, userID string) error {
+	project, err := s.repository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.userRepository.GetByID(userID); err != nil {
+		return err
+	}
+
+	if slices.Contains(project.MemberIDs, userID) {
+		return nil
+	}
+	project.MemberIDs = append(project.MemberIDs, userID)
+	project.UpdatedAt = time.Now()
+	return s.repository.Update(project)
+}
+
+// AddTeam grants every member of a team, present and future, membership of
+// a project.
+func (s *ProjectService) AddTeam(projectID, teamID string) error {
+	project, err := s.repository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.teamRepository.GetByID(teamID); err != nil {
+		return err
+	}
+
+	if slices.Contains(project.TeamIDs, teamID) {
+		return nil
+	}
+	project.TeamIDs = append(project.TeamIDs, teamID)
+	project.UpdatedAt = time.Now()
+	return s.repository.Update(project)
+}
+
+func (s *ProjectService) RemoveTeam(projectID, teamID string) error {
+	project, err := s.repository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+
+	project.TeamIDs = slices.DeleteFunc(project.TeamIDs, func(id string) bool { return id == teamID })
+	project.UpdatedAt = time.Now()
+	return s.repository.Update(project)
+}
+
+func (s *ProjectService) RemoveMember(projectID, userID string) error {
+	project, err := s.repository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+
+	if userID == project.OwnerID {
+		return errors.New("cannot remove the project owner")
+	}
+	project.MemberIDs = slices.DeleteFunc(project.MemberIDs, func(id string) bool { return id == userID })
+	project.UpdatedAt = time.Now()
+	return s.repository.Update(project)
+}
+
+// CheckAccess returns ErrProjectAccessDenied unless the caller is an admin or a
+// member of the project, directly or through a team.
+func (s *ProjectService) CheckAccess(caller Caller, projectID string) error {
+	if caller.IsAdmin() {
+		return nil
+	}
+
+	project, err := s.repository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(project.MemberIDs, caller.UserID) {
+		return nil
+	}
+	teamIDs, err := userTeamIDs(s.teamRepository, caller.UserID)
+	if err != nil {
+		return err
+	}
+	if !isProjectMember(project, caller.UserID, teamIDs) {
+		return ErrProjectAccessDenied
+	}
+	return nil
+}
+
+// AccessibleProjectIDs returns the IDs of the projects the caller is a member
+// of. Admins get a nil slice, meaning no restriction applies.
+func (s *ProjectService) AccessibleProjectIDs(caller Caller) ([]string, error) {
+	if caller.IsAdmin() {
+		return nil, nil
+	}
+
+	projects, err := s.memberProjects(caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ID)
+	}
+	return ids, nil
+}
+
+// memberProjects returns the projects a user is a member of, directly or
+// through a team.
+func (s *ProjectService) memberProjects(userID string) ([]*models.Project, error) {
+	teamIDs, err := userTeamIDs(s.teamRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.ListByMember(userID, teamIDs)
+}
+
+// deriveKey builds a key from the initials of a multi-word name, or the first
+// letters of a single word, adding a numeric suffix until it is unused.
+func (s *ProjectService) deriveKey(name string) (string, error) {
+	var words []string
+	for _, word := range strings.Fields(name) {
+		word = strings.Map(func(r rune) rune {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				return unicode.ToUpper(r)
+			}
+			return -1
+		}, word)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return "", errors.New("project key is required")
+	}
+
+	var base string
+	if len(words) == 1 {
+		base = words[0][:min(len(words[0]), 4)]
+	} else {
+		for _, word := range words[:min(len(words), 4)] {
+			base += word[:1]
+		}
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "P" + base
+	}
+	if len(base) < 2 {
+		base += "X"
+	}
+
+	key := base
+	for i := 2; ; i++ {
+		if _, err := s.repository.GetByKey(key); err != nil {
+			return key, nil
+		}
+		key = fmt.Sprintf("%s%d", base, i)
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/rrule"
+)
+
+type RecurringTaskRepository interface {
+	Create(recurring *models.RecurringTask) error
+	GetByID(id string) (*models.RecurringTask, error)
+	Update(recurring *models.RecurringTask) error
+	Delete(id string) error
+	ListByProject(projectID string) ([]*models.RecurringTask, error)
+	// ListDue returns the unpaused recurring tasks whose next run is at or
+	// before now.
+	ListDue(now time.Time) ([]*models.RecurringTask, error)
+	// ClaimRun moves a recurring task's next run from expected to next (nil
+	// when the rule has ended) and records expected as its last run, and
+	// reports whether it did. Of several schedulers racing for the same run,
+	// only one claims it.
+	ClaimRun(id string, expected time.Time, next *time.Time) (bool, error)
+}
+
+// TaskSchedule is when a recurring task recurs: an iCalendar RRULE such as
+// "FREQ=MONTHLY;BYDAY=1MO", counted from StartAt in the IANA Timezone, UTC by
+// default.
+type TaskSchedule struct {
+	RRule    string
+	StartAt  time.Time
+	Timezone string
+}
+
+const maxPreviewOccurrences = 100
+
+type RecurringTaskService struct {
+	repository        RecurringTaskRepository
+	taskService       *TaskService
+	projectRepository ProjectRepository
+}
+
+func NewRecurringTaskService(repository RecurringTaskRepository, taskService *TaskService, projectRepository ProjectRepository) *RecurringTaskService {
+	return &RecurringTaskService{
+		repository:        repository,
+		taskService:       taskService,
+		projectRepository: projectRepository,
+	}
+}
+
+// CreateRecurringTask schedules a task to be created in a project at every
+// occurrence of schedule from now on.
+func (s *RecurringTaskService) CreateRecurringTask(createdBy, projectID, title, description, assigneeID string, details TaskDetails, schedule TaskSchedule) (*models.RecurringTask, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, errors.New("task title is required")
+	}
+	if _, err := s.projectRepository.GetByID(projectID); err != nil {
+		return nil, err
+	}
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = time.Now()
+	}
+
+	recurring := &models.RecurringTask{
+		ID:                      generateID(),
+		ProjectID:               projectID,
+		Title:                   strings.TrimSpace(title),
+		Description:             description,
+		AssigneeID:              assigneeID,
+		Type:                    details.Type,
+		Priority:                details.Priority,
+		StoryPoints:             details.StoryPoints,
+		OriginalEstimateMinutes: details.OriginalEstimateMinutes,
+		LabelIDs:                details.LabelIDs,
+		RRule:                   schedule.RRule,
+		StartAt:                 schedule.StartAt.UTC().Truncate(time.Second),
+		Timezone:                schedule.Timezone,
+		CreatedBy:               createdBy,
+		CreatedAt:               time.Now(),
+		UpdatedAt:               time.Now(),
+	}
+
+	rule, start, err := recurrence(recurring)
+	if err != nil {
+		return nil, err
+	}
+	recurring.RRule = rule.String()
+	next, ok := rule.Next(start, time.Now())
+	if !ok {
+		return nil, errors.New("rrule has no upcoming occurrences")
+	}
+	recurring.NextRunAt = &next
+
+	if err := s.repository.Create(recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (s *RecurringTaskService) GetRecurringTask(id string) (*models.RecurringTask, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *RecurringTaskService) ListRecurringTasks(projectID string) ([]*models.RecurringTask, error) {
+	return s.repository.ListByProject(projectID)
+}
+
+func (s *RecurringTaskService) DeleteRecurringTask(id string) error {
+	return s.repository.Delete(id)
+}
+
+// PauseRecurringTask stops a recurring task from creating tasks until it is
+// resumed.
+func (s *RecurringTaskService) PauseRecurringTask(id string) (*models.RecurringTask, error) {
+	recurring, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	recurring.Paused = true
+	recurring.UpdatedAt = time.Now()
+	if err := s.repository.Update(recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// ResumeRecurringTask resumes a paused recurring task from its next
+// occurrence; the occurrences it missed while paused are skipped.
+func (s *RecurringTaskService) ResumeRecurringTask(id string) (*models.RecurringTask, error) {
+	recurring, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	rule, start, err := recurrence(recurring)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring.Paused = false
+	recurring.NextRunAt = nil
+	if next, ok := rule.Next(start, time.Now()); ok {
+		recurring.NextRunAt = &next
+	}
+	recurring.UpdatedAt = time.Now()
+	if err := s.repository.Update(recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// PreviewOccurrences returns the next n times a recurring task will create a
+// task, in its timezone.
+func (s *RecurringTaskService) PreviewOccurrences(id string, n int) ([]time.Time, error) {
+	recurring, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	rule, start, err := recurrence(recurring)
+	if err != nil {
+		return nil, err
+	}
+
+	after := time.Now()
+	if !recurring.Paused {
+		if recurring.NextRunAt == nil {
+			return []time.Time{}, nil
+		}
+		after = recurring.NextRunAt.Add(-time.Nanosecond)
+	}
+	occurrences := rule.Occurrences(start, after, min(n, maxPreviewOccurrences))
+	if occurrences == nil {
+		occurrences = []time.Time{}
+	}
+	return occurrences, nil
+}
+
+// RunDue creates a task for every recurring task due at now. A recurring task
+// that missed several occurrences, while the server was down, creates one task
+// and moves on to its next occurrence after now. Each run is claimed before
+// its task is created, so several servers never create the same task twice.
+func (s *RecurringTaskService) RunDue(now time.Time) error {
+	due, err := s.repository.ListDue(now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recurring := range due {
+		if err := s.run(recurring, now); err != nil {
+			errs = append(errs, fmt.Errorf("recurring task %s: %w", recurring.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *RecurringTaskService) run(recurring *models.RecurringTask, now time.Time) error {
+	rule, start, err := recurrence(recurring)
+	if err != nil {
+		return err
+	}
+
+	var next *time.Time
+	if t, ok := rule.Next(start, now); ok {
+		next = &t
+	}
+	claimed, err := s.repository.ClaimRun(recurring.ID, *recurring.NextRunAt, next)
+	if err != nil || !claimed {
+		return err
+	}
+
+	details := TaskDetails{
+		Type:                    recurring.Type,
+		Priority:                recurring.Priority,
+		StoryPoints:             recurring.StoryPoints,
+		LabelIDs:                recurring.LabelIDs,
+		OriginalEstimateMinutes: recurring.OriginalEstimateMinutes,
+	}
+	_, err = s.taskService.CreateTask(recurring.Title, recurring.Description, recurring.ProjectID, recurring.AssigneeID, details)
+	if errors.Is(err, ErrProjectArchived) {
+		// Occurrences falling while the project is archived are skipped.
+		return nil
+	}
+	return err
+}
+
+// recurrence parses the rule of a recurring task and returns it with the
+// task's start in its timezone.
+func recurrence(recurring *models.RecurringTask) (*rrule.Rule, time.Time, error) {
+	rule, err := rrule.Parse(recurring.RRule)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	loc, err := time.LoadLocation(recurring.Timezone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid timezone %q", recurring.Timezone)
+	}
+	return rule, recurring.StartAt.In(loc), nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"sort"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/textsearch"
+)
+
+const (
+	snippetLength = 160
+	// commentWeight scales comment matches relative to matches on the task itself.
+	commentWeight = 0.5
+)
+
+// SearchResult is a task matched by a full-text search. Highlights holds an
+// HTML snippet, with matches wrapped in <mark>, for every field that matched:
+// "title", "description" and "comment".
+type SearchResult struct {
+	Task       *models.Task      `json:"task"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type SearchService struct {
+	taskRepository    TaskRepository
+	commentRepository CommentRepository
+	projectService    *ProjectService
+}
+
+func NewSearchService(taskRepository TaskRepository, commentRepository CommentRepository, projectService *ProjectService) *SearchService {
+	return &SearchService{
+		taskRepository:    taskRepository,
+		commentRepository: commentRepository,
+		projectService:    projectService,
+	}
+}
+
+// Search ranks tasks by how well their title, description and comments match
+// text. Results are limited to projectID when given, and always to projects
+// the caller can access.
+func (s *SearchService) Search(caller Caller, text, projectID string, limit int) ([]*SearchResult, error) {
+	if text == "" {
+		return nil, errors.New("search text is required")
+	}
+
+	var projectIDs []string
+	if projectID != "" {
+		if err := s.projectService.CheckAccess(caller, projectID); err != nil {
+			return nil, err
+		}
+		projectIDs = []string{projectID}
+	} else {
+		ids, err := s.projectService.AccessibleProjectIDs(caller)
+		if err != nil {
+			return nil, err
+		}
+		projectIDs = ids
+	}
+
+	taskMatches, err := s.taskRepository.SearchText(text, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	commentMatches, err := s.commentRepository.SearchText(text, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the matched comments, then every task matched directly or through
+	// a comment, at once
+	commentIDs := make([]string, len(commentMatches))
+	for i, match := range commentMatches {
+		commentIDs[i] = match.ID
+	}
+	comments, err := s.commentRepository.ListByIDs(commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	commentsByID := make(map[string]*models.Comment, len(comments))
+	taskIDs := make([]string, 0, len(taskMatches)+len(comments))
+	for _, match := range taskMatches {
+		taskIDs = append(taskIDs, match.ID)
+	}
+	for _, comment := range comments {
+		commentsByID[comment.ID] = comment
+		taskIDs = append(taskIDs, comment.TaskID)
+	}
+	slices.Sort(taskIDs)
+	tasks, err := s.taskRepository.ListByIDs(slices.Compact(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	tasksByID := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
+	results := make(map[string]*SearchResult)
+	for _, match := range taskMatches {
+		task, ok := tasksByID[match.ID]
+		if !ok {
+			continue
+		}
+		result := &SearchResult{Task: task, Score: match.Score, Highlights: make(map[string]string)}
+		if snippet, ok := textsearch.Highlight(task.Title, text, snippetLength); ok {
+			result.Highlights["title"] = snippet
+		}
+		if snippet, ok := textsearch.Highlight(task.Description, text, snippetLength); ok {
+			result.Highlights["description"] = snippet
+		}
+		results[task.ID] = result
+	}
+
+	for _, match := range commentMatches {
+		comment, ok := commentsByID[match.ID]
+		if !ok {
+			continue
+		}
+
+		result, exists := results[comment.TaskID]
+		if !exists {
+			task, ok := tasksByID[comment.TaskID]
+			if !ok {
+				continue
+			}
+			result = &SearchResult{Task: task, Highlights: make(map[string]string)}
+			results[task.ID] = result
+		}
+
+		result.Score += match.Score * commentWeight
+		// Matches arrive best first, so keep the snippet of the best comment.
+		if _, ok := result.Highlights["comment"]; !ok {
+			if snippet, ok := textsearch.Highlight(comment.Body, text, snippetLength); ok {
+				result.Highlights["comment"] = snippet
+			}
+		}
+	}
+
+	ranked := make([]*SearchResult, 0, len(results))
+	for _, result := range results {
+		ranked = append(ranked, result)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Task.ID < ranked[j].Task.ID
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type SprintRepository interface {
+	Create(sprint *models.Sprint) error
+	GetByID(id string) (*models.Sprint, error)
+	Update(sprint *models.Sprint) error
+	Delete(id string) error
+	ListByProject(projectID string) ([]*models.Sprint, error)
+	ListByStatus(status models.SprintStatus) ([]*models.Sprint, error)
+}
+
+type SprintService struct {
+	repository        SprintRepository
+	projectRepository ProjectRepository
+	taskRepository    TaskRepository
+	burndownService   *BurndownService
+}
+
+func NewSprintService(repository SprintRepository, projectRepository ProjectRepository, taskRepository TaskRepository, burndownService *BurndownService) *SprintService {
+	return &SprintService{
+		repository:        repository,
+		projectRepository: projectRepository,
+		taskRepository:    taskRepository,
+		burndownService:   burndownService,
+	}
+}
+
+func (s *SprintService) CreateSprint(projectID, name string, startDate, endDate time.Time) (*models.Sprint, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("sprint name is required")
+	}
+	if !endDate.After(startDate) {
+		return nil, errors.New("sprint must end after it starts")
+	}
+	if _, err := s.projectRepository.GetByID(projectID); err != nil {
+		return nil, err
+	}
+
+	sprint := &models.Sprint{
+		ID:        generateID(),
+		ProjectID: projectID,
+		Name:      name,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Status:    models.SprintCreated,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.repository.Create(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+func (s *SprintService) GetSprint(id string) (*models.Sprint, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *SprintService) ListSprints(projectID string) ([]*models.Sprint, error) {
+	return s.repository.ListByProject(projectID)
+}
+
+// DeleteSprint deletes a sprint that has not started. Its tasks go back to the
+// backlog.
+func (s *SprintService) DeleteSprint(id string) error {
+	sprint, err := s.repository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if sprint.Status != models.SprintCreated {
+		return errors.New("only a sprint that has not started can be deleted")
+	}
+
+	tasks, err := s.taskRepository.ListBySprint(sprint.ID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		task.SprintID = nil
+		task.UpdatedAt = time.Now()
+		if err := s.taskRepository.Update(task); err != nil {
+			return err
+		}
+	}
+	return s.repository.Delete(sprint.ID)
+}
+
+// StartSprint makes a sprint the active one of its project. A project has at
+// most one active sprint.
+func (s *SprintService) StartSprint(id string) (*models.Sprint, error) {
+	sprint, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != models.SprintCreated {
+		return nil, errors.New("only a sprint that has not started can be started")
+	}
+
+	sprints, err := s.repository.ListByProject(sprint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range sprints {
+		if other.Status == models.SprintActive {
+			return nil, errors.New("project already has an active sprint")
+		}
+	}
+
+	now := time.Now()
+	sprint.Status = models.SprintActive
+	sprint.StartedAt = &now
+	sprint.UpdatedAt = now
+	if err := s.repository.Update(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// CloseSprint ends an active sprint. Its final snapshot is recorded first,
+// then its unfinished tasks go back to the backlog.
+func (s *SprintService) CloseSprint(id string) (*models.Sprint, error) {
+	sprint, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != models.SprintActive {
+		return nil, errors.New("only an active sprint can be closed")
+	}
+
+	now := time.Now()
+	sprint.Status = models.SprintClosed
+	sprint.ClosedAt = &now
+	sprint.UpdatedAt = now
+	if err := s.repository.Update(sprint); err != nil {
+		return nil, err
+	}
+	if err := s.burndownService.recordSnapshot(sprint, now); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepository.ListBySprint(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if task.Status == models.Done {
+			continue
+		}
+		task.SprintID = nil
+		task.UpdatedAt = time.Now()
+		if err := s.taskRepository.Update(task); err != nil {
+			return nil, err
+		}
+	}
+	return sprint, nil
+}
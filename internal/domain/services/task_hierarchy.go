@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// OrphanPolicy decides what happens to the children of a deleted task.
+type OrphanPolicy string
+
+const (
+	// OrphansRejected refuses to delete a task that still has children.
+	OrphansRejected OrphanPolicy = ""
+	// OrphansCascade deletes the task together with all its descendants.
+	OrphansCascade OrphanPolicy = "cascade"
+	// OrphansReparent hands the children over to another parent.
+	OrphansReparent OrphanPolicy = "reparent"
+)
+
+var ErrTaskHasChildren = errors.New("task has children: choose to cascade the delete or reparent them")
+
+// typeRank orders task types in the hierarchy; a parent must outrank its children.
+var typeRank = map[models.TaskType]int{
+	models.TypeSubtask: 1,
+	models.TypeTask:    2,
+	models.TypeStory:   2,
+	models.TypeEpic:    3,
+}
+
+// validateHierarchy checks a task's type and parent: the parent must exist in
+// the same project, outrank the task and not be one of its descendants, and
+// the task must still outrank its own children.
+func (s *TaskService) validateHierarchy(task *models.Task) error {
+	if task.Type == "" {
+		task.Type = models.TypeTask
+	}
+	rank, ok := typeRank[task.Type]
+	if !ok {
+		return fmt.Errorf("invalid task type %q", task.Type)
+	}
+	if task.Type == models.TypeSubtask && task.ParentID == "" {
+		return errors.New("a subtask needs a parent")
+	}
+
+	if task.ParentID != "" {
+		parent, err := findTask(s.repository, task.ParentID)
+		if err != nil {
+			return errors.New("parent task not found")
+		}
+		task.ParentID = parent.ID
+
+		if parent.ProjectID != task.ProjectID {
+			return errors.New("parent task belongs to another project")
+		}
+		if typeRank[parent.Type] <= rank {
+			return fmt.Errorf("type %s cannot be the parent of type %s", parent.Type, task.Type)
+		}
+		if err := s.checkAncestry(task.ID, parent); err != nil {
+			return err
+		}
+	}
+
+	if task.ID == "" {
+		return nil
+	}
+	children, err := s.repository.ListByParent(task.ID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if typeRank[child.Type] >= rank {
+			return fmt.Errorf("type %s cannot be the parent of type %s", task.Type, child.Type)
+		}
+	}
+	return nil
+}
+
+// checkAncestry walks up from parent and fails if it meets taskID, which
+// would make the task its own ancestor.
+func (s *TaskService) checkAncestry(taskID string, parent *models.Task) error {
+	seen := map[string]bool{}
+	for current := parent; current != nil; {
+		if current.ID == taskID {
+			return errors.New("parent would create a cycle in the task hierarchy")
+		}
+		if current.ParentID == "" || seen[current.ID] {
+			return nil
+		}
+		seen[current.ID] = true
+
+		next, err := s.repository.GetByID(current.ParentID)
+		if err != nil {
+			return nil
+		}
+		current = next
+	}
+	return nil
+}
+
+// withProgress fills in the rollup of a task's direct children: how many are
+// done and how many story points they add up to.
+func (s *TaskService) withProgress(task *models.Task) (*models.Task, error) {
+	children, err := s.repository.ListByParent(task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(children) == 0 {
+		task.Progress = nil
+		return task, nil
+	}
+
+	progress := &models.TaskProgress{Children: len(children)}
+	for _, child := range children {
+		points := 0
+		if child.StoryPoints != nil {
+			points = *child.StoryPoints
+		}
+		progress.StoryPoints += points
+		if child.Status == models.Done {
+			progress.Done++
+			progress.DonePoints += points
+		}
+	}
+	progress.DoneRatio = float64(progress.Done) / float64(progress.Children)
+
+	task.Progress = progress
+	return task, nil
+}
+
+// ListChildren returns the direct children of a task, each with its own rollup.
+func (s *TaskService) ListChildren(idOrKey string) ([]*models.Task, error) {
+	task, err := findTask(s.repository, idOrKey)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.repository.ListByParent(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if _, err := s.withProgress(child); err != nil {
+			return nil, err
+		}
+	}
+	return children, nil
+}
+
+// descendants returns every task below task in the hierarchy, deepest last.
+func (s *TaskService) descendants(task *models.Task) ([]*models.Task, error) {
+	return descendants(s.repository, task)
+}
+
+func descendants(repository TaskRepository, task *models.Task) ([]*models.Task, error) {
+	var result []*models.Task
+	queue := []*models.Task{task}
+	for len(queue) > 0 {
+		children, err := repository.ListByParent(queue[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue[1:], children...)
+		result = append(result, children...)
+	}
+	return result, nil
+}
+
+// reparentChildren moves the children of task under newParentID, or under
+// task's own parent when newParentID is empty. Every move is validated before
+// any is applied.
+func (s *TaskService) reparentChildren(task *models.Task, newParentID string) error {
+	if newParentID == "" {
+		newParentID = task.ParentID
+	}
+	if newParentID != "" {
+		parent, err := findTask(s.repository, newParentID)
+		if err != nil {
+			return errors.New("new parent task not found")
+		}
+		if parent.ID == task.ID {
+			return errors.New("cannot reparent children onto the task being deleted")
+		}
+		newParentID = parent.ID
+	}
+
+	children, err := s.repository.ListByParent(task.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		moved := *child
+		moved.ParentID = newParentID
+		if err := s.validateHierarchy(&moved); err != nil {
+			return fmt.Errorf("cannot reparent %s: %w", taskLabel(child), err)
+		}
+	}
+	for _, child := range children {
+		child.ParentID = newParentID
+		if err := s.repository.Update(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashTree moves task and its descendants to the trash together, so that
+// restoring task brings them all back.
+func (s *TaskService) trashTree(task *models.Task, deletedBy string) error {
+	descendants, err := s.descendants(task)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, d := range descendants {
+		if err := trashTask(s.repository, d, deletedBy, now, task.ID); err != nil {
+			return err
+		}
+	}
+	return trashTask(s.repository, task, deletedBy, now, "")
+}
+
+// purgeTree deletes task, its descendants and the tasks trashed with it,
+// deepest first, dropping their links from the tasks on the other end.
+func purgeTree(repository TaskRepository, task *models.Task) error {
+	tasks, err := descendants(repository, task)
+	if err != nil {
+		return err
+	}
+	trashed, err := trashedWith(repository, task.ProjectID, task.ID)
+	if err != nil {
+		return err
+	}
+	tasks = append(tasks, trashed...)
+
+	slices.Reverse(tasks)
+	for _, t := range append(tasks, task) {
+		if err := unlinkAll(repository, t); err != nil {
+			return err
+		}
+		if err := repository.Delete(t.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func taskLabel(task *models.Task) string {
+	if task.Key != "" {
+		return task.Key
+	}
+	return task.ID
+}
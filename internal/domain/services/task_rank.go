@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/rank"
+)
+
+// maxRankAttempts bounds the retries of a reorder that lost a race with
+// another reorder of the same tasks.
+const maxRankAttempts = 5
+
+var ErrRankConflict = errors.New("tasks were reordered concurrently, try again")
+
+// RankTask moves a task to just before beforeID, or just after afterID, in
+// its project's order; exactly one of them must be given. Only the moved task
+// is written. A reorder that races another is retried against fresh ranks, and
+// the list is rebalanced first when there is no room at the target spot.
+func (s *TaskService) RankTask(taskID, beforeID, afterID string) (*models.Task, error) {
+	if (beforeID == "") == (afterID == "") {
+		return nil, errors.New("give either a task to move before or a task to move after")
+	}
+
+	task, err := findTask(s.repository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	anchor, err := findTask(s.repository, beforeID+afterID)
+	if err != nil {
+		return nil, errors.New("anchor task not found")
+	}
+	if anchor.ID == task.ID {
+		return nil, errors.New("a task cannot be moved relative to itself")
+	}
+	if anchor.ProjectID != task.ProjectID {
+		return nil, errors.New("anchor task belongs to another project")
+	}
+
+	for range maxRankAttempts {
+		if task, err = s.repository.GetByID(task.ID); err != nil {
+			return nil, err
+		}
+		if anchor, err = s.repository.GetByID(anchor.ID); err != nil {
+			return nil, errors.New("anchor task not found")
+		}
+
+		r, err := rankBeside(s.repository, anchor, beforeID != "")
+		if errors.Is(err, rank.ErrNoRoom) {
+			if err := rebalanceRanks(s.repository, task.ProjectID); err != nil && !errors.Is(err, ErrRankConflict) {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := s.repository.SetRank(task.ID, task.Rank, r)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		// The anchor may have moved while the new rank was computed; if it
+		// did, place the task again next to where the anchor is now.
+		moved, err := anchorMoved(s.repository, anchor)
+		if err != nil {
+			return nil, err
+		}
+		if !moved {
+			task.Rank = r
+			return task, nil
+		}
+	}
+	return nil, ErrRankConflict
+}
+
+// rankBeside returns a rank just before or just after anchor. It fails with
+// rank.ErrNoRoom when the list needs a rebalance first, including when the
+// anchor has no rank yet.
+func rankBeside(repository TaskRepository, anchor *models.Task, before bool) (string, error) {
+	if anchor.Rank == "" {
+		return "", rank.ErrNoRoom
+	}
+
+	if before {
+		prev, err := repository.AdjacentRank(anchor.ProjectID, anchor.Rank, true)
+		if err != nil {
+			return "", err
+		}
+		return rank.Between(prev, anchor.Rank)
+	}
+	next, err := repository.AdjacentRank(anchor.ProjectID, anchor.Rank, false)
+	if err != nil {
+		return "", err
+	}
+	return rank.Between(anchor.Rank, next)
+}
+
+// anchorMoved reports whether anchor's rank changed since it was read.
+func anchorMoved(repository TaskRepository, anchor *models.Task) (bool, error) {
+	current, err := repository.GetByID(anchor.ID)
+	if err != nil {
+		return false, errors.New("anchor task not found")
+	}
+	return current.Rank != anchor.Rank, nil
+}
+
+// RebalanceRanks respreads the ranks of a project's tasks evenly once some
+// have grown long from repeated reordering, collide, or are missing. Tasks
+// are rewritten from last to first into a new rank generation, so the order
+// holds at every step; if a task is reordered meanwhile the rebalance stops
+// with ErrRankConflict, leaving a consistent order to finish later.
+func (s *TaskService) RebalanceRanks(projectID string) error {
+	return rebalanceRanks(s.repository, projectID)
+}
+
+func rebalanceRanks(repository TaskRepository, projectID string) error {
+	tasks, err := repository.ListByProject(projectID)
+	if err != nil {
+		return err
+	}
+	sortByRank(tasks)
+	if !needsRebalance(tasks) {
+		return nil
+	}
+
+	current := make([]string, len(tasks))
+	for i, task := range tasks {
+		current[i] = task.Rank
+	}
+	ranks := rank.Spread(current, len(tasks))
+
+	for i := len(tasks) - 1; i >= 0; i-- {
+		ok, err := repository.SetRank(tasks[i].ID, tasks[i].Rank, ranks[i])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrRankConflict
+		}
+	}
+	return nil
+}
+
+// RebalanceAllRanks rebalances every project that needs it, skipping archived
+// ones. It is meant to run periodically.
+func (s *TaskService) RebalanceAllRanks() error {
+	projects, err := s.projectRepository.List()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, project := range projects {
+		if project.ArchivedAt != nil {
+			continue
+		}
+		if err := s.RebalanceRanks(project.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func needsRebalance(tasks []*models.Task) bool {
+	for i, task := range tasks {
+		if task.Rank == "" || rank.Crowded(task.Rank) {
+			return true
+		}
+		if i > 0 && tasks[i-1].Rank == task.Rank {
+			return true
+		}
+	}
+	return false
+}
+
+// rankAtEnd returns a rank after every ranked task of a project.
+func rankAtEnd(repository TaskRepository, projectID string) (string, error) {
+	last, err := repository.LastRank(projectID)
+	if err != nil {
+		return "", err
+	}
+	return rank.Between(last, "")
+}
+
+// sortByRank orders tasks by rank. Unranked tasks come last, oldest first,
+// and ties are broken by ID so that the order is stable across reads.
+func sortByRank(tasks []*models.Task) {
+	slices.SortFunc(tasks, func(a, b *models.Task) int {
+		switch {
+		case a.Rank == "" && b.Rank != "":
+			return 1
+		case a.Rank != "" && b.Rank == "":
+			return -1
+		case a.Rank != b.Rank:
+			return strings.Compare(a.Rank, b.Rank)
+		case !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -1,43 +1,381 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+	"go-project-manager-backend/internal/domain/textsearch"
 )
 
+// TaskRepository stores tasks. Tasks in the trash are skipped by every
+// method but the trash ones, Update and Delete.
 type TaskRepository interface {
 	Create(task *models.Task) error
 	GetByID(id string) (*models.Task, error)
+	GetByKey(key string) (*models.Task, error)
+	// ListByIDs returns the tasks with the given IDs, skipping missing ones,
+	// in no particular order.
+	ListByIDs(ids []string) ([]*models.Task, error)
 	Update(task *models.Task) error
 	Delete(id string) error
 	ListByProject(projectID string) ([]*models.Task, error)
 	ListByAssignee(assigneeID string) ([]*models.Task, error)
 	ListBySprint(sprintID string) ([]*models.Task, error)
+	ListByParent(parentID string) ([]*models.Task, error)
 	ListBacklog(projectID string) ([]*models.Task, error)
+	ListByQuery(q *query.Query) ([]*models.Task, error)
+	SearchText(text string, projectIDs []string) ([]textsearch.Match, error)
+	RemoveLabel(labelID string) error
+	RemoveCustomField(fieldID string) error
+	RemoveTeam(teamID string) error
+	// GetTrashed returns a task in the trash.
+	GetTrashed(id string) (*models.Task, error)
+	// ListTrash returns the tasks of a project in the trash.
+	ListTrash(projectID string) ([]*models.Task, error)
+	// ListTrashedBefore returns the tasks of any project trashed before cutoff.
+	ListTrashedBefore(cutoff time.Time) ([]*models.Task, error)
+	// LastRank returns the highest rank among a project's live tasks, or ""
+	// if none is ranked.
+	LastRank(projectID string) (string, error)
+	// AdjacentRank returns the closest rank before (or after) rank among a
+	// project's live tasks, or "" if there is none.
+	AdjacentRank(projectID, rank string, before bool) (string, error)
+	// SetRank changes a task's rank only if it is still expected, and reports
+	// whether it did.
+	SetRank(taskID, expected, rank string) (bool, error)
+	// SetPosition changes a task's status and rank together, under the same
+	// condition as SetRank.
+	SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error)
+	// WithTransaction runs fn against a repository whose changes are kept
+	// only if fn succeeds, all together.
+	WithTransaction(fn func(repository TaskRepository) error) error
+}
+
+// CounterRepository allocates unique, increasing sequence numbers, atomically
+// per name. They are not gap-free: a number taken by a task that is then
+// rolled back or fails to save is never handed out again.
+type CounterRepository interface {
+	Next(name string) (int, error)
 }
 
 type TaskService struct {
-	repository TaskRepository
+	repository        TaskRepository
+	projectRepository ProjectRepository
+	counterRepository CounterRepository
+	labelRepository   LabelRepository
+	sprintRepository  SprintRepository
+	userRepository    UserRepository
+	customFields      CustomFieldRepository
+	teamRepository    TeamRepository
+}
+
+func NewTaskService(repository TaskRepository, projectRepository ProjectRepository, counterRepository CounterRepository, labelRepository LabelRepository, sprintRepository SprintRepository, userRepository UserRepository, customFields CustomFieldRepository, teamRepository TeamRepository) *TaskService {
+	return &TaskService{
+		repository:        repository,
+		projectRepository: projectRepository,
+		counterRepository: counterRepository,
+		labelRepository:   labelRepository,
+		sprintRepository:  sprintRepository,
+		userRepository:    userRepository,
+		customFields:      customFields,
+		teamRepository:    teamRepository,
+	}
+}
+
+// TaskDetails holds the attributes of a task that are optional when creating
+// it. Type defaults to a plain task.
+type TaskDetails struct {
+	Type models.TaskType
+	// TeamID assigns the task to a team; its assignee, if any, must be one of
+	// the team's members.
+	TeamID      string
+	ParentID    string
+	Priority    models.TaskPriority
+	StoryPoints *int
+	DueDate     *time.Time
+	DueTimezone string
+	LabelIDs    []string
+	// OriginalEstimateMinutes also sets the remaining estimate when that is
+	// not given.
+	OriginalEstimateMinutes  *int
+	RemainingEstimateMinutes *int
+	Checklist                []ChecklistItemInput
+	// CustomFields holds raw values, as decoded from JSON, by custom field ID
+	// or name. Fields left out take their default.
+	CustomFields map[string]any
+}
+
+// TaskFilter narrows a project's task list. Zero-valued fields are ignored.
+type TaskFilter struct {
+	Status      models.TaskStatus
+	AssigneeID  string
+	TeamID      string
+	Priority    models.TaskPriority
+	StoryPoints *int
+	LabelID     string
+	DueBefore   *time.Time
+	DueAfter    *time.Time
+	// CustomFields holds raw filter values by custom field ID or name.
+	CustomFields map[string]string
+}
+
+// UpdateOptions relaxes the workflow rules checked when a task is updated.
+type UpdateOptions struct {
+	// OverrideBlockers lets a task start while tasks blocking it are still open.
+	OverrideBlockers bool
+}
+
+// storyPointScale is the Fibonacci scale story point estimates must use.
+var storyPointScale = []int{0, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+// ParseDueDate reads a due date given as an RFC 3339 timestamp or as a
+// calendar date (2006-01-02). A calendar date means the end of that day in
+// timezone, or in UTC when timezone is empty. An empty value means no due date.
+func ParseDueDate(value, timezone string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	loc := time.UTC
+	if timezone != "" {
+		l, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", timezone)
+		}
+		loc = l
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.In(loc)
+		return &t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return nil, errors.New("due date must be a date (2006-01-02) or an RFC 3339 timestamp")
+	}
+	end := day.AddDate(0, 0, 1).Add(-time.Second)
+	return &end, nil
+}
+
+// validate checks the hierarchy, status and planning attributes of a task
+// before it is stored.
+func (s *TaskService) validate(task *models.Task) error {
+	if err := s.validateHierarchy(task); err != nil {
+		return err
+	}
+	if !task.Status.Valid() {
+		return fmt.Errorf("invalid status %q", task.Status)
+	}
+	if !task.Priority.Valid() {
+		return errors.New("invalid priority")
+	}
+	if task.StoryPoints != nil && !slices.Contains(storyPointScale, *task.StoryPoints) {
+		return fmt.Errorf("story points must be one of %v", storyPointScale)
+	}
+	for _, estimate := range []*int{task.OriginalEstimateMinutes, task.RemainingEstimateMinutes} {
+		if estimate != nil && *estimate < 0 {
+			return errors.New("estimates cannot be negative")
+		}
+	}
+	if task.DueTimezone != "" {
+		if _, err := time.LoadLocation(task.DueTimezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", task.DueTimezone)
+		}
+	}
+	if task.TeamID != "" {
+		team, err := s.teamRepository.GetByID(task.TeamID)
+		if err != nil {
+			return err
+		}
+		if task.AssigneeID != "" && !slices.Contains(team.MemberIDs, task.AssigneeID) {
+			return fmt.Errorf("assignee is not a member of team %s", team.Name)
+		}
+	}
+
+	slices.Sort(task.LabelIDs)
+	task.LabelIDs = slices.Compact(task.LabelIDs)
+	for _, id := range task.LabelIDs {
+		label, err := s.labelRepository.GetByID(id)
+		if err != nil || label.ProjectID != task.ProjectID {
+			return fmt.Errorf("label %q does not belong to the task's project", id)
+		}
+	}
+	return s.validateCustomFields(task)
+}
+
+// validateCustomFields checks that a task only has values for the custom
+// fields of its project, and has one for every required field.
+func (s *TaskService) validateCustomFields(task *models.Task) error {
+	fields, err := s.customFields.ListByProject(task.ProjectID)
+	if err != nil {
+		return err
+	}
+	for id := range task.CustomFields {
+		if !slices.ContainsFunc(fields, func(f *models.CustomField) bool { return f.ID == id }) {
+			return fmt.Errorf("%w: %q is not a field of the task's project", ErrInvalidCustomField, id)
+		}
+	}
+
+	sortCustomFields(fields)
+	for _, field := range fields {
+		if _, ok := task.CustomFields[field.ID]; field.Required && !ok {
+			return fmt.Errorf("%w: %s is required", ErrInvalidCustomField, field.Name)
+		}
+	}
+	return nil
+}
+
+// SetCustomFields sets the custom field values of a task, given raw as
+// decoded from JSON by field ID or name, without saving it. A nil or blank
+// value clears a field.
+func (s *TaskService) SetCustomFields(task *models.Task, values map[string]any) error {
+	return s.setCustomFields(task, values, false)
+}
+
+func (s *TaskService) setCustomFields(task *models.Task, values map[string]any, defaults bool) error {
+	if len(values) == 0 && !defaults {
+		return nil
+	}
+	fields, err := s.customFields.ListByProject(task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	custom := maps.Clone(task.CustomFields)
+	if custom == nil {
+		custom = make(map[string]models.CustomFieldValue)
+	}
+	for key, raw := range values {
+		field := findCustomField(fields, key)
+		if field == nil {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidCustomField, key)
+		}
+		value, set, err := customFieldValue(s.userRepository, field, raw)
+		if err != nil {
+			return err
+		}
+		delete(custom, field.ID)
+		if set {
+			custom[field.ID] = value
+		}
+	}
+	if defaults {
+		for _, field := range fields {
+			if _, ok := custom[field.ID]; !ok && field.Default != nil {
+				custom[field.ID] = *field.Default
+			}
+		}
+	}
+
+	task.CustomFields = custom
+	if len(custom) == 0 {
+		task.CustomFields = nil
+	}
+	return nil
+}
+
+var taskKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]{1,9}-[0-9]+$`)
+
+// findTask looks a task up by ID or by its human-readable key, including keys
+// it had before being moved to another project.
+func findTask(repository TaskRepository, idOrKey string) (*models.Task, error) {
+	if taskKeyPattern.MatchString(idOrKey) {
+		return repository.GetByKey(strings.ToUpper(idOrKey))
+	}
+	return repository.GetByID(idOrKey)
 }
 
-func NewTaskService(repository TaskRepository) *TaskService {
-	return &TaskService{repository: repository}
+// nextKey allocates the next sequential task key in project, such as API-42.
+func (s *TaskService) nextKey(project *models.Project) (string, error) {
+	if project.Key == "" {
+		return "", nil
+	}
+
+	n, err := s.counterRepository.Next("task_key:" + project.ID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%d", project.Key, n), nil
 }
 
-func (s *TaskService) CreateTask(title, description, projectID, assigneeID string) (*models.Task, error) {
+// CreateTask creates a task in a project. A task for a team may leave the
+// project out to go to the team's default project.
+func (s *TaskService) CreateTask(title, description, projectID, assigneeID string, details TaskDetails) (*models.Task, error) {
+	if projectID == "" && details.TeamID != "" {
+		team, err := s.teamRepository.GetByID(details.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if team.DefaultProjectID == "" {
+			return nil, errors.New("team has no default project")
+		}
+		projectID = team.DefaultProjectID
+	}
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.nextKey(project)
+	if err != nil {
+		return nil, err
+	}
+	r, err := rankAtEnd(s.repository, projectID)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &models.Task{
-		ID:          generateID(),
-		Title:       title,
-		Description: description,
-		ProjectID:   projectID,
-		AssigneeID:  assigneeID,
-		SprintID:    nil,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Key:                      key,
+		Type:                     details.Type,
+		ParentID:                 details.ParentID,
+		Title:                    title,
+		Description:              description,
+		Status:                   models.ToDo,
+		ProjectID:                projectID,
+		AssigneeID:               assigneeID,
+		TeamID:                   details.TeamID,
+		SprintID:                 nil,
+		Rank:                     r,
+		Priority:                 details.Priority,
+		StoryPoints:              details.StoryPoints,
+		DueDate:                  details.DueDate,
+		DueTimezone:              details.DueTimezone,
+		LabelIDs:                 details.LabelIDs,
+		CreatedAt:                time.Now(),
+		UpdatedAt:                time.Now(),
+		OriginalEstimateMinutes:  details.OriginalEstimateMinutes,
+		RemainingEstimateMinutes: details.RemainingEstimateMinutes,
+	}
+	if task.RemainingEstimateMinutes == nil && task.OriginalEstimateMinutes != nil {
+		remaining := *task.OriginalEstimateMinutes
+		task.RemainingEstimateMinutes = &remaining
+	}
+	for _, input := range details.Checklist {
+		item, err := newChecklistItem(input)
+		if err != nil {
+			return nil, err
+		}
+		task.Checklist = append(task.Checklist, item)
+	}
+	task.ChecklistProgress = checklistProgress(task.Checklist)
+	if err := s.setCustomFields(task, details.CustomFields, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(task); err != nil {
+		return nil, err
 	}
 
-	err := s.repository.Create(task)
+	task.ID = generateID()
+	err = s.repository.Create(task)
 	if err != nil {
 		return nil, err
 	}
@@ -45,32 +383,148 @@ func (s *TaskService) CreateTask(title, description, projectID, assigneeID strin
 	return task, nil
 }
 
-func (s *TaskService) GetTask(id string) (*models.Task, error) {
-	return s.repository.GetByID(id)
+// GetTask returns a task by ID or key, with the rollup of its children.
+func (s *TaskService) GetTask(idOrKey string) (*models.Task, error) {
+	task, err := findTask(s.repository, idOrKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.withProgress(task)
 }
 
-func (s *TaskService) UpdateTask(task *models.Task) error {
+// UpdateTask saves changes to a task. Links and the checklist are left as
+// stored, since they only change through the DependencyService and the
+// ChecklistService. A task cannot be started while its blockers are open
+// unless opts override it, nor done while its required checklist items are
+// unchecked, where the project asks for that.
+func (s *TaskService) UpdateTask(task *models.Task, opts UpdateOptions) error {
+	previous, err := s.repository.GetByID(task.ID)
+	if err != nil {
+		return err
+	}
+	task.Links = previous.Links
+	task.Checklist = previous.Checklist
+	task.ChecklistProgress = previous.ChecklistProgress
+
+	if err := s.validate(task); err != nil {
+		return err
+	}
+	if task.Status == models.InProgress && previous.Status != models.InProgress && !opts.OverrideBlockers {
+		if err := checkBlockers(s.repository, task); err != nil {
+			return err
+		}
+	}
+	if task.Status == models.Done && previous.Status != models.Done {
+		if err := checkChecklist(s.projectRepository, task); err != nil {
+			return err
+		}
+	}
+
 	task.UpdatedAt = time.Now()
 	return s.repository.Update(task)
 }
 
-func (s *TaskService) DeleteTask(id string) error {
-	return s.repository.Delete(id)
+// DeleteTask moves a task to the trash. A task with children is only deleted
+// when the policy says what to do with them; with OrphansCascade they go to
+// the trash with it, and with OrphansReparent they move under newParentID, or
+// under the deleted task's parent when that is empty. The children and the
+// task change together or not at all.
+func (s *TaskService) DeleteTask(deletedBy, idOrKey string, policy OrphanPolicy, newParentID string) error {
+	return s.InTransaction(func(tx *TaskService) error {
+		return tx.deleteTask(deletedBy, idOrKey, policy, newParentID)
+	})
 }
 
-func (s *TaskService) AssignToSprint(taskID, sprintID string) error {
-	task, err := s.repository.GetByID(taskID)
+func (s *TaskService) deleteTask(deletedBy, idOrKey string, policy OrphanPolicy, newParentID string) error {
+	task, err := findTask(s.repository, idOrKey)
 	if err != nil {
 		return err
 	}
 
+	children, err := s.repository.ListByParent(task.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(children) > 0 {
+		switch policy {
+		case OrphansCascade:
+			return s.trashTree(task, deletedBy)
+		case OrphansReparent:
+			if err := s.reparentChildren(task, newParentID); err != nil {
+				return err
+			}
+		case OrphansRejected:
+			return ErrTaskHasChildren
+		default:
+			return fmt.Errorf("invalid children policy %q", policy)
+		}
+	}
+
+	return trashTask(s.repository, task, deletedBy, time.Now(), "")
+}
+
+// PurgeTask deletes a task for good, whether live or in the trash, along with
+// its descendants and the tasks trashed with it, all together.
+func (s *TaskService) PurgeTask(idOrKey string) error {
+	task, err := findTask(s.repository, idOrKey)
+	if err != nil {
+		if task, err = s.repository.GetTrashed(idOrKey); err != nil {
+			return err
+		}
+	}
+	return s.repository.WithTransaction(func(tx TaskRepository) error {
+		return purgeTree(tx, task)
+	})
+}
+
+// InTransaction runs fn with a copy of the service whose changes to tasks are
+// kept only if fn succeeds, all together.
+func (s *TaskService) InTransaction(fn func(tx *TaskService) error) error {
+	return s.repository.WithTransaction(func(repository TaskRepository) error {
+		tx := *s
+		tx.repository = repository
+		return fn(&tx)
+	})
+}
+
+// AssignToSprint adds a task to a sprint of its project. When that takes the
+// assignee past their capacity, it returns their workload as a warning, or
+// fails with ErrCapacityExceeded if the project blocks such assignments.
+func (s *TaskService) AssignToSprint(taskID, sprintID string) (*MemberWorkload, error) {
+	task, err := findTask(s.repository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	sprint, err := s.sprintRepository.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.ProjectID != task.ProjectID {
+		return nil, errors.New("sprint belongs to another project")
+	}
+	if sprint.Status == models.SprintClosed {
+		return nil, errors.New("sprint is closed")
+	}
+	project, err := s.projectRepository.GetByID(task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	warning, err := checkCapacity(s.userRepository, s.repository, project, sprint, task)
+	if err != nil {
+		return nil, err
+	}
+
 	task.SprintID = &sprintID
 	task.UpdatedAt = time.Now()
-	return s.repository.Update(task)
+	if err := s.repository.Update(task); err != nil {
+		return nil, err
+	}
+	return warning, nil
 }
 
 func (s *TaskService) MoveToBacklog(taskID string) error {
-	task, err := s.repository.GetByID(taskID)
+	task, err := findTask(s.repository, taskID)
 	if err != nil {
 		return err
 	}
@@ -80,18 +534,168 @@ func (s *TaskService) MoveToBacklog(taskID string) error {
 	return s.repository.Update(task)
 }
 
+// MoveToProject moves a task and its descendants to another project. Each
+// moved task gets a fresh key in the target project and keeps its old one as
+// a redirect, and goes to the end of the target project's order. It also
+// leaves its sprint, and its labels, custom fields and status are carried
+// over to those of the target project, with statusMap choosing the status
+// for tasks of each status. The moved task is detached from its parent, and
+// a subtask becomes a plain task. Its history records the project and key it
+// came from. The task and its descendants move together or not at all.
+func (s *TaskService) MoveToProject(taskID, projectID string, statusMap map[models.TaskStatus]models.TaskStatus) (*models.Task, error) {
+	var moved *models.Task
+	err := s.InTransaction(func(tx *TaskService) error {
+		task, err := tx.moveToProject(taskID, projectID, statusMap)
+		moved = task
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (s *TaskService) moveToProject(taskID, projectID string, statusMap map[models.TaskStatus]models.TaskStatus) (*models.Task, error) {
+	task, err := findTask(s.repository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID == projectID {
+		return nil, errors.New("task already belongs to this project")
+	}
+
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.newProjectTransfer(task.ProjectID, project, statusMap)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.descendants(task)
+	if err != nil {
+		return nil, err
+	}
+
+	task.ParentID = ""
+	if task.Type == models.TypeSubtask {
+		task.Type = models.TypeTask
+	}
+	for _, t := range append([]*models.Task{task}, descendants...) {
+		if err := s.moveOne(t, transfer); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+func (s *TaskService) moveOne(task *models.Task, transfer *projectTransfer) error {
+	key, err := s.nextKey(transfer.to)
+	if err != nil {
+		return err
+	}
+
+	if task.Key != "" {
+		task.PreviousKeys = append(task.PreviousKeys, task.Key)
+	}
+	task.Key = key
+	transfer.apply(task)
+	task.UpdatedAt = time.Now()
+
+	r, err := rankAtEnd(s.repository, transfer.to.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.Update(task); err != nil {
+		return err
+	}
+	ok, err := s.repository.SetRank(task.ID, task.Rank, r)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRankConflict
+	}
+	task.Rank = r
+	return nil
+}
+
 func (s *TaskService) ListTasksByProject(projectID string) ([]*models.Task, error) {
 	return s.repository.ListByProject(projectID)
 }
 
+// ListTasks returns the tasks of a project that match filter.
+func (s *TaskService) ListTasks(projectID string, filter TaskFilter) ([]*models.Task, error) {
+	exprs := []query.Expr{
+		query.Compare("project", query.OpEqual, query.Value{Text: projectID}),
+	}
+	if filter.Status != "" {
+		exprs = append(exprs, query.Compare("status", query.OpEqual, query.Value{Text: string(filter.Status)}))
+	}
+	if filter.AssigneeID != "" {
+		exprs = append(exprs, query.Compare("assignee", query.OpEqual, query.Value{Text: filter.AssigneeID}))
+	}
+	if filter.TeamID != "" {
+		exprs = append(exprs, query.Compare("team", query.OpEqual, query.Value{Text: filter.TeamID}))
+	}
+	if filter.Priority != models.PriorityNone {
+		exprs = append(exprs, query.Compare("priority", query.OpEqual, query.Value{Number: int(filter.Priority)}))
+	}
+	if filter.StoryPoints != nil {
+		exprs = append(exprs, query.Compare("points", query.OpEqual, query.Value{Number: *filter.StoryPoints}))
+	}
+	if filter.LabelID != "" {
+		exprs = append(exprs, query.Compare("label", query.OpIn, query.Value{Text: filter.LabelID}))
+	}
+	if filter.DueBefore != nil {
+		exprs = append(exprs, query.Compare("due", query.OpLessOrEqual, query.Value{Time: *filter.DueBefore}))
+	}
+	if filter.DueAfter != nil {
+		exprs = append(exprs, query.Compare("due", query.OpGreaterOrEqual, query.Value{Time: *filter.DueAfter}))
+	}
+	if len(filter.CustomFields) > 0 {
+		fields, err := s.customFields.ListByProject(projectID)
+		if err != nil {
+			return nil, err
+		}
+		for key, raw := range filter.CustomFields {
+			field := findCustomField(fields, key)
+			if field == nil {
+				return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidCustomField, key)
+			}
+			expr, err := customFieldFilter(field, raw)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+	}
+
+	return s.repository.ListByQuery(&query.Query{Where: query.All(exprs...)})
+}
+
 func (s *TaskService) ListTasksByAssignee(assigneeID string) ([]*models.Task, error) {
 	return s.repository.ListByAssignee(assigneeID)
 }
 
+// ListTasksBySprint returns the tasks of a sprint in rank order.
 func (s *TaskService) ListTasksBySprint(sprintID string) ([]*models.Task, error) {
-	return s.repository.ListBySprint(sprintID)
+	tasks, err := s.repository.ListBySprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	sortByRank(tasks)
+	return tasks, nil
 }
 
+// ListBacklog returns the tasks of a project that are in no sprint, in rank order.
 func (s *TaskService) ListBacklog(projectID string) ([]*models.Task, error) {
-	return s.repository.ListBacklog(projectID)
+	tasks, err := s.repository.ListBacklog(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sortByRank(tasks)
+	return tasks, nil
 }
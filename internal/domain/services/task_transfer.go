@@ -0,0 +1,241 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// CloneOptions tunes CloneTask. An empty ProjectID clones the task into its
+// own project.
+type CloneOptions struct {
+	ProjectID        string
+	IncludeSubtasks  bool
+	IncludeChecklist bool
+}
+
+// projectTransfer carries the project-scoped data of tasks over from one
+// project to another: labels and custom fields go to those of the target
+// project with the same name, and statuses as the caller maps them.
+type projectTransfer struct {
+	to       *models.Project
+	labels   map[string]string
+	fields   map[string]*models.CustomField
+	defaults []*models.CustomField
+	statuses map[models.TaskStatus]models.TaskStatus
+}
+
+// newProjectTransfer prepares the transfer of tasks of project from to
+// project to. statusMap gives the status to use for tasks of each status;
+// statuses it leaves out are kept.
+func (s *TaskService) newProjectTransfer(from string, to *models.Project, statusMap map[models.TaskStatus]models.TaskStatus) (*projectTransfer, error) {
+	transfer := &projectTransfer{
+		to:       to,
+		labels:   make(map[string]string),
+		fields:   make(map[string]*models.CustomField),
+		statuses: make(map[models.TaskStatus]models.TaskStatus),
+	}
+
+	sourceLabels, err := s.labelRepository.ListByProject(from)
+	if err != nil {
+		return nil, err
+	}
+	targetLabels, err := s.labelRepository.ListByProject(to.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range sourceLabels {
+		i := slices.IndexFunc(targetLabels, func(l *models.Label) bool { return strings.EqualFold(l.Name, label.Name) })
+		if i >= 0 {
+			transfer.labels[label.ID] = targetLabels[i].ID
+		}
+	}
+
+	sourceFields, err := s.customFields.ListByProject(from)
+	if err != nil {
+		return nil, err
+	}
+	targetFields, err := s.customFields.ListByProject(to.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range sourceFields {
+		target := findCustomField(targetFields, field.Name)
+		if target != nil && target.Type == field.Type {
+			transfer.fields[field.ID] = target
+		}
+	}
+	transfer.defaults = slices.DeleteFunc(targetFields, func(f *models.CustomField) bool { return f.Default == nil })
+
+	for from, status := range statusMap {
+		if !from.Valid() || !status.Valid() {
+			return nil, fmt.Errorf("invalid status mapping from %q to %q", from, status)
+		}
+		transfer.statuses[from] = status
+	}
+	return transfer, nil
+}
+
+// apply rewrites the project-scoped data of task for the target project. It
+// drops the labels and custom field values the target project has no match
+// for, as well as select options its fields lack, and takes the task out of
+// its sprint.
+func (t *projectTransfer) apply(task *models.Task) {
+	task.ProjectID = t.to.ID
+	task.SprintID = nil
+	if status, ok := t.statuses[task.Status]; ok {
+		task.Status = status
+	}
+
+	var labelIDs []string
+	for _, id := range task.LabelIDs {
+		if target, ok := t.labels[id]; ok {
+			labelIDs = append(labelIDs, target)
+		}
+	}
+	task.LabelIDs = labelIDs
+
+	custom := make(map[string]models.CustomFieldValue)
+	for id, value := range task.CustomFields {
+		field, ok := t.fields[id]
+		if !ok {
+			continue
+		}
+		if value.Options != nil || field.Type == models.CustomFieldSingleSelect {
+			value = remapOptions(field, value)
+		}
+		if value.Text != "" || value.Number != nil || value.Date != nil || len(value.Options) > 0 {
+			custom[field.ID] = value
+		}
+	}
+	for _, field := range t.defaults {
+		if _, ok := custom[field.ID]; !ok {
+			custom[field.ID] = *field.Default
+		}
+	}
+	task.CustomFields = custom
+	if len(custom) == 0 {
+		task.CustomFields = nil
+	}
+}
+
+// remapOptions keeps the options of a select value that field also offers.
+func remapOptions(field *models.CustomField, value models.CustomFieldValue) models.CustomFieldValue {
+	if field.Type == models.CustomFieldSingleSelect {
+		option, _ := selectOption(field, value.Text)
+		return models.CustomFieldValue{Text: option}
+	}
+	var options []string
+	for _, o := range value.Options {
+		if option, ok := selectOption(field, o); ok {
+			options = append(options, option)
+		}
+	}
+	return models.CustomFieldValue{Options: options}
+}
+
+// CloneTask copies a task as the starting point of new work, in its own
+// project or another one. The copy is a fresh task to do, outside any sprint
+// and without links, whose checklist, when included, is unchecked. Its
+// project-scoped data is carried over as by MoveToProject. A copy in another
+// project is detached from its parent, and a subtask becomes a plain task.
+// The copy, and the copies of its subtasks, record the task they came from.
+func (s *TaskService) CloneTask(taskID string, opts CloneOptions) (*models.Task, error) {
+	task, err := findTask(s.repository, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := opts.ProjectID
+	if projectID == "" {
+		projectID = task.ProjectID
+	}
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := s.newProjectTransfer(task.ProjectID, project, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := task.ParentID
+	if project.ID != task.ProjectID {
+		parentID = ""
+	}
+	clone, err := s.cloneOne(task, parentID, transfer, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.IncludeSubtasks {
+		return clone, nil
+	}
+
+	// Descendants come parents first, so each parent is copied before its
+	// children.
+	descendants, err := s.descendants(task)
+	if err != nil {
+		return nil, err
+	}
+	clones := map[string]string{task.ID: clone.ID}
+	for _, d := range descendants {
+		c, err := s.cloneOne(d, clones[d.ParentID], transfer, opts)
+		if err != nil {
+			return nil, err
+		}
+		clones[d.ID] = c.ID
+	}
+	return clone, nil
+}
+
+func (s *TaskService) cloneOne(source *models.Task, parentID string, transfer *projectTransfer, opts CloneOptions) (*models.Task, error) {
+	key, err := s.nextKey(transfer.to)
+	if err != nil {
+		return nil, err
+	}
+	r, err := rankAtEnd(s.repository, transfer.to.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *source
+	clone.ID = generateID()
+	clone.Key = key
+	clone.PreviousKeys = nil
+	clone.ParentID = parentID
+	clone.Status = models.ToDo
+	clone.Rank = r
+	clone.Links = nil
+	clone.LabelIDs = slices.Clone(source.LabelIDs)
+	clone.ClonedFrom = source.ID
+	clone.CreatedAt = time.Now()
+	clone.UpdatedAt = time.Now()
+	clone.Progress = nil
+	if clone.ParentID == "" && clone.Type == models.TypeSubtask {
+		clone.Type = models.TypeTask
+	}
+	if source.OriginalEstimateMinutes != nil {
+		remaining := *source.OriginalEstimateMinutes
+		clone.RemainingEstimateMinutes = &remaining
+	}
+
+	clone.Checklist = nil
+	if opts.IncludeChecklist {
+		for _, item := range source.Checklist {
+			clone.Checklist = append(clone.Checklist, models.ChecklistItem{ID: generateID(), Text: item.Text, Required: item.Required})
+		}
+	}
+	clone.ChecklistProgress = checklistProgress(clone.Checklist)
+
+	transfer.apply(&clone)
+	if err := s.validate(&clone); err != nil {
+		return nil, fmt.Errorf("cannot clone %s: %w", taskLabel(source), err)
+	}
+	if err := s.repository.Create(&clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type TeamRepository interface {
+	Create(team *models.Team) error
+	GetByID(id string) (*models.Team, error)
+	Update(team *models.Team) error
+	Delete(id string) error
+	List() ([]*models.Team, error)
+	ListByMember(userID string) ([]*models.Team, error)
+}
+
+// TeamInput holds the attributes of a team that are set when creating or
+// updating it. The lead is added to the members if missing.
+type TeamInput struct {
+	Name             string
+	LeadID           string
+	MemberIDs        []string
+	DefaultProjectID string
+}
+
+type TeamService struct {
+	repository        TeamRepository
+	userRepository    UserRepository
+	projectRepository ProjectRepository
+	taskRepository    TaskRepository
+}
+
+func NewTeamService(repository TeamRepository, userRepository UserRepository, projectRepository ProjectRepository, taskRepository TaskRepository) *TeamService {
+	return &TeamService{
+		repository:        repository,
+		userRepository:    userRepository,
+		projectRepository: projectRepository,
+		taskRepository:    taskRepository,
+	}
+}
+
+func (s *TeamService) CreateTeam(input TeamInput) (*models.Team, error) {
+	team := &models.Team{
+		ID:        generateID(),
+		CreatedAt: time.Now(),
+	}
+	if err := s.apply(team, input); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Create(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *TeamService) GetTeam(id string) (*models.Team, error) {
+	return s.repository.GetByID(id)
+}
+
+func (s *TeamService) ListTeams() ([]*models.Team, error) {
+	return s.repository.List()
+}
+
+// UpdateTeam replaces the attributes of a team.
+func (s *TeamService) UpdateTeam(id string, input TeamInput) (*models.Team, error) {
+	team, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(team, input); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Update(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// DeleteTeam deletes a team, taking it off the projects it was granted and
+// the tasks assigned to it. Their individual assignees are kept. Tasks go
+// first, so that a team with tasks in an archived project is left as it was.
+func (s *TeamService) DeleteTeam(id string) error {
+	if _, err := s.repository.GetByID(id); err != nil {
+		return err
+	}
+	if err := s.taskRepository.RemoveTeam(id); err != nil {
+		return err
+	}
+
+	projects, err := s.projectRepository.ListByMember("", []string{id})
+	if err != nil {
+		return err
+	}
+	for _, project := range projects {
+		project.TeamIDs = slices.DeleteFunc(project.TeamIDs, func(teamID string) bool { return teamID == id })
+		project.UpdatedAt = time.Now()
+		if err := s.projectRepository.Update(project); err != nil {
+			return err
+		}
+	}
+	return s.repository.Delete(id)
+}
+
+func (s *TeamService) AddMember(teamID, userID string) (*models.Team, error) {
+	team, err := s.repository.GetByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(team.MemberIDs, userID) {
+		return team, nil
+	}
+	if _, err := s.userRepository.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	team.MemberIDs = append(team.MemberIDs, userID)
+	team.UpdatedAt = time.Now()
+	if err := s.repository.Update(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *TeamService) RemoveMember(teamID, userID string) (*models.Team, error) {
+	team, err := s.repository.GetByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if userID == team.LeadID {
+		return nil, errors.New("cannot remove the team lead")
+	}
+
+	team.MemberIDs = slices.DeleteFunc(team.MemberIDs, func(id string) bool { return id == userID })
+	team.UpdatedAt = time.Now()
+	if err := s.repository.Update(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// apply validates input and sets it on team.
+func (s *TeamService) apply(team *models.Team, input TeamInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("team name is required")
+	}
+
+	members := slices.Clone(input.MemberIDs)
+	if input.LeadID != "" && !slices.Contains(members, input.LeadID) {
+		members = append(members, input.LeadID)
+	}
+	slices.Sort(members)
+	members = slices.Compact(members)
+	for _, id := range members {
+		if _, err := s.userRepository.GetByID(id); err != nil {
+			return errors.New("unknown team member " + id)
+		}
+	}
+	if input.DefaultProjectID != "" {
+		if _, err := s.projectRepository.GetByID(input.DefaultProjectID); err != nil {
+			return err
+		}
+	}
+
+	team.Name = name
+	team.LeadID = input.LeadID
+	team.MemberIDs = members
+	team.DefaultProjectID = input.DefaultProjectID
+	team.UpdatedAt = time.Now()
+	return nil
+}
+
+// userTeamIDs returns the IDs of the teams a user is a member of.
+func userTeamIDs(teams TeamRepository, userID string) ([]string, error) {
+	memberOf, err := teams.ListByMember(userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(memberOf))
+	for i, team := range memberOf {
+		ids[i] = team.ID
+	}
+	return ids, nil
+}
+
+// isProjectMember reports whether a user is a member of a project, directly
+// or through one of teamIDs, the teams they are in.
+func isProjectMember(project *models.Project, userID string, teamIDs []string) bool {
+	return slices.Contains(project.MemberIDs, userID) ||
+		slices.ContainsFunc(project.TeamIDs, func(id string) bool { return slices.Contains(teamIDs, id) })
+}
+
+// projectMembers returns the users of a project, its direct members first,
+// then those of its teams. Teams that no longer exist are skipped.
+func projectMembers(teams TeamRepository, project *models.Project) []string {
+	members := slices.Clone(project.MemberIDs)
+	for _, id := range project.TeamIDs {
+		team, err := teams.GetByID(id)
+		if err != nil {
+			continue
+		}
+		for _, userID := range team.MemberIDs {
+			if !slices.Contains(members, userID) {
+				members = append(members, userID)
+			}
+		}
+	}
+	return members
+}
@@ -0,0 +1,488 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+var ErrNotTemplateOwner = errors.New("only the owner can change a template")
+
+type TaskTemplateRepository interface {
+	Create(template *models.TaskTemplate) error
+	GetByID(id string) (*models.TaskTemplate, error)
+	Update(template *models.TaskTemplate) error
+	Delete(id string) error
+	List() ([]*models.TaskTemplate, error)
+}
+
+type ProjectTemplateRepository interface {
+	Create(template *models.ProjectTemplate) error
+	GetByID(id string) (*models.ProjectTemplate, error)
+	Update(template *models.ProjectTemplate) error
+	Delete(id string) error
+	List() ([]*models.ProjectTemplate, error)
+}
+
+// TemplatedProject is everything created from a project template.
+type TemplatedProject struct {
+	Project *models.Project  `json:"project"`
+	Labels  []*models.Label  `json:"labels"`
+	Tasks   []*models.Task   `json:"tasks"`
+	Sprints []*models.Sprint `json:"sprints"`
+}
+
+// TemplateService manages task and project templates and creates tasks and
+// projects from them. Templates are shared by everyone; only their owner or
+// an admin can change them.
+type TemplateService struct {
+	taskTemplateRepository    TaskTemplateRepository
+	projectTemplateRepository ProjectTemplateRepository
+	projectService            *ProjectService
+	taskService               *TaskService
+	labelService              *LabelService
+	boardService              *BoardService
+	sprintService             *SprintService
+}
+
+func NewTemplateService(taskTemplateRepository TaskTemplateRepository, projectTemplateRepository ProjectTemplateRepository, projectService *ProjectService, taskService *TaskService, labelService *LabelService, boardService *BoardService, sprintService *SprintService) *TemplateService {
+	return &TemplateService{
+		taskTemplateRepository:    taskTemplateRepository,
+		projectTemplateRepository: projectTemplateRepository,
+		projectService:            projectService,
+		taskService:               taskService,
+		labelService:              labelService,
+		boardService:              boardService,
+		sprintService:             sprintService,
+	}
+}
+
+func (s *TemplateService) CreateTaskTemplate(caller Caller, template *models.TaskTemplate) (*models.TaskTemplate, error) {
+	if err := validateTaskTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.ID = generateID()
+	template.OwnerID = caller.UserID
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+	if err := s.taskTemplateRepository.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) GetTaskTemplate(id string) (*models.TaskTemplate, error) {
+	return s.taskTemplateRepository.GetByID(id)
+}
+
+func (s *TemplateService) ListTaskTemplates() ([]*models.TaskTemplate, error) {
+	return s.taskTemplateRepository.List()
+}
+
+// UpdateTaskTemplate replaces the content of a task template.
+func (s *TemplateService) UpdateTaskTemplate(caller Caller, id string, template *models.TaskTemplate) (*models.TaskTemplate, error) {
+	existing, err := s.taskTemplateRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.OwnerID != caller.UserID && !caller.IsAdmin() {
+		return nil, ErrNotTemplateOwner
+	}
+	if err := validateTaskTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.ID = existing.ID
+	template.OwnerID = existing.OwnerID
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedAt = time.Now()
+	if err := s.taskTemplateRepository.Update(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) DeleteTaskTemplate(caller Caller, id string) error {
+	template, err := s.taskTemplateRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if template.OwnerID != caller.UserID && !caller.IsAdmin() {
+		return ErrNotTemplateOwner
+	}
+	return s.taskTemplateRepository.Delete(id)
+}
+
+func (s *TemplateService) CreateProjectTemplate(caller Caller, template *models.ProjectTemplate) (*models.ProjectTemplate, error) {
+	if err := s.validateProjectTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.ID = generateID()
+	template.OwnerID = caller.UserID
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+	if err := s.projectTemplateRepository.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) GetProjectTemplate(id string) (*models.ProjectTemplate, error) {
+	return s.projectTemplateRepository.GetByID(id)
+}
+
+func (s *TemplateService) ListProjectTemplates() ([]*models.ProjectTemplate, error) {
+	return s.projectTemplateRepository.List()
+}
+
+// UpdateProjectTemplate replaces the content of a project template.
+func (s *TemplateService) UpdateProjectTemplate(caller Caller, id string, template *models.ProjectTemplate) (*models.ProjectTemplate, error) {
+	existing, err := s.projectTemplateRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.OwnerID != caller.UserID && !caller.IsAdmin() {
+		return nil, ErrNotTemplateOwner
+	}
+	if err := s.validateProjectTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.ID = existing.ID
+	template.OwnerID = existing.OwnerID
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedAt = time.Now()
+	if err := s.projectTemplateRepository.Update(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) DeleteProjectTemplate(caller Caller, id string) error {
+	template, err := s.projectTemplateRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if template.OwnerID != caller.UserID && !caller.IsAdmin() {
+		return ErrNotTemplateOwner
+	}
+	return s.projectTemplateRepository.Delete(id)
+}
+
+// CreateTaskFromTemplate creates a task and its subtasks in a project from a
+// task template, filling its placeholders from variables. The project_name
+// and project_key variables are always set. Template labels missing from the
+// project are created.
+func (s *TemplateService) CreateTaskFromTemplate(templateID, projectID, assigneeID string, variables map[string]string) (*models.Task, error) {
+	template, err := s.taskTemplateRepository.GetByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.projectService.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	fill, err := newPlaceholderFiller(project, variables, taskTemplateTexts(template))
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.projectLabels(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var undo rollback
+	task, err := s.instantiate(template, project.ID, assigneeID, fill, labels, &undo)
+	if err != nil {
+		return nil, errors.Join(err, undo.run())
+	}
+	return task, nil
+}
+
+// CreateProjectFromTemplate creates a project owned by the caller with the
+// board, labels, starter tasks and sprints of a project template, filling
+// placeholders from variables. Sprints start back to back on startDate,
+// today when zero. If any step fails, everything created is removed again.
+func (s *TemplateService) CreateProjectFromTemplate(caller Caller, templateID, name, key string, variables map[string]string, startDate time.Time) (*TemplatedProject, error) {
+	template, err := s.projectTemplateRepository.GetByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	texts := projectTemplateTexts(template)
+	taskTemplates := make([]*models.TaskTemplate, len(template.TaskTemplateIDs))
+	for i, id := range template.TaskTemplateIDs {
+		if taskTemplates[i], err = s.taskTemplateRepository.GetByID(id); err != nil {
+			return nil, fmt.Errorf("task template %s: %w", id, err)
+		}
+		texts = append(texts, taskTemplateTexts(taskTemplates[i])...)
+	}
+
+	// Check the variables before creating anything, with a stand-in for the
+	// project key, which may still have to be derived from the name.
+	if _, err := newPlaceholderFiller(&models.Project{Name: name, Key: key}, variables, texts); err != nil {
+		return nil, err
+	}
+
+	var undo rollback
+	result, err := s.buildProject(caller, template, taskTemplates, name, key, variables, texts, startDate, &undo)
+	if err != nil {
+		return nil, errors.Join(err, undo.run())
+	}
+	return result, nil
+}
+
+func (s *TemplateService) buildProject(caller Caller, template *models.ProjectTemplate, taskTemplates []*models.TaskTemplate, name, key string, variables map[string]string, texts []string, startDate time.Time, undo *rollback) (*TemplatedProject, error) {
+	project, err := s.projectService.CreateProject(name, key, "", caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+	undo.add(func() error { return s.projectService.DeleteProject(project.ID) })
+
+	fill, err := newPlaceholderFiller(project, variables, texts)
+	if err != nil {
+		return nil, err
+	}
+	project.Description = fill(template.Description)
+	if err := s.projectService.UpdateProject(project); err != nil {
+		return nil, err
+	}
+	if len(template.BoardColumns) > 0 {
+		if _, err := s.boardService.ConfigureBoard(project.ID, template.BoardColumns); err != nil {
+			return nil, err
+		}
+		project.BoardColumns = template.BoardColumns
+	}
+
+	result := &TemplatedProject{Project: project, Labels: []*models.Label{}, Tasks: []*models.Task{}, Sprints: []*models.Sprint{}}
+	labels := make(map[string]string)
+	for _, labelTemplate := range template.Labels {
+		label, err := s.labelService.CreateLabel(project.ID, fill(labelTemplate.Name), labelTemplate.Color)
+		if err != nil {
+			return nil, err
+		}
+		undo.add(func() error { return s.labelService.DeleteLabel(label.ID) })
+		labels[strings.ToLower(label.Name)] = label.ID
+		result.Labels = append(result.Labels, label)
+	}
+
+	for _, taskTemplate := range taskTemplates {
+		task, err := s.instantiate(taskTemplate, project.ID, "", fill, labels, undo)
+		if err != nil {
+			return nil, err
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	if cadence := template.SprintCadence; cadence != nil {
+		if startDate.IsZero() {
+			startDate = day(time.Now())
+		}
+		for i := range max(cadence.Count, 1) {
+			start := startDate.AddDate(0, 0, i*cadence.LengthDays)
+			sprint, err := s.sprintService.CreateSprint(project.ID, fmt.Sprintf("%s Sprint %d", project.Key, i+1), start, start.AddDate(0, 0, cadence.LengthDays))
+			if err != nil {
+				return nil, err
+			}
+			undo.add(func() error { return s.sprintService.DeleteSprint(sprint.ID) })
+			result.Sprints = append(result.Sprints, sprint)
+		}
+	}
+	return result, nil
+}
+
+// instantiate creates a task and its subtasks from a template. labels maps
+// the lowercase names of the project's labels to their IDs; missing ones are
+// created and added to it.
+func (s *TemplateService) instantiate(template *models.TaskTemplate, projectID, assigneeID string, fill func(string) string, labels map[string]string, undo *rollback) (*models.Task, error) {
+	var labelIDs []string
+	for _, name := range template.Labels {
+		name = fill(name)
+		id, ok := labels[strings.ToLower(name)]
+		if !ok {
+			label, err := s.labelService.CreateLabel(projectID, name, "")
+			if err != nil {
+				return nil, err
+			}
+			undo.add(func() error { return s.labelService.DeleteLabel(label.ID) })
+			id = label.ID
+			labels[strings.ToLower(name)] = id
+		}
+		labelIDs = append(labelIDs, id)
+	}
+
+	var checklist []ChecklistItemInput
+	for _, item := range template.Checklist {
+		checklist = append(checklist, ChecklistItemInput{Text: fill(item)})
+	}
+
+	details := TaskDetails{
+		Type:        template.Type,
+		Priority:    template.Priority,
+		StoryPoints: template.StoryPoints,
+		LabelIDs:    labelIDs,
+		Checklist:   checklist,
+	}
+	task, err := s.taskService.CreateTask(fill(template.Title), fill(template.Description), projectID, assigneeID, details)
+	if err != nil {
+		return nil, err
+	}
+	undo.add(func() error { return s.taskService.PurgeTask(task.ID) })
+
+	for _, subtask := range template.Subtasks {
+		details := TaskDetails{Type: models.TypeSubtask, ParentID: task.ID}
+		if _, err := s.taskService.CreateTask(fill(subtask.Title), fill(subtask.Description), projectID, assigneeID, details); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// projectLabels maps the lowercase names of a project's labels to their IDs.
+func (s *TemplateService) projectLabels(projectID string) (map[string]string, error) {
+	existing, err := s.labelService.ListLabels(projectID)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(existing))
+	for _, label := range existing {
+		labels[strings.ToLower(label.Name)] = label.ID
+	}
+	return labels, nil
+}
+
+func validateTaskTemplate(template *models.TaskTemplate) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return errors.New("template name is required")
+	}
+	if strings.TrimSpace(template.Title) == "" {
+		return errors.New("template title is required")
+	}
+	if template.Type != "" {
+		if _, ok := typeRank[template.Type]; !ok {
+			return fmt.Errorf("invalid task type %q", template.Type)
+		}
+	}
+	if template.Type == models.TypeSubtask {
+		return errors.New("a template cannot be a subtask; add subtasks to it instead")
+	}
+	if slices.ContainsFunc(template.Labels, func(name string) bool { return strings.TrimSpace(name) == "" }) {
+		return errors.New("template labels need a name")
+	}
+	if slices.ContainsFunc(template.Checklist, func(item string) bool { return strings.TrimSpace(item) == "" }) {
+		return errors.New("checklist items cannot be empty")
+	}
+	for _, subtask := range template.Subtasks {
+		if strings.TrimSpace(subtask.Title) == "" {
+			return errors.New("template subtasks need a title")
+		}
+	}
+	return nil
+}
+
+func (s *TemplateService) validateProjectTemplate(template *models.ProjectTemplate) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return errors.New("template name is required")
+	}
+	if len(template.BoardColumns) > 0 {
+		if err := validateBoardColumns(template.BoardColumns); err != nil {
+			return err
+		}
+	}
+	for _, label := range template.Labels {
+		if strings.TrimSpace(label.Name) == "" {
+			return errors.New("template labels need a name")
+		}
+		if label.Color != "" && !labelColorPattern.MatchString(label.Color) {
+			return errors.New("label color must be a hex color such as #ff5630")
+		}
+	}
+	for _, id := range template.TaskTemplateIDs {
+		if _, err := s.taskTemplateRepository.GetByID(id); err != nil {
+			return fmt.Errorf("task template %s: %w", id, err)
+		}
+	}
+	if cadence := template.SprintCadence; cadence != nil && (cadence.LengthDays < 1 || cadence.Count < 0) {
+		return errors.New("sprint cadence needs a positive length and count")
+	}
+	return nil
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// newPlaceholderFiller returns a function replacing the {{variable}}
+// placeholders of a text with their values, after checking that every
+// placeholder in texts has one.
+func newPlaceholderFiller(project *models.Project, variables map[string]string, texts []string) (func(string) string, error) {
+	values := map[string]string{"project_name": project.Name, "project_key": project.Key}
+	for name, value := range variables {
+		values[name] = value
+	}
+
+	var missing []string
+	for _, text := range texts {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			if _, ok := values[match[1]]; !ok && !slices.Contains(missing, match[1]) {
+				missing = append(missing, match[1])
+			}
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+
+	return func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			return values[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+		})
+	}, nil
+}
+
+// taskTemplateTexts returns the texts of a task template that may hold
+// placeholders.
+func taskTemplateTexts(template *models.TaskTemplate) []string {
+	texts := append([]string{template.Title, template.Description}, template.Labels...)
+	texts = append(texts, template.Checklist...)
+	for _, subtask := range template.Subtasks {
+		texts = append(texts, subtask.Title, subtask.Description)
+	}
+	return texts
+}
+
+func projectTemplateTexts(template *models.ProjectTemplate) []string {
+	texts := []string{template.Description}
+	for _, label := range template.Labels {
+		texts = append(texts, label.Name)
+	}
+	return texts
+}
+
+// rollback undoes the steps of a template instantiation, last first, when a
+// later step fails.
+type rollback []func() error
+
+func (r *rollback) add(undo func() error) {
+	*r = append(*r, undo)
+}
+
+// run undoes every step it can; a step that fails to undo leaves its data
+// behind but does not stop the others. It returns the errors of those steps.
+func (r rollback) run() error {
+	var errs []error
+	for i := len(r) - 1; i >= 0; i-- {
+		if err := r[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to undo template: %w", errors.Join(errs...))
+	}
+	return nil
+}
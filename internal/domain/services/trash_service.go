@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+// ErrTrashedWithOther is returned when restoring a task that went to the
+// trash along with its parent or project, which must be restored instead.
+var ErrTrashedWithOther = errors.New("task was deleted along with its parent or project: restore that instead")
+
+// TrashService restores deleted tasks and projects from the trash and purges
+// them for good. Deleted items keep their place in their repository, marked
+// with the time they were deleted; tasks deleted along with a parent or a
+// project record its ID, so they come back and go away with it.
+type TrashService struct {
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+	teamRepository    TeamRepository
+}
+
+func NewTrashService(taskRepository TaskRepository, projectRepository ProjectRepository, teamRepository TeamRepository) *TrashService {
+	return &TrashService{
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+		teamRepository:    teamRepository,
+	}
+}
+
+// TrashProject moves a project and its tasks to the trash, together.
+func (s *TrashService) TrashProject(deletedBy, projectID string) error {
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+
+	// The project is written last, so that the tasks are put back if it
+	// fails
+	return s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+		tasks, err := tx.ListByProject(project.ID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, task := range tasks {
+			if err := trashTask(tx, task, deletedBy, now, project.ID); err != nil {
+				return err
+			}
+		}
+		project.DeletedAt = &now
+		project.DeletedBy = deletedBy
+		return s.projectRepository.Update(project)
+	})
+}
+
+func (s *TrashService) GetTrashedTask(id string) (*models.Task, error) {
+	return s.taskRepository.GetTrashed(id)
+}
+
+func (s *TrashService) GetTrashedProject(id string) (*models.Project, error) {
+	return s.projectRepository.GetTrashed(id)
+}
+
+// ListTaskTrash returns the tasks deleted from a project, most recent first,
+// leaving out those deleted along with a parent.
+func (s *TrashService) ListTaskTrash(projectID string) ([]*models.Task, error) {
+	tasks, err := s.taskRepository.ListTrash(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(tasks, func(task *models.Task) bool { return task.TrashedWith != "" }), nil
+}
+
+// ListProjectTrash returns the deleted projects the caller can see: all of
+// them for admins, otherwise the ones the caller was a member of.
+func (s *TrashService) ListProjectTrash(caller Caller) ([]*models.Project, error) {
+	projects, err := s.projectRepository.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	if caller.IsAdmin() {
+		return projects, nil
+	}
+	teamIDs, err := userTeamIDs(s.teamRepository, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(projects, func(project *models.Project) bool {
+		return !isProjectMember(project, caller.UserID, teamIDs)
+	}), nil
+}
+
+// RestoreTask brings a task back from the trash along with the tasks deleted
+// with it, all or none of them, at the end of its project's order. Its
+// project must not be in the trash; if its parent is gone, it is restored at
+// the top of the hierarchy.
+func (s *TrashService) RestoreTask(id string) (*models.Task, error) {
+	task, err := s.taskRepository.GetTrashed(id)
+	if err != nil {
+		return nil, err
+	}
+	if task.TrashedWith != "" {
+		return nil, ErrTrashedWithOther
+	}
+	if _, err := s.projectRepository.GetByID(task.ProjectID); err != nil {
+		return nil, errors.New("the task's project is deleted: restore it first")
+	}
+
+	if task.ParentID != "" {
+		if _, err := s.taskRepository.GetByID(task.ParentID); err != nil {
+			task.ParentID = ""
+			if task.Type == models.TypeSubtask {
+				task.Type = models.TypeTask
+			}
+		}
+	}
+	err = s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+		if err := restoreTask(tx, task); err != nil {
+			return err
+		}
+		return restoreWith(tx, task.ProjectID, task.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// RestoreProject brings a project back from the trash along with the tasks
+// deleted with it, together.
+func (s *TrashService) RestoreProject(id string) (*models.Project, error) {
+	project, err := s.projectRepository.GetTrashed(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+		if err := restoreWith(tx, project.ID, project.ID); err != nil {
+			return err
+		}
+		project.DeletedAt = nil
+		project.DeletedBy = ""
+		project.UpdatedAt = time.Now()
+		return s.projectRepository.Update(project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// PurgeProject deletes a project for good, whether live or in the trash,
+// along with all of its tasks, or leaves them all when any fails to go.
+func (s *TrashService) PurgeProject(id string) error {
+	project, err := s.projectRepository.GetByID(id)
+	if err != nil {
+		if project, err = s.projectRepository.GetTrashed(id); err != nil {
+			return err
+		}
+	}
+
+	return s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+		live, err := tx.ListByProject(project.ID)
+		if err != nil {
+			return err
+		}
+		trashed, err := tx.ListTrash(project.ID)
+		if err != nil {
+			return err
+		}
+		for _, task := range append(live, trashed...) {
+			if err := unlinkAll(tx, task); err != nil {
+				return err
+			}
+			if err := tx.Delete(task.ID); err != nil {
+				return err
+			}
+		}
+		return s.projectRepository.Delete(project.ID)
+	})
+}
+
+// PurgeExpired deletes for good the projects and tasks that went to the
+// trash before cutoff, and returns how many of each it purged.
+func (s *TrashService) PurgeExpired(cutoff time.Time) (projects, tasks int, err error) {
+	trashedProjects, err := s.projectRepository.ListTrashedBefore(cutoff)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, project := range trashedProjects {
+		if err := s.PurgeProject(project.ID); err != nil {
+			return projects, tasks, err
+		}
+		projects++
+	}
+
+	trashedTasks, err := s.taskRepository.ListTrashedBefore(cutoff)
+	if err != nil {
+		return projects, tasks, err
+	}
+	for _, task := range trashedTasks {
+		if task.TrashedWith != "" {
+			continue
+		}
+		err := s.taskRepository.WithTransaction(func(tx TaskRepository) error {
+			return purgeTree(tx, task)
+		})
+		if err != nil {
+			return projects, tasks, err
+		}
+		tasks++
+	}
+	return projects, tasks, nil
+}
+
+// restoreWith brings back the tasks of a project that went to the trash
+// along with the task or project with the given ID.
+func restoreWith(repository TaskRepository, projectID, id string) error {
+	tasks, err := trashedWith(repository, projectID, id)
+	if err != nil {
+		return err
+	}
+	sortByRank(tasks)
+	for _, task := range tasks {
+		if err := restoreTask(repository, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trashTask(repository TaskRepository, task *models.Task, deletedBy string, deletedAt time.Time, trashedWith string) error {
+	task.DeletedAt = &deletedAt
+	task.DeletedBy = deletedBy
+	task.TrashedWith = trashedWith
+	return repository.Update(task)
+}
+
+// restoreTask brings a task back at the end of its project's order, as the
+// ranks of the others may have been respread while it was in the trash.
+func restoreTask(repository TaskRepository, task *models.Task) error {
+	r, err := rankAtEnd(repository, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	task.DeletedAt = nil
+	task.DeletedBy = ""
+	task.TrashedWith = ""
+	task.UpdatedAt = time.Now()
+	if err := repository.Update(task); err != nil {
+		return err
+	}
+	ok, err := repository.SetRank(task.ID, task.Rank, r)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRankConflict
+	}
+	task.Rank = r
+	return nil
+}
+
+// trashedWith returns the tasks of a project that went to the trash along
+// with the task or project with the given ID.
+func trashedWith(repository TaskRepository, projectID, id string) ([]*models.Task, error) {
+	tasks, err := repository.ListTrash(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(tasks, func(task *models.Task) bool { return task.TrashedWith != id }), nil
+}
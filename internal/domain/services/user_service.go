@@ -16,6 +16,7 @@ type UserRepository interface {
 	Update(user *models.User) error
 	Delete(id string) error
 	List() ([]*models.User, error)
+	ListByOrganization(organizationID string) ([]*models.User, error)
 }
 
 type UserService struct {
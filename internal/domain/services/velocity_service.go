@@ -0,0 +1,365 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+const (
+	// rollingWindow is the number of sprints each rolling average covers.
+	rollingWindow = 3
+	// forecastTrials is the number of simulated futures a forecast draws.
+	forecastTrials = 10000
+	// maxForecastSprints bounds a simulated future, which could otherwise
+	// run forever when most sampled sprints completed nothing.
+	maxForecastSprints = 500
+)
+
+// forecastConfidences are the percentiles a forecast reports.
+var forecastConfidences = []int{50, 85, 95}
+
+// SprintVelocity is the work a closed sprint committed to when it started
+// and the work it completed by the time it closed.
+type SprintVelocity struct {
+	SprintID string `json:"sprint_id"`
+	// ProjectID is set in team velocities, whose sprints come from several
+	// projects.
+	ProjectID       string    `json:"project_id,omitempty"`
+	Name            string    `json:"name"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	CommittedPoints int       `json:"committed_points"`
+	CompletedPoints int       `json:"completed_points"`
+	CommittedTasks  int       `json:"committed_tasks"`
+	CompletedTasks  int       `json:"completed_tasks"`
+	// RollingAverage is the completed points averaged over this sprint and
+	// the ones just before it.
+	RollingAverage float64 `json:"rolling_average"`
+}
+
+// Velocity is the completed points of a project's last closed sprints, oldest
+// first, with their mean and standard deviation.
+type Velocity struct {
+	ProjectID string           `json:"project_id"`
+	Sprints   []SprintVelocity `json:"sprints"`
+	Average   float64          `json:"average"`
+	StdDev    float64          `json:"std_dev"`
+}
+
+// TeamVelocity is the points a team completed in the last closed sprints it
+// had work in, across the projects it works in, oldest first. Only the tasks
+// assigned to the team count, whoever in it they were assigned to.
+type TeamVelocity struct {
+	TeamID  string           `json:"team_id"`
+	Sprints []SprintVelocity `json:"sprints"`
+	Average float64          `json:"average"`
+	StdDev  float64          `json:"std_dev"`
+}
+
+type ForecastUnit string
+
+const (
+	ForecastPoints ForecastUnit = "points"
+	ForecastTasks  ForecastUnit = "tasks"
+)
+
+// Forecast answers when the remaining work of a backlog or an epic will be
+// done, at several confidence levels.
+type Forecast struct {
+	ProjectID string       `json:"project_id"`
+	EpicID    string       `json:"epic_id,omitempty"`
+	Unit      ForecastUnit `json:"unit"`
+	Remaining int          `json:"remaining"`
+	// UnestimatedTasks counts open tasks without story points, which a
+	// forecast in points cannot account for.
+	UnestimatedTasks int            `json:"unestimated_tasks"`
+	SampledSprints   int            `json:"sampled_sprints"`
+	SprintDays       float64        `json:"sprint_days"`
+	Dates            []ForecastDate `json:"dates"`
+}
+
+// ForecastDate is the date by which the work is done in Confidence percent of
+// the simulated futures, Sprints sprints from now.
+type ForecastDate struct {
+	Confidence int       `json:"confidence"`
+	Sprints    int       `json:"sprints"`
+	Date       time.Time `json:"date"`
+}
+
+type VelocityService struct {
+	sprintRepository  SprintRepository
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+	teamRepository    TeamRepository
+	burndownService   *BurndownService
+}
+
+func NewVelocityService(sprintRepository SprintRepository, taskRepository TaskRepository, projectRepository ProjectRepository, teamRepository TeamRepository, burndownService *BurndownService) *VelocityService {
+	return &VelocityService{
+		sprintRepository:  sprintRepository,
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+		teamRepository:    teamRepository,
+		burndownService:   burndownService,
+	}
+}
+
+// Velocity returns the velocity of a project over its last n closed sprints.
+func (s *VelocityService) Velocity(projectID string, n int) (*Velocity, error) {
+	sprints, err := s.lastClosedSprints(projectID, n)
+	if err != nil {
+		return nil, err
+	}
+
+	velocity := &Velocity{ProjectID: projectID, Sprints: make([]SprintVelocity, 0, len(sprints))}
+	for _, sprint := range sprints {
+		start, end, err := s.burndownService.sprintTotals(sprint, "")
+		if err != nil {
+			return nil, err
+		}
+		velocity.Sprints = append(velocity.Sprints, sprintVelocity(sprint, start, end))
+	}
+	velocity.Average, velocity.StdDev = averageVelocity(velocity.Sprints)
+	return velocity, nil
+}
+
+// TeamVelocity returns the velocity of a team over the last n closed sprints
+// of its default project and the projects granted to it in which the team
+// had work.
+func (s *VelocityService) TeamVelocity(teamID string, n int) (*TeamVelocity, error) {
+	team, err := s.teamRepository.GetByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectRepository.ListByMember("", []string{team.ID})
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projects)+1)
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	if team.DefaultProjectID != "" && !slices.Contains(projectIDs, team.DefaultProjectID) {
+		projectIDs = append(projectIDs, team.DefaultProjectID)
+	}
+
+	var sprints []*models.Sprint
+	for _, projectID := range projectIDs {
+		closed, err := s.lastClosedSprints(projectID, n)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, closed...)
+	}
+	slices.SortFunc(sprints, func(a, b *models.Sprint) int {
+		return b.ClosedAt.Compare(*a.ClosedAt)
+	})
+
+	velocity := &TeamVelocity{TeamID: team.ID, Sprints: make([]SprintVelocity, 0, n)}
+	for _, sprint := range sprints {
+		if len(velocity.Sprints) == n {
+			break
+		}
+		start, end, err := s.burndownService.sprintTotals(sprint, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		if start.ScopeTasks == 0 && end.ScopeTasks == 0 {
+			continue
+		}
+		sprintVelocity := sprintVelocity(sprint, start, end)
+		sprintVelocity.ProjectID = sprint.ProjectID
+		velocity.Sprints = append(velocity.Sprints, sprintVelocity)
+	}
+	slices.Reverse(velocity.Sprints)
+	velocity.Average, velocity.StdDev = averageVelocity(velocity.Sprints)
+	return velocity, nil
+}
+
+// Forecast simulates the sprints needed to finish the open tasks of a
+// project, or of one of its epics when epicID is given, by drawing the
+// throughput of each future sprint at random from the last n closed sprints.
+func (s *VelocityService) Forecast(projectID, epicID string, unit ForecastUnit, n int) (*Forecast, error) {
+	if unit == "" {
+		unit = ForecastPoints
+	}
+	if unit != ForecastPoints && unit != ForecastTasks {
+		return nil, errors.New("forecast unit must be points or tasks")
+	}
+
+	tasks, err := s.openWork(projectID, epicID)
+	if err != nil {
+		return nil, err
+	}
+	forecast := &Forecast{ProjectID: projectID, EpicID: epicID, Unit: unit}
+	for _, task := range tasks {
+		switch {
+		case unit == ForecastTasks:
+			forecast.Remaining++
+		case task.StoryPoints == nil:
+			forecast.UnestimatedTasks++
+		default:
+			forecast.Remaining += *task.StoryPoints
+		}
+	}
+
+	sprints, err := s.lastClosedSprints(projectID, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(sprints) == 0 {
+		return nil, errors.New("forecasting needs at least one closed sprint")
+	}
+	forecast.SampledSprints = len(sprints)
+
+	throughput := make([]int, 0, len(sprints))
+	var length time.Duration
+	for _, sprint := range sprints {
+		_, end, err := s.burndownService.sprintTotals(sprint, "")
+		if err != nil {
+			return nil, err
+		}
+		if unit == ForecastTasks {
+			throughput = append(throughput, end.CompletedTasks)
+		} else {
+			throughput = append(throughput, end.CompletedPoints)
+		}
+		length += sprint.EndDate.Sub(sprint.StartDate)
+	}
+	length /= time.Duration(len(sprints))
+	forecast.SprintDays = length.Hours() / 24
+
+	if slices.Max(throughput) == 0 && forecast.Remaining > 0 {
+		return nil, errors.New("the sampled sprints completed no work")
+	}
+
+	outcomes := simulate(forecast.Remaining, throughput, forecastTrials)
+	now := time.Now()
+	for _, confidence := range forecastConfidences {
+		sprintsNeeded := outcomes[(len(outcomes)*confidence+99)/100-1]
+		forecast.Dates = append(forecast.Dates, ForecastDate{
+			Confidence: confidence,
+			Sprints:    sprintsNeeded,
+			Date:       now.Add(time.Duration(sprintsNeeded) * length),
+		})
+	}
+	return forecast, nil
+}
+
+// openWork returns the tasks left to do in a project, or below an epic of it.
+// Epics themselves are containers and hold no work of their own.
+func (s *VelocityService) openWork(projectID, epicID string) ([]*models.Task, error) {
+	var tasks []*models.Task
+	if epicID == "" {
+		all, err := s.taskRepository.ListByProject(projectID)
+		if err != nil {
+			return nil, err
+		}
+		tasks = all
+	} else {
+		epic, err := findTask(s.taskRepository, epicID)
+		if err != nil {
+			return nil, err
+		}
+		if epic.ProjectID != projectID {
+			return nil, errors.New("epic belongs to another project")
+		}
+		if epic.Type != models.TypeEpic {
+			return nil, errors.New("task is not an epic")
+		}
+		if tasks, err = descendants(s.taskRepository, epic); err != nil {
+			return nil, err
+		}
+	}
+
+	return slices.DeleteFunc(tasks, func(task *models.Task) bool {
+		return task.Status == models.Done || task.Type == models.TypeEpic
+	}), nil
+}
+
+// lastClosedSprints returns the last n closed sprints of a project, oldest
+// first.
+func (s *VelocityService) lastClosedSprints(projectID string, n int) ([]*models.Sprint, error) {
+	sprints, err := s.sprintRepository.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints = slices.DeleteFunc(sprints, func(sprint *models.Sprint) bool {
+		return sprint.Status != models.SprintClosed
+	})
+	slices.SortFunc(sprints, func(a, b *models.Sprint) int {
+		return a.ClosedAt.Compare(*b.ClosedAt)
+	})
+	return sprints[max(0, len(sprints)-n):], nil
+}
+
+func sprintVelocity(sprint *models.Sprint, start, end *models.SprintSnapshot) SprintVelocity {
+	return SprintVelocity{
+		SprintID:        sprint.ID,
+		Name:            sprint.Name,
+		StartDate:       sprint.StartDate,
+		EndDate:         sprint.EndDate,
+		CommittedPoints: start.ScopePoints,
+		CompletedPoints: end.CompletedPoints,
+		CommittedTasks:  start.ScopeTasks,
+		CompletedTasks:  end.CompletedTasks,
+	}
+}
+
+// averageVelocity sets the rolling average of each sprint, oldest first, and
+// returns the mean and standard deviation of the completed points.
+func averageVelocity(sprints []SprintVelocity) (average, deviation float64) {
+	completed := make([]float64, len(sprints))
+	for i := range sprints {
+		completed[i] = float64(sprints[i].CompletedPoints)
+		sprints[i].RollingAverage = mean(completed[max(0, i+1-rollingWindow) : i+1])
+	}
+	return mean(completed), stdDev(completed)
+}
+
+// simulate returns, for each trial in increasing order, the number of
+// sprints it took to complete remaining work, each sprint completing a
+// throughput drawn at random from samples.
+func simulate(remaining int, samples []int, trials int) []int {
+	outcomes := make([]int, trials)
+	for i := range outcomes {
+		done, sprints := 0, 0
+		for done < remaining && sprints < maxForecastSprints {
+			done += samples[rand.IntN(len(samples))]
+			sprints++
+		}
+		outcomes[i] = sprints
+	}
+	slices.Sort(outcomes)
+	return outcomes
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdDev returns the sample standard deviation of values.
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+var ErrCapacityExceeded = errors.New("assignee capacity exceeded")
+
+// Workload is the work assigned to each member of a project, including the
+// members of its teams, in a sprint against the capacity they have in it.
+type Workload struct {
+	ProjectID string            `json:"project_id"`
+	SprintID  string            `json:"sprint_id"`
+	Members   []*MemberWorkload `json:"members"`
+	// UnassignedPoints is the work in the sprint no member has taken yet.
+	UnassignedPoints int `json:"unassigned_points"`
+	UnassignedTasks  int `json:"unassigned_tasks"`
+}
+
+// TeamWorkload is the work assigned to each member of a team in a sprint
+// against the capacity they have in it, along with the work assigned to the
+// team that none of them has taken yet. Members' work counts all their tasks
+// in the sprint, for the team or not.
+type TeamWorkload struct {
+	TeamID    string            `json:"team_id"`
+	ProjectID string            `json:"project_id"`
+	SprintID  string            `json:"sprint_id"`
+	Members   []*MemberWorkload `json:"members"`
+	// UnassignedPoints is the team's work in the sprint no member has taken
+	// yet.
+	UnassignedPoints int `json:"unassigned_points"`
+	UnassignedTasks  int `json:"unassigned_tasks"`
+}
+
+// MemberWorkload compares the story points and remaining estimated hours
+// assigned to a member in a sprint with their capacity, reduced by their time
+// off during it. A member is over capacity when either exceeds the capacity
+// set for it; members without a capacity are never over it.
+type MemberWorkload struct {
+	UserID           string  `json:"user_id"`
+	Name             string  `json:"name"`
+	AssignedPoints   int     `json:"assigned_points"`
+	AssignedHours    float64 `json:"assigned_hours"`
+	AssignedTasks    int     `json:"assigned_tasks"`
+	UnestimatedTasks int     `json:"unestimated_tasks"`
+	HasCapacity      bool    `json:"has_capacity"`
+	AvailablePoints  float64 `json:"available_points"`
+	AvailableHours   float64 `json:"available_hours"`
+	TimeOffDays      int     `json:"time_off_days"`
+	OverCapacity     bool    `json:"over_capacity"`
+
+	capacity *models.UserCapacity
+}
+
+type WorkloadService struct {
+	userRepository    UserRepository
+	projectRepository ProjectRepository
+	sprintRepository  SprintRepository
+	taskRepository    TaskRepository
+	teamRepository    TeamRepository
+}
+
+func NewWorkloadService(userRepository UserRepository, projectRepository ProjectRepository, sprintRepository SprintRepository, taskRepository TaskRepository, teamRepository TeamRepository) *WorkloadService {
+	return &WorkloadService{
+		userRepository:    userRepository,
+		projectRepository: projectRepository,
+		sprintRepository:  sprintRepository,
+		taskRepository:    taskRepository,
+		teamRepository:    teamRepository,
+	}
+}
+
+// SetCapacity replaces a user's capacity; nil clears it.
+func (s *WorkloadService) SetCapacity(userID string, capacity *models.UserCapacity) (*models.User, error) {
+	if capacity != nil {
+		if capacity.PointsPerSprint < 0 || capacity.HoursPerSprint < 0 {
+			return nil, errors.New("capacity cannot be negative")
+		}
+		for _, off := range capacity.TimeOff {
+			if off.End.Before(off.Start) {
+				return nil, errors.New("time off ends before it starts")
+			}
+		}
+	}
+
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Capacity = capacity
+	if err := s.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetCapacityPolicy sets whether sprint assignments that take a member of a
+// project past their capacity are rejected rather than only warned about.
+func (s *WorkloadService) SetCapacityPolicy(projectID string, block bool) (*models.Project, error) {
+	if err := checkWritable(s.projectRepository, projectID); err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	project.BlockOverCapacity = block
+	project.UpdatedAt = time.Now()
+	if err := s.projectRepository.Update(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// Workload reports the workload of every member of a project in a sprint,
+// its active sprint when sprintID is empty.
+func (s *WorkloadService) Workload(projectID, sprintID string) (*Workload, error) {
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sprint, err := s.sprint(project.ID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepository.ListBySprint(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	workload := &Workload{ProjectID: project.ID, SprintID: sprint.ID}
+	members := make(map[string]*MemberWorkload)
+	for _, userID := range projectMembers(s.teamRepository, project) {
+		member := memberWorkload(s.userRepository, sprint, userID, nil)
+		members[userID] = member
+		workload.Members = append(workload.Members, member)
+	}
+
+	for _, task := range tasks {
+		if task.Type == models.TypeEpic {
+			continue
+		}
+		member, ok := members[task.AssigneeID]
+		if !ok {
+			workload.UnassignedTasks++
+			if task.StoryPoints != nil {
+				workload.UnassignedPoints += *task.StoryPoints
+			}
+			continue
+		}
+		member.assign(task)
+	}
+	for _, member := range workload.Members {
+		member.OverCapacity = member.overCapacity()
+	}
+	return workload, nil
+}
+
+// TeamWorkload reports the workload of every member of a team in a sprint,
+// the active sprint of the team's default project when sprintID is empty.
+func (s *WorkloadService) TeamWorkload(teamID, sprintID string) (*TeamWorkload, error) {
+	team, err := s.teamRepository.GetByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	var sprint *models.Sprint
+	if sprintID != "" {
+		sprint, err = s.sprintRepository.GetByID(sprintID)
+	} else if team.DefaultProjectID != "" {
+		sprint, err = s.sprint(team.DefaultProjectID, "")
+	} else {
+		err = errors.New("team has no default project; choose a sprint")
+	}
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepository.ListBySprint(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	workload := &TeamWorkload{TeamID: team.ID, ProjectID: sprint.ProjectID, SprintID: sprint.ID}
+	for _, userID := range team.MemberIDs {
+		workload.Members = append(workload.Members, memberWorkload(s.userRepository, sprint, userID, tasks))
+	}
+	for _, task := range tasks {
+		if task.TeamID != team.ID || task.AssigneeID != "" || task.Type == models.TypeEpic {
+			continue
+		}
+		workload.UnassignedTasks++
+		if task.StoryPoints != nil {
+			workload.UnassignedPoints += *task.StoryPoints
+		}
+	}
+	return workload, nil
+}
+
+func (s *WorkloadService) sprint(projectID, sprintID string) (*models.Sprint, error) {
+	if sprintID != "" {
+		sprint, err := s.sprintRepository.GetByID(sprintID)
+		if err != nil {
+			return nil, err
+		}
+		if sprint.ProjectID != projectID {
+			return nil, errors.New("sprint belongs to another project")
+		}
+		return sprint, nil
+	}
+
+	sprints, err := s.sprintRepository.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sprint := range sprints {
+		if sprint.Status == models.SprintActive {
+			return sprint, nil
+		}
+	}
+	return nil, errors.New("project has no active sprint")
+}
+
+// memberWorkload computes a user's capacity in a sprint and the work assigned
+// to them among tasks. Unknown users have no capacity.
+func memberWorkload(users UserRepository, sprint *models.Sprint, userID string, tasks []*models.Task) *MemberWorkload {
+	member := &MemberWorkload{UserID: userID}
+	user, err := users.GetByID(userID)
+	if err != nil {
+		return member
+	}
+	member.Name = user.Name
+
+	if user.Capacity != nil {
+		workdays, off := sprintWorkdays(sprint, user.Capacity.TimeOff)
+		member.HasCapacity = true
+		member.capacity = user.Capacity
+		member.TimeOffDays = off
+		if workdays > 0 {
+			share := float64(workdays-off) / float64(workdays)
+			member.AvailablePoints = float64(user.Capacity.PointsPerSprint) * share
+			member.AvailableHours = user.Capacity.HoursPerSprint * share
+		}
+	}
+
+	for _, task := range tasks {
+		if task.AssigneeID == userID && task.Type != models.TypeEpic {
+			member.assign(task)
+		}
+	}
+	member.OverCapacity = member.overCapacity()
+	return member
+}
+
+func (m *MemberWorkload) assign(task *models.Task) {
+	m.AssignedTasks++
+	if task.RemainingEstimateMinutes != nil {
+		m.AssignedHours += float64(*task.RemainingEstimateMinutes) / 60
+	}
+	if task.StoryPoints == nil {
+		m.UnestimatedTasks++
+		return
+	}
+	m.AssignedPoints += *task.StoryPoints
+}
+
+// overCapacity checks points and hours only against the capacities the member
+// has set, so a capacity in hours alone ignores story points.
+func (m *MemberWorkload) overCapacity() bool {
+	if m.capacity == nil {
+		return false
+	}
+	return (m.capacity.PointsPerSprint > 0 && float64(m.AssignedPoints) > m.AvailablePoints) ||
+		(m.capacity.HoursPerSprint > 0 && m.AssignedHours > m.AvailableHours)
+}
+
+// checkCapacity reports the workload of a task's assignee in a sprint once
+// the task is added to it, when that takes them past their capacity. If the
+// project blocks over-capacity assignments it fails instead.
+func checkCapacity(users UserRepository, tasks TaskRepository, project *models.Project, sprint *models.Sprint, task *models.Task) (*MemberWorkload, error) {
+	if task.AssigneeID == "" || task.Type == models.TypeEpic {
+		return nil, nil
+	}
+
+	sprintTasks, err := tasks.ListBySprint(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	sprintTasks = slices.DeleteFunc(sprintTasks, func(t *models.Task) bool { return t.ID == task.ID })
+	member := memberWorkload(users, sprint, task.AssigneeID, append(sprintTasks, task))
+	if !member.OverCapacity {
+		return nil, nil
+	}
+
+	if project.BlockOverCapacity {
+		return nil, fmt.Errorf("%w: %d of %.1f points and %.1f of %.1f hours assigned", ErrCapacityExceeded,
+			member.AssignedPoints, member.AvailablePoints, member.AssignedHours, member.AvailableHours)
+	}
+	return member, nil
+}
+
+// sprintWorkdays counts the weekdays of a sprint and how many of them fall
+// in time off.
+func sprintWorkdays(sprint *models.Sprint, timeOff []models.TimeOff) (workdays, off int) {
+	for date := day(sprint.StartDate); !date.After(day(sprint.EndDate)); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		workdays++
+		for _, period := range timeOff {
+			if !date.Before(day(period.Start)) && !date.After(day(period.End)) {
+				off++
+				break
+			}
+		}
+	}
+	return workdays, off
+}
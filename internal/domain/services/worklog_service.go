@@ -0,0 +1,404 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+var (
+	ErrTimesheetLocked = errors.New("timesheet period is locked")
+	ErrTimerRunning    = errors.New("a timer is already running")
+	ErrNoTimer         = errors.New("no timer is running")
+	ErrNotWorklogOwner = errors.New("only the author of a worklog can change it")
+)
+
+type WorklogRepository interface {
+	Create(worklog *models.Worklog) error
+	GetByID(id string) (*models.Worklog, error)
+	Update(worklog *models.Worklog) error
+	Delete(id string) error
+	ListByTask(taskID string) ([]*models.Worklog, error)
+	// ListByUser returns a user's worklogs starting in [from, to).
+	ListByUser(userID string, from, to time.Time) ([]*models.Worklog, error)
+	// ListByProject returns a project's worklogs starting in [from, to).
+	ListByProject(projectID string, from, to time.Time) ([]*models.Worklog, error)
+}
+
+// TimerRepository holds at most one running timer per user.
+type TimerRepository interface {
+	// Start stores a timer unless its user already has one, and reports
+	// whether it did.
+	Start(timer *models.Timer) (bool, error)
+	// Get returns a user's timer, or nil if none is running.
+	Get(userID string) (*models.Timer, error)
+	// Stop removes and returns a user's timer, or nil if none was running.
+	Stop(userID string) (*models.Timer, error)
+}
+
+// Timesheet is the time logged over a week, starting on From, one row per
+// task, or per task and user for a project timesheet. Minutes are by day
+// (UTC), Monday first.
+type Timesheet struct {
+	From         time.Time       `json:"from"`
+	To           time.Time       `json:"to"`
+	UserID       string          `json:"user_id,omitempty"`
+	ProjectID    string          `json:"project_id,omitempty"`
+	Rows         []*TimesheetRow `json:"rows"`
+	DayMinutes   [7]int          `json:"day_minutes"`
+	TotalMinutes int             `json:"total_minutes"`
+}
+
+type TimesheetRow struct {
+	TaskID       string `json:"task_id"`
+	TaskKey      string `json:"task_key,omitempty"`
+	TaskTitle    string `json:"task_title"`
+	UserID       string `json:"user_id"`
+	DayMinutes   [7]int `json:"day_minutes"`
+	TotalMinutes int    `json:"total_minutes"`
+}
+
+type WorklogService struct {
+	repository        WorklogRepository
+	timerRepository   TimerRepository
+	taskRepository    TaskRepository
+	projectRepository ProjectRepository
+}
+
+func NewWorklogService(repository WorklogRepository, timerRepository TimerRepository, taskRepository TaskRepository, projectRepository ProjectRepository) *WorklogService {
+	return &WorklogService{
+		repository:        repository,
+		timerRepository:   timerRepository,
+		taskRepository:    taskRepository,
+		projectRepository: projectRepository,
+	}
+}
+
+// LogWork records time a user spent on a task and takes it off the task's
+// remaining estimate. Worklogs and tasks are stored apart, outside of one
+// transaction: the worklog is written first, as the record of the time spent,
+// and if the task then fails to save, its remaining estimate is left as it was
+// while the worklog stays.
+func (s *WorklogService) LogWork(userID, taskID string, start time.Time, minutes int, note string) (*models.Worklog, error) {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if minutes <= 0 {
+		return nil, errors.New("logged time must be positive")
+	}
+	if err := s.checkUnlocked(task.ProjectID, start); err != nil {
+		return nil, err
+	}
+
+	worklog := &models.Worklog{
+		ID:        generateID(),
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		UserID:    userID,
+		Start:     start,
+		Minutes:   minutes,
+		Note:      strings.TrimSpace(note),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repository.Create(worklog); err != nil {
+		return nil, err
+	}
+	if err := s.adjustRemaining(task, minutes); err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+func (s *WorklogService) GetWorklog(id string) (*models.Worklog, error) {
+	return s.repository.GetByID(id)
+}
+
+// UpdateWorklog changes a worklog. Zero values leave a field unchanged.
+// Neither the old nor the new start may fall in a locked period.
+func (s *WorklogService) UpdateWorklog(caller Caller, id string, start time.Time, minutes int, note string) (*models.Worklog, error) {
+	worklog, err := s.ownedWorklog(caller, id)
+	if err != nil {
+		return nil, err
+	}
+	if minutes < 0 {
+		return nil, errors.New("logged time must be positive")
+	}
+
+	previousMinutes := worklog.Minutes
+	if !start.IsZero() {
+		if err := s.checkUnlocked(worklog.ProjectID, start); err != nil {
+			return nil, err
+		}
+		worklog.Start = start
+	}
+	if minutes > 0 {
+		worklog.Minutes = minutes
+	}
+	if note != "" {
+		worklog.Note = strings.TrimSpace(note)
+	}
+	worklog.UpdatedAt = time.Now()
+	if err := s.repository.Update(worklog); err != nil {
+		return nil, err
+	}
+
+	if worklog.Minutes != previousMinutes {
+		task, err := s.taskRepository.GetByID(worklog.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.adjustRemaining(task, worklog.Minutes-previousMinutes); err != nil {
+			return nil, err
+		}
+	}
+	return worklog, nil
+}
+
+// DeleteWorklog deletes a worklog and gives its time back to the task's
+// remaining estimate.
+func (s *WorklogService) DeleteWorklog(caller Caller, id string) error {
+	worklog, err := s.ownedWorklog(caller, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.Delete(worklog.ID); err != nil {
+		return err
+	}
+
+	task, err := s.taskRepository.GetByID(worklog.TaskID)
+	if err != nil {
+		// The task is gone; there is no estimate left to restore.
+		return nil
+	}
+	return s.adjustRemaining(task, -worklog.Minutes)
+}
+
+// ListWorklogs returns the worklogs of a task, oldest first.
+func (s *WorklogService) ListWorklogs(taskID string) ([]*models.Worklog, error) {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	worklogs, err := s.repository.ListByTask(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(worklogs, func(a, b *models.Worklog) int {
+		return a.Start.Compare(b.Start)
+	})
+	return worklogs, nil
+}
+
+// StartTimer starts timing a user's work on a task. A user runs at most one
+// timer at a time.
+func (s *WorklogService) StartTimer(userID, taskID, note string) (*models.Timer, error) {
+	task, err := findTask(s.taskRepository, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(s.projectRepository, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	timer := &models.Timer{
+		UserID:    userID,
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		StartedAt: time.Now(),
+		Note:      strings.TrimSpace(note),
+	}
+	started, err := s.timerRepository.Start(timer)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, ErrTimerRunning
+	}
+	return timer, nil
+}
+
+func (s *WorklogService) GetTimer(userID string) (*models.Timer, error) {
+	timer, err := s.timerRepository.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	if timer == nil {
+		return nil, ErrNoTimer
+	}
+	return timer, nil
+}
+
+// StopTimer stops a user's timer and logs the time it ran, rounded up to the
+// minute. The timer keeps running when the time cannot be logged, because its
+// task was trashed or its project archived or locked meanwhile, so that the
+// time is not lost.
+func (s *WorklogService) StopTimer(userID string) (*models.Worklog, error) {
+	timer, err := s.GetTimer(userID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := findTask(s.taskRepository, timer.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnlocked(task.ProjectID, timer.StartedAt); err != nil {
+		return nil, err
+	}
+
+	if timer, err = s.timerRepository.Stop(userID); err != nil {
+		return nil, err
+	}
+	if timer == nil {
+		return nil, ErrNoTimer
+	}
+	minutes := int(math.Ceil(time.Since(timer.StartedAt).Minutes()))
+	worklog, err := s.LogWork(userID, timer.TaskID, timer.StartedAt, max(minutes, 1), timer.Note)
+	if err != nil {
+		if _, restartErr := s.timerRepository.Start(timer); restartErr != nil {
+			return nil, errors.Join(err, restartErr)
+		}
+		return nil, err
+	}
+	return worklog, nil
+}
+
+// DiscardTimer stops a user's timer without logging its time, for a timer
+// whose time cannot be logged.
+func (s *WorklogService) DiscardTimer(userID string) error {
+	timer, err := s.timerRepository.Stop(userID)
+	if err != nil {
+		return err
+	}
+	if timer == nil {
+		return ErrNoTimer
+	}
+	return nil
+}
+
+// LockTimesheets locks a project's worklogs that start before until, so they
+// can no longer be added, changed or deleted. A nil until unlocks them all.
+func (s *WorklogService) LockTimesheets(projectID string, until *time.Time) (*models.Project, error) {
+	if err := checkWritable(s.projectRepository, projectID); err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	project.TimesheetLockedUntil = until
+	project.UpdatedAt = time.Now()
+	if err := s.projectRepository.Update(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// UserTimesheet returns the time a user logged in the week starting on the
+// Monday of week, across projects.
+func (s *WorklogService) UserTimesheet(userID string, week time.Time) (*Timesheet, error) {
+	from := weekStart(week)
+	worklogs, err := s.repository.ListByUser(userID, from, from.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+	timesheet := &Timesheet{UserID: userID}
+	s.fillTimesheet(timesheet, from, worklogs)
+	return timesheet, nil
+}
+
+// ProjectTimesheet returns the time logged on a project in the week starting
+// on the Monday of week, by task and user.
+func (s *WorklogService) ProjectTimesheet(projectID string, week time.Time) (*Timesheet, error) {
+	from := weekStart(week)
+	worklogs, err := s.repository.ListByProject(projectID, from, from.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+	timesheet := &Timesheet{ProjectID: projectID}
+	s.fillTimesheet(timesheet, from, worklogs)
+	return timesheet, nil
+}
+
+func (s *WorklogService) fillTimesheet(timesheet *Timesheet, from time.Time, worklogs []*models.Worklog) {
+	timesheet.From = from
+	timesheet.To = from.AddDate(0, 0, 6)
+	timesheet.Rows = make([]*TimesheetRow, 0)
+
+	rows := make(map[[2]string]*TimesheetRow)
+	for _, worklog := range worklogs {
+		key := [2]string{worklog.TaskID, worklog.UserID}
+		row, ok := rows[key]
+		if !ok {
+			row = &TimesheetRow{TaskID: worklog.TaskID, UserID: worklog.UserID}
+			if task, err := s.taskRepository.GetByID(worklog.TaskID); err == nil {
+				row.TaskKey, row.TaskTitle = task.Key, task.Title
+			}
+			rows[key] = row
+			timesheet.Rows = append(timesheet.Rows, row)
+		}
+
+		d := int(day(worklog.Start).Sub(from).Hours()) / 24
+		row.DayMinutes[d] += worklog.Minutes
+		row.TotalMinutes += worklog.Minutes
+		timesheet.DayMinutes[d] += worklog.Minutes
+		timesheet.TotalMinutes += worklog.Minutes
+	}
+
+	slices.SortFunc(timesheet.Rows, func(a, b *TimesheetRow) int {
+		if c := strings.Compare(a.TaskKey, b.TaskKey); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
+}
+
+// ownedWorklog loads a worklog the caller may change: their own, or any for
+// admins, outside the locked period.
+func (s *WorklogService) ownedWorklog(caller Caller, id string) (*models.Worklog, error) {
+	worklog, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if worklog.UserID != caller.UserID && !caller.IsAdmin() {
+		return nil, ErrNotWorklogOwner
+	}
+	if err := s.checkUnlocked(worklog.ProjectID, worklog.Start); err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+func (s *WorklogService) checkUnlocked(projectID string, start time.Time) error {
+	project, err := s.projectRepository.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+	if project.ArchivedAt != nil {
+		return fmt.Errorf("%w: unarchive %s to change it", ErrProjectArchived, project.Name)
+	}
+	if project.TimesheetLockedUntil != nil && start.Before(*project.TimesheetLockedUntil) {
+		return ErrTimesheetLocked
+	}
+	return nil
+}
+
+// adjustRemaining takes minutes off a task's remaining estimate, never going
+// below zero; negative minutes give time back. Unestimated tasks are left
+// alone.
+func (s *WorklogService) adjustRemaining(task *models.Task, minutes int) error {
+	if task.RemainingEstimateMinutes == nil {
+		return nil
+	}
+	remaining := max(*task.RemainingEstimateMinutes-minutes, 0)
+	task.RemainingEstimateMinutes = &remaining
+	task.UpdatedAt = time.Now()
+	return s.taskRepository.Update(task)
+}
@@ -0,0 +1,124 @@
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Language string
+
+const (
+	English Language = "english"
+	Spanish Language = "spanish"
+)
+
+// token is a normalized word and its byte offsets in the original text.
+type token struct {
+	word       string
+	start, end int
+}
+
+// tokenize splits text into lowercase, accent-folded words.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{word: fold(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: fold(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n",
+)
+
+func fold(word string) string {
+	return accents.Replace(strings.ToLower(word))
+}
+
+var stopwords = map[Language]map[string]bool{
+	English: setOf("a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "has", "have",
+		"in", "into", "is", "it", "its", "not", "of", "on", "or", "that", "the", "their", "then",
+		"there", "these", "this", "to", "was", "we", "were", "will", "with"),
+	Spanish: setOf("a", "al", "como", "con", "de", "del", "el", "en", "es", "esta", "este", "la", "las",
+		"lo", "los", "mas", "no", "o", "para", "pero", "por", "que", "se", "si", "sin", "sobre",
+		"su", "sus", "un", "una", "uno", "y"),
+}
+
+func setOf(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+func isStopword(word string) bool {
+	return stopwords[English][word] || stopwords[Spanish][word]
+}
+
+// DetectLanguage guesses whether text is Spanish or English by counting
+// stopwords. Ties, including text with no stopwords at all, count as English.
+func DetectLanguage(text string) Language {
+	english, spanish := 0, 0
+	for _, tok := range tokenize(text) {
+		if stopwords[English][tok.word] {
+			english++
+		}
+		if stopwords[Spanish][tok.word] {
+			spanish++
+		}
+	}
+	if spanish > english {
+		return Spanish
+	}
+	return English
+}
+
+// Terms returns the stemmed index terms of text in the given language.
+func Terms(text string, lang Language) []string {
+	var terms []string
+	for _, tok := range tokenize(text) {
+		if isStopword(tok.word) {
+			continue
+		}
+		terms = append(terms, Stem(tok.word, lang))
+	}
+	return terms
+}
+
+// queryTerms returns, for every word of a search query, the set of stems it
+// may have been indexed under. The query language is unknown, so each word is
+// stemmed as both English and Spanish.
+func queryTerms(text string) [][]string {
+	var terms [][]string
+	for _, tok := range tokenize(text) {
+		if isStopword(tok.word) {
+			continue
+		}
+		en, es := Stem(tok.word, English), Stem(tok.word, Spanish)
+		if en == es {
+			terms = append(terms, []string{en})
+		} else {
+			terms = append(terms, []string{en, es})
+		}
+	}
+	return terms
+}
@@ -0,0 +1,62 @@
+package textsearch
+
+import (
+	"html"
+	"strings"
+)
+
+// Highlight returns a fragment of text of roughly maxLen bytes around the
+// first word matching the search query. Matching words are wrapped in <mark>
+// tags and everything else is HTML-escaped. ok is false when nothing matched.
+func Highlight(text, query string, maxLen int) (snippet string, ok bool) {
+	wanted := make(map[string]bool)
+	for _, stems := range queryTerms(query) {
+		for _, stem := range stems {
+			wanted[stem] = true
+		}
+	}
+
+	tokens := tokenize(text)
+	matched := make([]bool, len(tokens))
+	first := -1
+	for i, tok := range tokens {
+		if wanted[Stem(tok.word, English)] || wanted[Stem(tok.word, Spanish)] {
+			matched[i] = true
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	// Keep about a third of the fragment as leading context.
+	from, to := first, first
+	for from > 0 && tokens[first].end-tokens[from-1].start <= maxLen/3 {
+		from--
+	}
+	for to < len(tokens)-1 && tokens[to+1].end-tokens[from].start <= maxLen {
+		to++
+	}
+
+	var sb strings.Builder
+	if from > 0 {
+		sb.WriteString("…")
+	}
+	pos := tokens[from].start
+	for i := from; i <= to; i++ {
+		tok := tokens[i]
+		sb.WriteString(html.EscapeString(text[pos:tok.start]))
+		if matched[i] {
+			sb.WriteString("<mark>" + html.EscapeString(text[tok.start:tok.end]) + "</mark>")
+		} else {
+			sb.WriteString(html.EscapeString(text[tok.start:tok.end]))
+		}
+		pos = tok.end
+	}
+	if to < len(tokens)-1 {
+		sb.WriteString("…")
+	}
+	return sb.String(), true
+}
@@ -0,0 +1,136 @@
+package textsearch
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Field is a piece of document text and the weight its matches carry.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Match is a search hit and its relevance score; higher is more relevant.
+type Match struct {
+	ID    string
+	Score float64
+}
+
+// Index is an in-process inverted index ranked with BM25. Each document is
+// stemmed in the language detected from its text; queries are stemmed in
+// both supported languages.
+type Index struct {
+	postings map[string]map[string]float64 // term -> document -> weighted frequency
+	terms    map[string][]string           // document -> distinct terms
+	lengths  map[string]float64
+	total    float64
+	mu       sync.RWMutex
+}
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]float64),
+		terms:    make(map[string][]string),
+		lengths:  make(map[string]float64),
+	}
+}
+
+// Add indexes a document, replacing any previous version with the same ID.
+func (ix *Index) Add(id string, fields ...Field) {
+	var all string
+	for _, f := range fields {
+		all += f.Text + " "
+	}
+	lang := DetectLanguage(all)
+
+	frequencies := make(map[string]float64)
+	length := 0.0
+	for _, f := range fields {
+		for _, term := range Terms(f.Text, lang) {
+			frequencies[term] += f.Weight
+			length += f.Weight
+		}
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+	terms := make([]string, 0, len(frequencies))
+	for term, freq := range frequencies {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[string]float64)
+		}
+		ix.postings[term][id] = freq
+		terms = append(terms, term)
+	}
+	ix.terms[id] = terms
+	ix.lengths[id] = length
+	ix.total += length
+}
+
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+}
+
+func (ix *Index) remove(id string) {
+	for _, term := range ix.terms[id] {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	ix.total -= ix.lengths[id]
+	delete(ix.terms, id)
+	delete(ix.lengths, id)
+}
+
+// Search returns the documents matching any word of text, most relevant first.
+func (ix *Index) Search(text string) []Match {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	n := float64(len(ix.terms))
+	if n == 0 {
+		return nil
+	}
+	avgLength := ix.total / n
+
+	scores := make(map[string]float64)
+	for _, stems := range queryTerms(text) {
+		frequencies := make(map[string]float64)
+		for _, stem := range stems {
+			for id, freq := range ix.postings[stem] {
+				frequencies[id] = math.Max(frequencies[id], freq)
+			}
+		}
+
+		df := float64(len(frequencies))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, freq := range frequencies {
+			norm := bm25K1 * (1 - bm25B + bm25B*ix.lengths[id]/avgLength)
+			scores[id] += idf * freq * (bm25K1 + 1) / (freq + norm)
+		}
+	}
+
+	matches := make([]Match, 0, len(scores))
+	for id, score := range scores {
+		matches = append(matches, Match{ID: id, Score: score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches
+}
@@ -0,0 +1,194 @@
+package textsearch
+
+import "strings"
+
+// Stem reduces an already folded word to its stem in the given language.
+func Stem(word string, lang Language) string {
+	if lang == Spanish {
+		return stemSpanish(word)
+	}
+	return stemEnglish(word)
+}
+
+// stemSpanish is a light stemmer that removes plural and gender endings, in
+// the spirit of Savoy's Spanish light stemmer. Accents are already folded.
+func stemSpanish(w string) string {
+	if len(w) < 5 {
+		return w
+	}
+	switch w[len(w)-1] {
+	case 'o', 'a', 'e':
+		return w[:len(w)-1]
+	case 's':
+		switch {
+		case strings.HasSuffix(w, "eses"):
+			return w[:len(w)-2]
+		case strings.HasSuffix(w, "ces"):
+			return w[:len(w)-3] + "z"
+		case strings.HasSuffix(w, "os"), strings.HasSuffix(w, "as"), strings.HasSuffix(w, "es"):
+			return w[:len(w)-2]
+		}
+	}
+	return w
+}
+
+// stemEnglish implements the original Porter stemming algorithm.
+func stemEnglish(w string) string {
+	if len(w) <= 2 {
+		return w
+	}
+
+	// Step 1a: plurals.
+	switch {
+	case strings.HasSuffix(w, "sses"):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "ies"):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "ss"):
+	case strings.HasSuffix(w, "s"):
+		w = w[:len(w)-1]
+	}
+
+	// Step 1b: past tense and gerunds.
+	fixup := false
+	switch {
+	case strings.HasSuffix(w, "eed"):
+		if measure(w[:len(w)-3]) > 0 {
+			w = w[:len(w)-1]
+		}
+	case strings.HasSuffix(w, "ed") && hasVowel(w[:len(w)-2]):
+		w, fixup = w[:len(w)-2], true
+	case strings.HasSuffix(w, "ing") && hasVowel(w[:len(w)-3]):
+		w, fixup = w[:len(w)-3], true
+	}
+	if fixup {
+		switch {
+		case strings.HasSuffix(w, "at"), strings.HasSuffix(w, "bl"), strings.HasSuffix(w, "iz"):
+			w += "e"
+		case endsWithDoubleConsonant(w) && !strings.ContainsAny(w[len(w)-1:], "lsz"):
+			w = w[:len(w)-1]
+		case measure(w) == 1 && endsCVC(w):
+			w += "e"
+		}
+	}
+
+	// Step 1c: terminal y.
+	if strings.HasSuffix(w, "y") && hasVowel(w[:len(w)-1]) {
+		w = w[:len(w)-1] + "i"
+	}
+
+	w = replaceSuffix(w, step2)
+	w = replaceSuffix(w, step3)
+
+	// Step 4: strip derivational suffixes from long stems.
+	for _, suffix := range step4 {
+		if !strings.HasSuffix(w, suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if measure(stem) > 1 && (suffix != "ion" || strings.HasSuffix(stem, "s") || strings.HasSuffix(stem, "t")) {
+			w = stem
+		}
+		break
+	}
+
+	// Step 5: tidy up.
+	if strings.HasSuffix(w, "e") {
+		stem := w[:len(w)-1]
+		if m := measure(stem); m > 1 || (m == 1 && !endsCVC(stem)) {
+			w = stem
+		}
+	}
+	if strings.HasSuffix(w, "ll") && measure(w) > 1 {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+type suffixRule struct {
+	suffix, replacement string
+}
+
+var step2 = []suffixRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"abli", "able"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+var step3 = []suffixRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+var step4 = []string{
+	"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism", "ate",
+	"iti", "ous", "ive", "ize", "ion", "al", "er", "ic", "ou",
+}
+
+// replaceSuffix applies the first rule whose suffix matches, provided the
+// remaining stem is not empty of vowel-consonant sequences.
+func replaceSuffix(w string, rules []suffixRule) string {
+	for _, rule := range rules {
+		if strings.HasSuffix(w, rule.suffix) {
+			stem := w[:len(w)-len(rule.suffix)]
+			if measure(stem) > 0 {
+				return stem + rule.replacement
+			}
+			return w
+		}
+	}
+	return w
+}
+
+func isConsonant(w string, i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(w, i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in w, Porter's m.
+func measure(w string) int {
+	m := 0
+	inVowel := false
+	for i := range len(w) {
+		if isConsonant(w, i) {
+			if inVowel {
+				m++
+			}
+			inVowel = false
+		} else {
+			inVowel = true
+		}
+	}
+	return m
+}
+
+func hasVowel(w string) bool {
+	for i := range len(w) {
+		if !isConsonant(w, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func endsWithDoubleConsonant(w string) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && isConsonant(w, n-1)
+}
+
+// endsCVC reports whether w ends consonant-vowel-consonant where the final
+// consonant is not w, x or y.
+func endsCVC(w string) bool {
+	n := len(w)
+	if n < 3 || !isConsonant(w, n-1) || isConsonant(w, n-2) || !isConsonant(w, n-3) {
+		return false
+	}
+	return !strings.ContainsAny(w[n-1:], "wxy")
+}
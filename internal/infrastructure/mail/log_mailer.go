@@ -0,0 +1,20 @@
+package mail
+
+import (
+	"log"
+
+	"go-project-manager-backend/internal/domain/services"
+)
+
+// LogMailer logs emails instead of sending them, for deployments without an
+// SMTP server.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(message *services.EmailMessage) error {
+	log.Printf("Email to %s: %s\n%s", message.To, message.Subject, message.Text)
+	return nil
+}
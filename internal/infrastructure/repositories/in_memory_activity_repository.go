@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"slices"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryActivityRepository struct {
+	activities []*models.Activity
+	mu         sync.RWMutex
+}
+
+func NewInMemoryActivityRepository() *InMemoryActivityRepository {
+	return &InMemoryActivityRepository{}
+}
+
+func (r *InMemoryActivityRepository) Create(activity *models.Activity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.activities = append(r.activities, activity)
+	return nil
+}
+
+func (r *InMemoryActivityRepository) ListByTasks(taskIDs []string) ([]*models.Activity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	activities := make([]*models.Activity, 0)
+	for _, activity := range r.activities {
+		if slices.Contains(taskIDs, activity.TaskID) {
+			activities = append(activities, activity)
+		}
+	}
+	slices.SortStableFunc(activities, func(a, b *models.Activity) int {
+		return a.At.Compare(b.At)
+	})
+	return activities, nil
+}
+
+func (r *InMemoryActivityRepository) TaskIDsForSprint(sprintID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0)
+	for _, activity := range r.activities {
+		if activity.Field != "sprint" || (activity.From != sprintID && activity.To != sprintID) {
+			continue
+		}
+		if !slices.Contains(ids, activity.TaskID) {
+			ids = append(ids, activity.TaskID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/textsearch"
+)
+
+type InMemoryCommentRepository struct {
+	comments map[string]*models.Comment
+	index    *textsearch.Index
+	tasks    *InMemoryTaskRepository
+	mu       sync.RWMutex
+}
+
+// NewInMemoryCommentRepository creates a repository for the comments of the
+// tasks in tasks, which it reads to search by project.
+func NewInMemoryCommentRepository(tasks *InMemoryTaskRepository) *InMemoryCommentRepository {
+	return &InMemoryCommentRepository{
+		comments: make(map[string]*models.Comment),
+		index:    textsearch.NewIndex(),
+		tasks:    tasks,
+	}
+}
+
+func (r *InMemoryCommentRepository) Create(comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.comments[comment.ID]; exists {
+		return errors.New("comment already exists")
+	}
+
+	r.comments[comment.ID] = comment
+	r.index.Add(comment.ID, textsearch.Field{Text: comment.Body, Weight: 1})
+	return nil
+}
+
+func (r *InMemoryCommentRepository) GetByID(id string) (*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, exists := r.comments[id]
+	if !exists {
+		return nil, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+func (r *InMemoryCommentRepository) Update(comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.comments[comment.ID]; !exists {
+		return errors.New("comment not found")
+	}
+
+	r.comments[comment.ID] = comment
+	r.index.Add(comment.ID, textsearch.Field{Text: comment.Body, Weight: 1})
+	return nil
+}
+
+func (r *InMemoryCommentRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.comments[id]; !exists {
+		return errors.New("comment not found")
+	}
+
+	delete(r.comments, id)
+	r.index.Remove(id)
+	return nil
+}
+
+func (r *InMemoryCommentRepository) ListByTask(taskID string) ([]*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comments := make([]*models.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.TaskID == taskID {
+			comments = append(comments, comment)
+		}
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return comments, nil
+}
+
+func (r *InMemoryCommentRepository) ListByIDs(ids []string) ([]*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comments := make([]*models.Comment, 0, len(ids))
+	for _, id := range ids {
+		if comment, exists := r.comments[id]; exists {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+func (r *InMemoryCommentRepository) SearchText(text string, projectIDs []string) ([]textsearch.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := make([]textsearch.Match, 0)
+	for _, match := range r.index.Search(text) {
+		if projectIDs != nil {
+			projectID, ok := r.tasks.projectOf(r.comments[match.ID].TaskID)
+			if !ok || !slices.Contains(projectIDs, projectID) {
+				continue
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import "sync"
+
+type InMemoryCounterRepository struct {
+	counters map[string]int
+	mu       sync.Mutex
+}
+
+func NewInMemoryCounterRepository() *InMemoryCounterRepository {
+	return &InMemoryCounterRepository{
+		counters: make(map[string]int),
+	}
+}
+
+func (r *InMemoryCounterRepository) Next(name string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counters[name]++
+	return r.counters[name], nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryCustomFieldRepository struct {
+	fields map[string]*models.CustomField
+	mu     sync.RWMutex
+}
+
+func NewInMemoryCustomFieldRepository() *InMemoryCustomFieldRepository {
+	return &InMemoryCustomFieldRepository{
+		fields: make(map[string]*models.CustomField),
+	}
+}
+
+func (r *InMemoryCustomFieldRepository) Create(field *models.CustomField) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.fields[field.ID]; exists {
+		return errors.New("custom field already exists")
+	}
+	for _, other := range r.fields {
+		if other.ProjectID == field.ProjectID && strings.EqualFold(other.Name, field.Name) {
+			return errors.New("custom field already exists")
+		}
+	}
+
+	r.fields[field.ID] = field
+	return nil
+}
+
+func (r *InMemoryCustomFieldRepository) GetByID(id string) (*models.CustomField, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	field, exists := r.fields[id]
+	if !exists {
+		return nil, errors.New("custom field not found")
+	}
+	return field, nil
+}
+
+func (r *InMemoryCustomFieldRepository) Update(field *models.CustomField) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.fields[field.ID]; !exists {
+		return errors.New("custom field not found")
+	}
+	for _, other := range r.fields {
+		if other.ID != field.ID && other.ProjectID == field.ProjectID && strings.EqualFold(other.Name, field.Name) {
+			return errors.New("custom field already exists")
+		}
+	}
+
+	r.fields[field.ID] = field
+	return nil
+}
+
+func (r *InMemoryCustomFieldRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.fields[id]; !exists {
+		return errors.New("custom field not found")
+	}
+
+	delete(r.fields, id)
+	return nil
+}
+
+func (r *InMemoryCustomFieldRepository) ListByProject(projectID string) ([]*models.CustomField, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fields := make([]*models.CustomField, 0)
+	for _, field := range r.fields {
+		if field.ProjectID == projectID {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryFilterRepository struct {
+	filters map[string]*models.SavedFilter
+	mu      sync.RWMutex
+}
+
+func NewInMemoryFilterRepository() *InMemoryFilterRepository {
+	return &InMemoryFilterRepository{
+		filters: make(map[string]*models.SavedFilter),
+	}
+}
+
+func (r *InMemoryFilterRepository) Create(filter *models.SavedFilter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.filters[filter.ID]; exists {
+		return errors.New("filter already exists")
+	}
+
+	r.filters[filter.ID] = filter
+	return nil
+}
+
+func (r *InMemoryFilterRepository) GetByID(id string) (*models.SavedFilter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filter, exists := r.filters[id]
+	if !exists {
+		return nil, errors.New("filter not found")
+	}
+	return filter, nil
+}
+
+func (r *InMemoryFilterRepository) Update(filter *models.SavedFilter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.filters[filter.ID]; !exists {
+		return errors.New("filter not found")
+	}
+
+	r.filters[filter.ID] = filter
+	return nil
+}
+
+func (r *InMemoryFilterRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.filters[id]; !exists {
+		return errors.New("filter not found")
+	}
+
+	delete(r.filters, id)
+	return nil
+}
+
+func (r *InMemoryFilterRepository) ListByOwner(ownerID string) ([]*models.SavedFilter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filters := make([]*models.SavedFilter, 0)
+	for _, filter := range r.filters {
+		if filter.OwnerID == ownerID {
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
+
+func (r *InMemoryFilterRepository) ListByProjects(projectIDs []string) ([]*models.SavedFilter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filters := make([]*models.SavedFilter, 0)
+	for _, filter := range r.filters {
+		if filter.ProjectID != "" && slices.Contains(projectIDs, filter.ProjectID) {
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
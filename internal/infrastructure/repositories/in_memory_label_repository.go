@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryLabelRepository struct {
+	labels map[string]*models.Label
+	mu     sync.RWMutex
+}
+
+func NewInMemoryLabelRepository() *InMemoryLabelRepository {
+	return &InMemoryLabelRepository{
+		labels: make(map[string]*models.Label),
+	}
+}
+
+func (r *InMemoryLabelRepository) Create(label *models.Label) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.labels[label.ID]; exists {
+		return errors.New("label already exists")
+	}
+	for _, other := range r.labels {
+		if other.ProjectID == label.ProjectID && strings.EqualFold(other.Name, label.Name) {
+			return errors.New("label already exists")
+		}
+	}
+
+	r.labels[label.ID] = label
+	return nil
+}
+
+func (r *InMemoryLabelRepository) GetByID(id string) (*models.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	label, exists := r.labels[id]
+	if !exists {
+		return nil, errors.New("label not found")
+	}
+	return label, nil
+}
+
+func (r *InMemoryLabelRepository) Update(label *models.Label) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.labels[label.ID]; !exists {
+		return errors.New("label not found")
+	}
+	for _, other := range r.labels {
+		if other.ID != label.ID && other.ProjectID == label.ProjectID && strings.EqualFold(other.Name, label.Name) {
+			return errors.New("label already exists")
+		}
+	}
+
+	r.labels[label.ID] = label
+	return nil
+}
+
+func (r *InMemoryLabelRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.labels[id]; !exists {
+		return errors.New("label not found")
+	}
+
+	delete(r.labels, id)
+	return nil
+}
+
+func (r *InMemoryLabelRepository) ListByProject(projectID string) ([]*models.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	labels := make([]*models.Label, 0)
+	for _, label := range r.labels {
+		if label.ProjectID == projectID {
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+func (r *InMemoryLabelRepository) ListByName(name string) ([]*models.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	labels := make([]*models.Label, 0)
+	for _, label := range r.labels {
+		if strings.EqualFold(label.Name, name) {
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
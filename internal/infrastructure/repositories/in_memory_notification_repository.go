@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryNotificationRepository struct {
+	notifications map[string]*models.Notification
+	mu            sync.RWMutex
+}
+
+func NewInMemoryNotificationRepository() *InMemoryNotificationRepository {
+	return &InMemoryNotificationRepository{
+		notifications: make(map[string]*models.Notification),
+	}
+}
+
+func (r *InMemoryNotificationRepository) Create(notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.notifications[notification.ID]; exists {
+		return errors.New("notification already exists")
+	}
+
+	c := *notification
+	r.notifications[notification.ID] = &c
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) ListByUser(userID string, unreadOnly bool, limit int) ([]*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := make([]*models.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.UserID != userID || (unreadOnly && notification.ReadAt != nil) {
+			continue
+		}
+		c := *notification
+		notifications = append(notifications, &c)
+	}
+	slices.SortFunc(notifications, func(a, b *models.Notification) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (r *InMemoryNotificationRepository) CountUnread(userID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, notification := range r.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemoryNotificationRepository) MarkRead(userID, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notification, exists := r.notifications[id]
+	if !exists || notification.UserID != userID {
+		return errors.New("notification not found")
+	}
+	if notification.ReadAt == nil {
+		notification.ReadAt = &at
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) MarkAllRead(userID string, at time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, notification := range r.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			notification.ReadAt = &at
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemoryNotificationRepository) ListUnemailed(userID string, since time.Time) ([]*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := make([]*models.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.UserID != userID || notification.ReadAt != nil || notification.EmailedAt != nil || notification.CreatedAt.Before(since) {
+			continue
+		}
+		c := *notification
+		notifications = append(notifications, &c)
+	}
+	slices.SortFunc(notifications, func(a, b *models.Notification) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return notifications, nil
+}
+
+func (r *InMemoryNotificationRepository) MarkEmailed(ids []string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		if notification, exists := r.notifications[id]; exists {
+			notification.EmailedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) HasDueSoon(userID, taskID string, dueDate time.Time) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, notification := range r.notifications {
+		if notification.UserID == userID && notification.TaskID == taskID && notification.Event == models.NotifyDueSoon &&
+			notification.DueDate != nil && notification.DueDate.Equal(dueDate) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryOrganizationRepository struct {
+	organizations map[string]*models.Organization
+	mu            sync.RWMutex
+}
+
+func NewInMemoryOrganizationRepository() *InMemoryOrganizationRepository {
+	return &InMemoryOrganizationRepository{
+		organizations: make(map[string]*models.Organization),
+	}
+}
+
+func (r *InMemoryOrganizationRepository) Create(organization *models.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.organizations[organization.ID]; exists {
+		return errors.New("organization already exists")
+	}
+
+	r.organizations[organization.ID] = organization
+	return nil
+}
+
+func (r *InMemoryOrganizationRepository) GetByID(id string) (*models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	organization, exists := r.organizations[id]
+	if !exists {
+		return nil, errors.New("organization not found")
+	}
+	return organization, nil
+}
+
+func (r *InMemoryOrganizationRepository) List() ([]*models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	organizations := make([]*models.Organization, 0, len(r.organizations))
+	for _, organization := range r.organizations {
+		organizations = append(organizations, organization)
+	}
+	slices.SortFunc(organizations, func(a, b *models.Organization) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return organizations, nil
+}
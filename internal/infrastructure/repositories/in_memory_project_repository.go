@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryProjectRepository struct {
+	projects map[string]*models.Project
+	mu       sync.RWMutex
+}
+
+func NewInMemoryProjectRepository() *InMemoryProjectRepository {
+	return &InMemoryProjectRepository{
+		projects: make(map[string]*models.Project),
+	}
+}
+
+func (r *InMemoryProjectRepository) Create(project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.projects[project.ID]; exists {
+		return errors.New("project already exists")
+	}
+	for _, other := range r.projects {
+		if project.Key != "" && other.Key == project.Key {
+			return errors.New("project key already in use")
+		}
+	}
+
+	r.projects[project.ID] = project
+	return nil
+}
+
+func (r *InMemoryProjectRepository) GetByID(id string) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, exists := r.projects[id]
+	if !exists || project.DeletedAt != nil {
+		return nil, errors.New("project not found")
+	}
+	return project, nil
+}
+
+func (r *InMemoryProjectRepository) GetByKey(key string) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, project := range r.projects {
+		if project.Key == key {
+			return project, nil
+		}
+	}
+	return nil, errors.New("project not found")
+}
+
+func (r *InMemoryProjectRepository) Update(project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.projects[project.ID]; !exists {
+		return errors.New("project not found")
+	}
+
+	r.projects[project.ID] = project
+	return nil
+}
+
+func (r *InMemoryProjectRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.projects[id]; !exists {
+		return errors.New("project not found")
+	}
+
+	delete(r.projects, id)
+	return nil
+}
+
+func (r *InMemoryProjectRepository) List() ([]*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]*models.Project, 0, len(r.projects))
+	for _, project := range r.projects {
+		if project.DeletedAt == nil {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+func (r *InMemoryProjectRepository) ListByMember(userID string, teamIDs []string) ([]*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]*models.Project, 0)
+	for _, project := range r.projects {
+		if project.DeletedAt != nil {
+			continue
+		}
+		if slices.Contains(project.MemberIDs, userID) || slices.ContainsFunc(project.TeamIDs, func(id string) bool { return slices.Contains(teamIDs, id) }) {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+func (r *InMemoryProjectRepository) GetTrashed(id string) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, exists := r.projects[id]
+	if !exists || project.DeletedAt == nil {
+		return nil, errors.New("project not found")
+	}
+	return project, nil
+}
+
+func (r *InMemoryProjectRepository) ListTrash() ([]*models.Project, error) {
+	return r.listTrashed(func(*models.Project) bool { return true })
+}
+
+func (r *InMemoryProjectRepository) ListTrashedBefore(cutoff time.Time) ([]*models.Project, error) {
+	return r.listTrashed(func(project *models.Project) bool { return project.DeletedAt.Before(cutoff) })
+}
+
+func (r *InMemoryProjectRepository) listTrashed(keep func(project *models.Project) bool) ([]*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]*models.Project, 0)
+	for _, project := range r.projects {
+		if project.DeletedAt != nil && keep(project) {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryProjectTemplateRepository struct {
+	templates map[string]*models.ProjectTemplate
+	mu        sync.RWMutex
+}
+
+func NewInMemoryProjectTemplateRepository() *InMemoryProjectTemplateRepository {
+	return &InMemoryProjectTemplateRepository{
+		templates: make(map[string]*models.ProjectTemplate),
+	}
+}
+
+func (r *InMemoryProjectTemplateRepository) Create(template *models.ProjectTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.ID]; exists {
+		return errors.New("project template already exists")
+	}
+
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *InMemoryProjectTemplateRepository) GetByID(id string) (*models.ProjectTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, exists := r.templates[id]
+	if !exists {
+		return nil, errors.New("project template not found")
+	}
+	return template, nil
+}
+
+func (r *InMemoryProjectTemplateRepository) Update(template *models.ProjectTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.ID]; !exists {
+		return errors.New("project template not found")
+	}
+
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *InMemoryProjectTemplateRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[id]; !exists {
+		return errors.New("project template not found")
+	}
+
+	delete(r.templates, id)
+	return nil
+}
+
+func (r *InMemoryProjectTemplateRepository) List() ([]*models.ProjectTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]*models.ProjectTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		templates = append(templates, template)
+	}
+	slices.SortFunc(templates, func(a, b *models.ProjectTemplate) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return templates, nil
+}
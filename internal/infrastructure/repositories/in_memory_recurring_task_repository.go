@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryRecurringTaskRepository struct {
+	recurring map[string]*models.RecurringTask
+	mu        sync.RWMutex
+}
+
+func NewInMemoryRecurringTaskRepository() *InMemoryRecurringTaskRepository {
+	return &InMemoryRecurringTaskRepository{
+		recurring: make(map[string]*models.RecurringTask),
+	}
+}
+
+func (r *InMemoryRecurringTaskRepository) Create(recurring *models.RecurringTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.recurring[recurring.ID]; exists {
+		return errors.New("recurring task already exists")
+	}
+
+	r.recurring[recurring.ID] = recurring
+	return nil
+}
+
+func (r *InMemoryRecurringTaskRepository) GetByID(id string) (*models.RecurringTask, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recurring, exists := r.recurring[id]
+	if !exists {
+		return nil, errors.New("recurring task not found")
+	}
+	return recurring, nil
+}
+
+func (r *InMemoryRecurringTaskRepository) Update(recurring *models.RecurringTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.recurring[recurring.ID]; !exists {
+		return errors.New("recurring task not found")
+	}
+
+	r.recurring[recurring.ID] = recurring
+	return nil
+}
+
+func (r *InMemoryRecurringTaskRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.recurring[id]; !exists {
+		return errors.New("recurring task not found")
+	}
+
+	delete(r.recurring, id)
+	return nil
+}
+
+func (r *InMemoryRecurringTaskRepository) ListByProject(projectID string) ([]*models.RecurringTask, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recurring := make([]*models.RecurringTask, 0)
+	for _, rt := range r.recurring {
+		if rt.ProjectID == projectID {
+			recurring = append(recurring, rt)
+		}
+	}
+	return recurring, nil
+}
+
+func (r *InMemoryRecurringTaskRepository) ListDue(now time.Time) ([]*models.RecurringTask, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	due := make([]*models.RecurringTask, 0)
+	for _, rt := range r.recurring {
+		if !rt.Paused && rt.NextRunAt != nil && !rt.NextRunAt.After(now) {
+			c := *rt
+			due = append(due, &c)
+		}
+	}
+	return due, nil
+}
+
+func (r *InMemoryRecurringTaskRepository) ClaimRun(id string, expected time.Time, next *time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rt, exists := r.recurring[id]
+	if !exists || rt.Paused || rt.NextRunAt == nil || !rt.NextRunAt.Equal(expected) {
+		return false, nil
+	}
+	rt.LastRunAt = &expected
+	rt.NextRunAt = next
+	rt.UpdatedAt = time.Now()
+	return true, nil
+}
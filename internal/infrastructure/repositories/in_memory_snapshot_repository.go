@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type snapshotKey struct {
+	sprintID string
+	date     time.Time
+}
+
+type InMemorySnapshotRepository struct {
+	snapshots map[snapshotKey]*models.SprintSnapshot
+	mu        sync.RWMutex
+}
+
+func NewInMemorySnapshotRepository() *InMemorySnapshotRepository {
+	return &InMemorySnapshotRepository{
+		snapshots: make(map[snapshotKey]*models.SprintSnapshot),
+	}
+}
+
+func (r *InMemorySnapshotRepository) Upsert(snapshot *models.SprintSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.snapshots[snapshotKey{snapshot.SprintID, snapshot.Date.UTC()}] = snapshot
+	return nil
+}
+
+func (r *InMemorySnapshotRepository) ListBySprint(sprintID string) ([]*models.SprintSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := make([]*models.SprintSnapshot, 0)
+	for key, snapshot := range r.snapshots {
+		if key.sprintID == sprintID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	slices.SortFunc(snapshots, func(a, b *models.SprintSnapshot) int {
+		return a.Date.Compare(b.Date)
+	})
+	return snapshots, nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemorySprintRepository struct {
+	sprints map[string]*models.Sprint
+	mu      sync.RWMutex
+}
+
+func NewInMemorySprintRepository() *InMemorySprintRepository {
+	return &InMemorySprintRepository{
+		sprints: make(map[string]*models.Sprint),
+	}
+}
+
+func (r *InMemorySprintRepository) Create(sprint *models.Sprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sprints[sprint.ID]; exists {
+		return errors.New("sprint already exists")
+	}
+
+	r.sprints[sprint.ID] = sprint
+	return nil
+}
+
+func (r *InMemorySprintRepository) GetByID(id string) (*models.Sprint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sprint, exists := r.sprints[id]
+	if !exists {
+		return nil, errors.New("sprint not found")
+	}
+	return sprint, nil
+}
+
+func (r *InMemorySprintRepository) Update(sprint *models.Sprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sprints[sprint.ID]; !exists {
+		return errors.New("sprint not found")
+	}
+
+	r.sprints[sprint.ID] = sprint
+	return nil
+}
+
+func (r *InMemorySprintRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sprints[id]; !exists {
+		return errors.New("sprint not found")
+	}
+
+	delete(r.sprints, id)
+	return nil
+}
+
+func (r *InMemorySprintRepository) ListByProject(projectID string) ([]*models.Sprint, error) {
+	return r.list(func(sprint *models.Sprint) bool { return sprint.ProjectID == projectID })
+}
+
+func (r *InMemorySprintRepository) ListByStatus(status models.SprintStatus) ([]*models.Sprint, error) {
+	return r.list(func(sprint *models.Sprint) bool { return sprint.Status == status })
+}
+
+func (r *InMemorySprintRepository) list(match func(*models.Sprint) bool) ([]*models.Sprint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sprints := make([]*models.Sprint, 0)
+	for _, sprint := range r.sprints {
+		if match(sprint) {
+			sprints = append(sprints, sprint)
+		}
+	}
+	slices.SortFunc(sprints, func(a, b *models.Sprint) int {
+		return a.StartDate.Compare(b.StartDate)
+	})
+	return sprints, nil
+}
@@ -2,23 +2,35 @@ package repositories
 
 import (
 	"errors"
+	"slices"
 	"sync"
+	"time"
 
 	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+	"go-project-manager-backend/internal/domain/services"
+	"go-project-manager-backend/internal/domain/textsearch"
 )
 
 type InMemoryTaskRepository struct {
 	tasks map[string]*models.Task
+	index *textsearch.Index
 	mu    sync.RWMutex
+	tx    sync.Mutex
 }
 
 func NewInMemoryTaskRepository() *InMemoryTaskRepository {
 	return &InMemoryTaskRepository{
 		tasks: make(map[string]*models.Task),
+		index: textsearch.NewIndex(),
 	}
 }
 
 func (r *InMemoryTaskRepository) Create(task *models.Task) error {
+	return r.create(task, nil)
+}
+
+func (r *InMemoryTaskRepository) create(task *models.Task, saved savedTasks) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -26,7 +38,9 @@ func (r *InMemoryTaskRepository) Create(task *models.Task) error {
 		return errors.New("task already exists")
 	}
 
-	r.tasks[task.ID] = task
+	saved.save(r.tasks, task.ID)
+	r.tasks[task.ID] = cloneTask(task)
+	r.index.Add(task.ID, taskSearchFields(task)...)
 	return nil
 }
 
@@ -35,25 +49,76 @@ func (r *InMemoryTaskRepository) GetByID(id string) (*models.Task, error) {
 	defer r.mu.RUnlock()
 
 	task, exists := r.tasks[id]
-	if !exists {
+	if !exists || task.DeletedAt != nil {
 		return nil, errors.New("task not found")
 	}
-	return task, nil
+	return cloneTask(task), nil
+}
+
+func (r *InMemoryTaskRepository) GetByKey(key string) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && (task.Key == key || slices.Contains(task.PreviousKeys, key)) {
+			return cloneTask(task), nil
+		}
+	}
+	return nil, errors.New("task not found")
+}
+
+func (r *InMemoryTaskRepository) ListByIDs(ids []string) ([]*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tasks := make([]*models.Task, 0, len(ids))
+	for _, id := range ids {
+		if task, exists := r.tasks[id]; exists && task.DeletedAt == nil {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+	return tasks, nil
+}
+
+// projectOf returns the project of a task, or false for a missing or trashed
+// task.
+func (r *InMemoryTaskRepository) projectOf(id string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, exists := r.tasks[id]
+	if !exists || task.DeletedAt != nil {
+		return "", false
+	}
+	return task.ProjectID, true
 }
 
 func (r *InMemoryTaskRepository) Update(task *models.Task) error {
+	return r.update(task, nil)
+}
+
+func (r *InMemoryTaskRepository) update(task *models.Task, saved savedTasks) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.tasks[task.ID]; !exists {
+	existing, exists := r.tasks[task.ID]
+	if !exists {
 		return errors.New("task not found")
 	}
 
-	r.tasks[task.ID] = task
+	saved.save(r.tasks, task.ID)
+	stored := cloneTask(task)
+	stored.Rank = existing.Rank
+	r.tasks[task.ID] = stored
+	r.index.Add(task.ID, taskSearchFields(task)...)
 	return nil
 }
 
 func (r *InMemoryTaskRepository) Delete(id string) error {
+	return r.delete(id, nil)
+}
+
+func (r *InMemoryTaskRepository) delete(id string, saved savedTasks) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -61,18 +126,141 @@ func (r *InMemoryTaskRepository) Delete(id string) error {
 		return errors.New("task not found")
 	}
 
+	saved.save(r.tasks, id)
 	delete(r.tasks, id)
+	r.index.Remove(id)
+	return nil
+}
+
+func (r *InMemoryTaskRepository) RemoveLabel(labelID string) error {
+	return r.removeLabel(labelID, nil)
+}
+
+func (r *InMemoryTaskRepository) removeLabel(labelID string, saved savedTasks) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, task := range r.tasks {
+		if !slices.Contains(task.LabelIDs, labelID) {
+			continue
+		}
+		saved.save(r.tasks, id)
+		task.LabelIDs = slices.DeleteFunc(task.LabelIDs, func(id string) bool { return id == labelID })
+	}
+	return nil
+}
+
+func (r *InMemoryTaskRepository) RemoveCustomField(fieldID string) error {
+	return r.removeCustomField(fieldID, nil)
+}
+
+func (r *InMemoryTaskRepository) removeCustomField(fieldID string, saved savedTasks) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, task := range r.tasks {
+		if _, ok := task.CustomFields[fieldID]; !ok {
+			continue
+		}
+		saved.save(r.tasks, id)
+		delete(task.CustomFields, fieldID)
+	}
 	return nil
 }
 
+func (r *InMemoryTaskRepository) RemoveTeam(teamID string) error {
+	return r.removeTeam(teamID, nil)
+}
+
+func (r *InMemoryTaskRepository) removeTeam(teamID string, saved savedTasks) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, task := range r.tasks {
+		if task.TeamID == teamID {
+			saved.save(r.tasks, id)
+			task.TeamID = ""
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryTaskRepository) LastRank(projectID string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	last := ""
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && task.ProjectID == projectID && task.Rank > last {
+			last = task.Rank
+		}
+	}
+	return last, nil
+}
+
+func (r *InMemoryTaskRepository) AdjacentRank(projectID, rank string, before bool) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	adjacent := ""
+	for _, task := range r.tasks {
+		if task.DeletedAt != nil || task.ProjectID != projectID || task.Rank == "" {
+			continue
+		}
+		if before && task.Rank < rank && task.Rank > adjacent {
+			adjacent = task.Rank
+		}
+		if !before && task.Rank > rank && (adjacent == "" || task.Rank < adjacent) {
+			adjacent = task.Rank
+		}
+	}
+	return adjacent, nil
+}
+
+func (r *InMemoryTaskRepository) SetRank(taskID, expected, rank string) (bool, error) {
+	return r.setRank(taskID, expected, rank, nil)
+}
+
+func (r *InMemoryTaskRepository) setRank(taskID, expected, rank string, saved savedTasks) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, exists := r.tasks[taskID]
+	if !exists || task.Rank != expected {
+		return false, nil
+	}
+	saved.save(r.tasks, taskID)
+	task.Rank = rank
+	return true, nil
+}
+
+func (r *InMemoryTaskRepository) SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error) {
+	return r.setPosition(taskID, expected, status, rank, nil)
+}
+
+func (r *InMemoryTaskRepository) setPosition(taskID, expected string, status models.TaskStatus, rank string, saved savedTasks) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, exists := r.tasks[taskID]
+	if !exists || task.Rank != expected {
+		return false, nil
+	}
+	saved.save(r.tasks, taskID)
+	task.Status = status
+	task.Rank = rank
+	task.UpdatedAt = time.Now()
+	return true, nil
+}
+
 func (r *InMemoryTaskRepository) ListByProject(projectID string) ([]*models.Task, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	tasks := make([]*models.Task, 0)
 	for _, task := range r.tasks {
-		if task.ProjectID == projectID {
-			tasks = append(tasks, task)
+		if task.DeletedAt == nil && task.ProjectID == projectID {
+			tasks = append(tasks, cloneTask(task))
 		}
 	}
 
@@ -85,8 +273,8 @@ func (r *InMemoryTaskRepository) ListByAssignee(assigneeID string) ([]*models.Ta
 
 	tasks := make([]*models.Task, 0)
 	for _, task := range r.tasks {
-		if task.AssigneeID == assigneeID {
-			tasks = append(tasks, task)
+		if task.DeletedAt == nil && task.AssigneeID == assigneeID {
+			tasks = append(tasks, cloneTask(task))
 		}
 	}
 
@@ -99,8 +287,22 @@ func (r *InMemoryTaskRepository) ListBySprint(sprintID string) ([]*models.Task,
 
 	tasks := make([]*models.Task, 0)
 	for _, task := range r.tasks {
-		if task.SprintID != nil && *task.SprintID == sprintID {
-			tasks = append(tasks, task)
+		if task.DeletedAt == nil && task.SprintID != nil && *task.SprintID == sprintID {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+
+	return tasks, nil
+}
+
+func (r *InMemoryTaskRepository) ListByParent(parentID string) ([]*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tasks := make([]*models.Task, 0)
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && task.ParentID == parentID {
+			tasks = append(tasks, cloneTask(task))
 		}
 	}
 
@@ -113,10 +315,246 @@ func (r *InMemoryTaskRepository) ListBacklog(projectID string) ([]*models.Task,
 
 	tasks := make([]*models.Task, 0)
 	for _, task := range r.tasks {
-		if task.ProjectID == projectID && task.SprintID == nil {
-			tasks = append(tasks, task)
+		if task.DeletedAt == nil && task.ProjectID == projectID && task.SprintID == nil {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+
+	return tasks, nil
+}
+
+func (r *InMemoryTaskRepository) ListByQuery(q *query.Query) ([]*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	match := query.Predicate(q.Where)
+	tasks := make([]*models.Task, 0)
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && match(task) {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+
+	query.Sort(tasks, q.OrderBy)
+	return tasks, nil
+}
+
+func (r *InMemoryTaskRepository) SearchText(text string, projectIDs []string) ([]textsearch.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := make([]textsearch.Match, 0)
+	for _, match := range r.index.Search(text) {
+		task, exists := r.tasks[match.ID]
+		if !exists || task.DeletedAt != nil || (projectIDs != nil && !slices.Contains(projectIDs, task.ProjectID)) {
+			continue
 		}
+		matches = append(matches, match)
 	}
+	return matches, nil
+}
 
+func (r *InMemoryTaskRepository) GetTrashed(id string) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, exists := r.tasks[id]
+	if !exists || task.DeletedAt == nil {
+		return nil, errors.New("task not found")
+	}
+	return cloneTask(task), nil
+}
+
+func (r *InMemoryTaskRepository) ListTrash(projectID string) ([]*models.Task, error) {
+	return r.listTrashed(func(task *models.Task) bool { return task.ProjectID == projectID })
+}
+
+func (r *InMemoryTaskRepository) ListTrashedBefore(cutoff time.Time) ([]*models.Task, error) {
+	return r.listTrashed(func(task *models.Task) bool { return task.DeletedAt.Before(cutoff) })
+}
+
+// listTrashed returns the trashed tasks matching keep, most recently deleted
+// first.
+func (r *InMemoryTaskRepository) listTrashed(keep func(task *models.Task) bool) ([]*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tasks := make([]*models.Task, 0)
+	for _, task := range r.tasks {
+		if task.DeletedAt != nil && keep(task) {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *models.Task) int { return b.DeletedAt.Compare(*a.DeletedAt) })
 	return tasks, nil
 }
+
+// WithTransaction runs fn against the repository and, if it fails, puts back
+// the tasks fn wrote as they were before. Transactions run one at a time;
+// writes made outside them are kept, but unlike in Mongo they are not
+// isolated from a running transaction.
+func (r *InMemoryTaskRepository) WithTransaction(fn func(repository services.TaskRepository) error) error {
+	r.tx.Lock()
+	defer r.tx.Unlock()
+
+	tx := &inMemoryTaskTransaction{InMemoryTaskRepository: r, saved: make(savedTasks)}
+	if err := fn(tx); err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for id, task := range tx.saved {
+			if task == nil {
+				delete(r.tasks, id)
+				r.index.Remove(id)
+				continue
+			}
+			r.tasks[id] = task
+			r.index.Add(id, taskSearchFields(task)...)
+		}
+		return err
+	}
+	return nil
+}
+
+// savedTasks holds the tasks a transaction wrote as they were before its
+// first write to them, nil for the ones it created. Writes outside a
+// transaction pass a nil savedTasks and save nothing.
+type savedTasks map[string]*models.Task
+
+// save keeps the task with id as it is in tasks, unless it is already kept.
+// Callers hold the repository's write lock.
+func (s savedTasks) save(tasks map[string]*models.Task, id string) {
+	if s == nil {
+		return
+	}
+	if _, ok := s[id]; ok {
+		return
+	}
+	if task, exists := tasks[id]; exists {
+		s[id] = cloneTask(task)
+	} else {
+		s[id] = nil
+	}
+}
+
+// inMemoryTaskTransaction is the repository as seen by a transaction, saving
+// every task before writing it so that a rollback only puts back those.
+type inMemoryTaskTransaction struct {
+	*InMemoryTaskRepository
+	saved savedTasks
+}
+
+func (t *inMemoryTaskTransaction) Create(task *models.Task) error {
+	return t.create(task, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) Update(task *models.Task) error {
+	return t.update(task, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) Delete(id string) error {
+	return t.delete(id, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) RemoveLabel(labelID string) error {
+	return t.removeLabel(labelID, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) RemoveCustomField(fieldID string) error {
+	return t.removeCustomField(fieldID, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) RemoveTeam(teamID string) error {
+	return t.removeTeam(teamID, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) SetRank(taskID, expected, rank string) (bool, error) {
+	return t.setRank(taskID, expected, rank, t.saved)
+}
+
+func (t *inMemoryTaskTransaction) SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error) {
+	return t.setPosition(taskID, expected, status, rank, t.saved)
+}
+
+// WithTransaction joins the running transaction.
+func (t *inMemoryTaskTransaction) WithTransaction(fn func(repository services.TaskRepository) error) error {
+	return fn(t)
+}
+
+// taskSearchFields weights title matches above description matches, mirroring
+// the weights of the Mongo text index.
+func taskSearchFields(task *models.Task) []textsearch.Field {
+	return []textsearch.Field{
+		{Text: task.Title, Weight: 10},
+		{Text: task.Description, Weight: 5},
+	}
+}
+
+// cloneTask copies a task on its way in and out of the repository, so callers
+// never share state with the stored task, as with a real database.
+func cloneTask(task *models.Task) *models.Task {
+	c := *task
+	c.PreviousKeys = slices.Clone(task.PreviousKeys)
+	c.LabelIDs = slices.Clone(task.LabelIDs)
+	c.Links = slices.Clone(task.Links)
+	c.Checklist = slices.Clone(task.Checklist)
+	for i, item := range c.Checklist {
+		if item.CheckedAt != nil {
+			checked := *item.CheckedAt
+			c.Checklist[i].CheckedAt = &checked
+		}
+	}
+	c.CustomFields = cloneCustomFields(task.CustomFields)
+	if task.ChecklistProgress != nil {
+		progress := *task.ChecklistProgress
+		c.ChecklistProgress = &progress
+	}
+	if task.SprintID != nil {
+		sprintID := *task.SprintID
+		c.SprintID = &sprintID
+	}
+	if task.StoryPoints != nil {
+		points := *task.StoryPoints
+		c.StoryPoints = &points
+	}
+	if task.DueDate != nil {
+		due := *task.DueDate
+		c.DueDate = &due
+	}
+	if task.OriginalEstimateMinutes != nil {
+		original := *task.OriginalEstimateMinutes
+		c.OriginalEstimateMinutes = &original
+	}
+	if task.RemainingEstimateMinutes != nil {
+		remaining := *task.RemainingEstimateMinutes
+		c.RemainingEstimateMinutes = &remaining
+	}
+	if task.DeletedAt != nil {
+		deleted := *task.DeletedAt
+		c.DeletedAt = &deleted
+	}
+	c.Progress = nil
+	return &c
+}
+
+// cloneCustomFields copies custom field values down to their options and
+// pointers.
+func cloneCustomFields(fields map[string]models.CustomFieldValue) map[string]models.CustomFieldValue {
+	if fields == nil {
+		return nil
+	}
+	c := make(map[string]models.CustomFieldValue, len(fields))
+	for id, value := range fields {
+		if value.Number != nil {
+			number := *value.Number
+			value.Number = &number
+		}
+		if value.Date != nil {
+			date := *value.Date
+			value.Date = &date
+		}
+		value.Options = slices.Clone(value.Options)
+		c[id] = value
+	}
+	return c
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryTaskTemplateRepository struct {
+	templates map[string]*models.TaskTemplate
+	mu        sync.RWMutex
+}
+
+func NewInMemoryTaskTemplateRepository() *InMemoryTaskTemplateRepository {
+	return &InMemoryTaskTemplateRepository{
+		templates: make(map[string]*models.TaskTemplate),
+	}
+}
+
+func (r *InMemoryTaskTemplateRepository) Create(template *models.TaskTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.ID]; exists {
+		return errors.New("task template already exists")
+	}
+
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *InMemoryTaskTemplateRepository) GetByID(id string) (*models.TaskTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, exists := r.templates[id]
+	if !exists {
+		return nil, errors.New("task template not found")
+	}
+	return template, nil
+}
+
+func (r *InMemoryTaskTemplateRepository) Update(template *models.TaskTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.ID]; !exists {
+		return errors.New("task template not found")
+	}
+
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *InMemoryTaskTemplateRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[id]; !exists {
+		return errors.New("task template not found")
+	}
+
+	delete(r.templates, id)
+	return nil
+}
+
+func (r *InMemoryTaskTemplateRepository) List() ([]*models.TaskTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]*models.TaskTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		templates = append(templates, template)
+	}
+	slices.SortFunc(templates, func(a, b *models.TaskTemplate) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return templates, nil
+}
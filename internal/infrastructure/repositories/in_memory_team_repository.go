@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryTeamRepository struct {
+	teams map[string]*models.Team
+	mu    sync.RWMutex
+}
+
+func NewInMemoryTeamRepository() *InMemoryTeamRepository {
+	return &InMemoryTeamRepository{
+		teams: make(map[string]*models.Team),
+	}
+}
+
+func (r *InMemoryTeamRepository) Create(team *models.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.teams[team.ID]; exists {
+		return errors.New("team already exists")
+	}
+
+	r.teams[team.ID] = cloneTeam(team)
+	return nil
+}
+
+func (r *InMemoryTeamRepository) GetByID(id string) (*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	team, exists := r.teams[id]
+	if !exists {
+		return nil, errors.New("team not found")
+	}
+	return cloneTeam(team), nil
+}
+
+func (r *InMemoryTeamRepository) Update(team *models.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.teams[team.ID]; !exists {
+		return errors.New("team not found")
+	}
+
+	r.teams[team.ID] = cloneTeam(team)
+	return nil
+}
+
+func (r *InMemoryTeamRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.teams[id]; !exists {
+		return errors.New("team not found")
+	}
+
+	delete(r.teams, id)
+	return nil
+}
+
+func (r *InMemoryTeamRepository) List() ([]*models.Team, error) {
+	return r.list(func(*models.Team) bool { return true })
+}
+
+func (r *InMemoryTeamRepository) ListByMember(userID string) ([]*models.Team, error) {
+	return r.list(func(team *models.Team) bool { return slices.Contains(team.MemberIDs, userID) })
+}
+
+// list returns the teams matching keep, by name.
+func (r *InMemoryTeamRepository) list(keep func(team *models.Team) bool) ([]*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	teams := make([]*models.Team, 0)
+	for _, team := range r.teams {
+		if keep(team) {
+			teams = append(teams, cloneTeam(team))
+		}
+	}
+	slices.SortFunc(teams, func(a, b *models.Team) int { return strings.Compare(a.Name, b.Name) })
+	return teams, nil
+}
+
+func cloneTeam(team *models.Team) *models.Team {
+	c := *team
+	c.MemberIDs = slices.Clone(team.MemberIDs)
+	return &c
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"sync"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryTimerRepository struct {
+	timers map[string]*models.Timer
+	mu     sync.Mutex
+}
+
+func NewInMemoryTimerRepository() *InMemoryTimerRepository {
+	return &InMemoryTimerRepository{
+		timers: make(map[string]*models.Timer),
+	}
+}
+
+func (r *InMemoryTimerRepository) Start(timer *models.Timer) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, running := r.timers[timer.UserID]; running {
+		return false, nil
+	}
+	r.timers[timer.UserID] = timer
+	return true, nil
+}
+
+func (r *InMemoryTimerRepository) Get(userID string) (*models.Timer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.timers[userID], nil
+}
+
+func (r *InMemoryTimerRepository) Stop(userID string) (*models.Timer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timer := r.timers[userID]
+	delete(r.timers, userID)
+	return timer, nil
+}
@@ -87,3 +87,16 @@ func (r *InMemoryUserRepository) List() ([]*models.User, error) {
 	}
 	return users, nil
 }
+
+func (r *InMemoryUserRepository) ListByOrganization(organizationID string) ([]*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*models.User, 0)
+	for _, user := range r.users {
+		if user.OrganizationID == organizationID {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
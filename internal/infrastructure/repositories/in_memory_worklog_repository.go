@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+)
+
+type InMemoryWorklogRepository struct {
+	worklogs map[string]*models.Worklog
+	mu       sync.RWMutex
+}
+
+func NewInMemoryWorklogRepository() *InMemoryWorklogRepository {
+	return &InMemoryWorklogRepository{
+		worklogs: make(map[string]*models.Worklog),
+	}
+}
+
+func (r *InMemoryWorklogRepository) Create(worklog *models.Worklog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.worklogs[worklog.ID]; exists {
+		return errors.New("worklog already exists")
+	}
+
+	r.worklogs[worklog.ID] = worklog
+	return nil
+}
+
+func (r *InMemoryWorklogRepository) GetByID(id string) (*models.Worklog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	worklog, exists := r.worklogs[id]
+	if !exists {
+		return nil, errors.New("worklog not found")
+	}
+	return worklog, nil
+}
+
+func (r *InMemoryWorklogRepository) Update(worklog *models.Worklog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.worklogs[worklog.ID]; !exists {
+		return errors.New("worklog not found")
+	}
+
+	r.worklogs[worklog.ID] = worklog
+	return nil
+}
+
+func (r *InMemoryWorklogRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.worklogs[id]; !exists {
+		return errors.New("worklog not found")
+	}
+
+	delete(r.worklogs, id)
+	return nil
+}
+
+func (r *InMemoryWorklogRepository) ListByTask(taskID string) ([]*models.Worklog, error) {
+	return r.list(func(worklog *models.Worklog) bool { return worklog.TaskID == taskID })
+}
+
+func (r *InMemoryWorklogRepository) ListByUser(userID string, from, to time.Time) ([]*models.Worklog, error) {
+	return r.list(func(worklog *models.Worklog) bool {
+		return worklog.UserID == userID && !worklog.Start.Before(from) && worklog.Start.Before(to)
+	})
+}
+
+func (r *InMemoryWorklogRepository) ListByProject(projectID string, from, to time.Time) ([]*models.Worklog, error) {
+	return r.list(func(worklog *models.Worklog) bool {
+		return worklog.ProjectID == projectID && !worklog.Start.Before(from) && worklog.Start.Before(to)
+	})
+}
+
+func (r *InMemoryWorklogRepository) list(match func(*models.Worklog) bool) ([]*models.Worklog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	worklogs := make([]*models.Worklog, 0)
+	for _, worklog := range r.worklogs {
+		if match(worklog) {
+			worklogs = append(worklogs, worklog)
+		}
+	}
+	slices.SortFunc(worklogs, func(a, b *models.Worklog) int {
+		return a.Start.Compare(b.Start)
+	})
+	return worklogs, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoActivityRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoActivityRepository(db *mongo.Database) *MongoActivityRepository {
+	return &MongoActivityRepository{
+		collection: db.Collection("activities"),
+	}
+}
+
+// EnsureIndexes creates the indexes the activity queries rely on: a task's
+// history, and the tasks that passed through a sprint.
+func (r *MongoActivityRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "at", Value: 1}}},
+		{Keys: bson.D{{Key: "field", Value: 1}, {Key: "to", Value: 1}}},
+		{Keys: bson.D{{Key: "field", Value: 1}, {Key: "from", Value: 1}}},
+	})
+	return err
+}
+
+func (r *MongoActivityRepository) Create(activity *models.Activity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, activity)
+	return err
+}
+
+func (r *MongoActivityRepository) ListByTasks(taskIDs []string) ([]*models.Activity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activities []*models.Activity
+	if err = cursor.All(ctx, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+func (r *MongoActivityRepository) TaskIDsForSprint(sprintID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"field": "sprint", "to": sprintID},
+		bson.M{"field": "sprint", "from": sprintID},
+	}}
+	values, err := r.collection.Distinct(ctx, "task_id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
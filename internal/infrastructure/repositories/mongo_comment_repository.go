@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoCommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCommentRepository(db *mongo.Database) *MongoCommentRepository {
+	return &MongoCommentRepository{
+		collection: db.Collection("comments"),
+	}
+}
+
+// EnsureIndexes creates the indexes the comment queries rely on.
+func (r *MongoCommentRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "body", Value: "text"}}, Options: options.Index().SetName("comment_text")},
+	})
+	return err
+}
+
+func (r *MongoCommentRepository) Create(comment *models.Comment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, comment)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("comment already exists")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoCommentRepository) GetByID(id string) (*models.Comment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var comment models.Comment
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *MongoCommentRepository) Update(comment *models.Comment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": comment.ID},
+		comment,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
+
+func (r *MongoCommentRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
+
+func (r *MongoCommentRepository) ListByTask(taskID string) ([]*models.Comment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []*models.Comment
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *MongoCommentRepository) ListByIDs(ids []string) ([]*models.Comment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []*models.Comment
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// SearchText joins the matching comments with their tasks, in the same
+// database, to keep only those of tasks in projectIDs.
+func (r *MongoCommentRepository) SearchText(text string, projectIDs []string) ([]textsearch.Match, error) {
+	filter := bson.M{"$text": bson.M{"$search": text}}
+	if projectIDs == nil {
+		return textSearch(r.collection, filter)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "tasks",
+			"localField":   "task_id",
+			"foreignField": "_id",
+			"as":           "task",
+		}}},
+		{{Key: "$match", Value: bson.M{"task.project_id": bson.M{"$in": projectIDs}}}},
+		{{Key: "$project", Value: bson.M{"_id": 1, "score": bson.M{"$meta": "textScore"}}}},
+		{{Key: "$sort", Value: bson.M{"score": -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID    string  `bson:"_id"`
+		Score float64 `bson:"score"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	matches := make([]textsearch.Match, len(results))
+	for i, result := range results {
+		matches[i] = textsearch.Match{ID: result.ID, Score: result.Score}
+	}
+	return matches, nil
+}
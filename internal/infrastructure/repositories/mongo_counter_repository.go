@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCounterRepository hands out sequence numbers from one counter document
+// per sequence, incremented atomically with findOneAndUpdate.
+type MongoCounterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCounterRepository(db *mongo.Database) *MongoCounterRepository {
+	return &MongoCounterRepository{
+		collection: db.Collection("counters"),
+	}
+}
+
+func (r *MongoCounterRepository) Next(name string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var counter struct {
+		Seq int `bson:"seq"`
+	}
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		opts,
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
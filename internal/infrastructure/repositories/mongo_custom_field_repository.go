@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoCustomFieldRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCustomFieldRepository(db *mongo.Database) *MongoCustomFieldRepository {
+	return &MongoCustomFieldRepository{
+		collection: db.Collection("custom_fields"),
+	}
+}
+
+// EnsureIndexes creates the indexes the custom field queries rely on. Field
+// names are unique within a project, ignoring case.
+func (r *MongoCustomFieldRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	})
+	return err
+}
+
+func (r *MongoCustomFieldRepository) Create(field *models.CustomField) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, field)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("custom field already exists")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoCustomFieldRepository) GetByID(id string) (*models.CustomField, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var field models.CustomField
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&field)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("custom field not found")
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *MongoCustomFieldRepository) Update(field *models.CustomField) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": field.ID},
+		field,
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("custom field already exists")
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("custom field not found")
+	}
+	return nil
+}
+
+func (r *MongoCustomFieldRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("custom field not found")
+	}
+	return nil
+}
+
+func (r *MongoCustomFieldRepository) ListByProject(projectID string) ([]*models.CustomField, error) {
+	return r.find(bson.M{"project_id": projectID})
+}
+
+func (r *MongoCustomFieldRepository) find(filter bson.M) ([]*models.CustomField, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var fields []*models.CustomField
+	if err = cursor.All(ctx, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
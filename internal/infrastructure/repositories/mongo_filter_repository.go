@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoFilterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoFilterRepository(db *mongo.Database) *MongoFilterRepository {
+	return &MongoFilterRepository{
+		collection: db.Collection("saved_filters"),
+	}
+}
+
+func (r *MongoFilterRepository) Create(filter *models.SavedFilter) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, filter)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("filter already exists")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoFilterRepository) GetByID(id string) (*models.SavedFilter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var filter models.SavedFilter
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&filter)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("filter not found")
+		}
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func (r *MongoFilterRepository) Update(filter *models.SavedFilter) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": filter.ID},
+		filter,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("filter not found")
+	}
+	return nil
+}
+
+func (r *MongoFilterRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("filter not found")
+	}
+	return nil
+}
+
+func (r *MongoFilterRepository) ListByOwner(ownerID string) ([]*models.SavedFilter, error) {
+	return r.find(bson.M{"owner_id": ownerID})
+}
+
+func (r *MongoFilterRepository) ListByProjects(projectIDs []string) ([]*models.SavedFilter, error) {
+	return r.find(bson.M{"project_id": bson.M{"$in": projectIDs}})
+}
+
+func (r *MongoFilterRepository) find(filter bson.M) ([]*models.SavedFilter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var filters []*models.SavedFilter
+	if err = cursor.All(ctx, &filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoLabelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLabelRepository(db *mongo.Database) *MongoLabelRepository {
+	return &MongoLabelRepository{
+		collection: db.Collection("labels"),
+	}
+}
+
+// EnsureIndexes creates the indexes the label queries rely on. Label names
+// are unique within a project, ignoring case.
+func (r *MongoLabelRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	})
+	return err
+}
+
+func (r *MongoLabelRepository) Create(label *models.Label) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, label)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("label already exists")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoLabelRepository) GetByID(id string) (*models.Label, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var label models.Label
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&label)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("label not found")
+		}
+		return nil, err
+	}
+	return &label, nil
+}
+
+func (r *MongoLabelRepository) Update(label *models.Label) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": label.ID},
+		label,
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("label already exists")
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("label not found")
+	}
+	return nil
+}
+
+func (r *MongoLabelRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("label not found")
+	}
+	return nil
+}
+
+func (r *MongoLabelRepository) ListByProject(projectID string) ([]*models.Label, error) {
+	return r.find(bson.M{"project_id": projectID})
+}
+
+func (r *MongoLabelRepository) ListByName(name string) ([]*models.Label, error) {
+	pattern := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(name) + "$", Options: "i"}
+	return r.find(bson.M{"name": pattern})
+}
+
+func (r *MongoLabelRepository) find(filter bson.M) ([]*models.Label, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var labels []*models.Label
+	if err = cursor.All(ctx, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
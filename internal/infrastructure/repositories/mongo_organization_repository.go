@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoOrganizationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoOrganizationRepository(db *mongo.Database) *MongoOrganizationRepository {
+	return &MongoOrganizationRepository{
+		collection: db.Collection("organizations"),
+	}
+}
+
+func (r *MongoOrganizationRepository) Create(organization *models.Organization) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, organization)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("organization already exists")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoOrganizationRepository) GetByID(id string) (*models.Organization, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var organization models.Organization
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&organization)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("organization not found")
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *MongoOrganizationRepository) List() ([]*models.Organization, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	organizations := make([]*models.Organization, 0)
+	if err = cursor.All(ctx, &organizations); err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
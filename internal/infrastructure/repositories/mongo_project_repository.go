@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoProjectRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoProjectRepository(db *mongo.Database) *MongoProjectRepository {
+	return &MongoProjectRepository{
+		collection: db.Collection("projects"),
+	}
+}
+
+// EnsureIndexes creates the indexes the project queries rely on.
+func (r *MongoProjectRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "member_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "team_ids", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	return err
+}
+
+func (r *MongoProjectRepository) Create(project *models.Project) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, project)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("project key already in use")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoProjectRepository) GetByID(id string) (*models.Project, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var project models.Project
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *MongoProjectRepository) GetByKey(key string) (*models.Project, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var project models.Project
+	err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *MongoProjectRepository) Update(project *models.Project) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": project.ID},
+		project,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("project not found")
+	}
+	return nil
+}
+
+func (r *MongoProjectRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("project not found")
+	}
+	return nil
+}
+
+func (r *MongoProjectRepository) List() ([]*models.Project, error) {
+	return r.find(bson.M{"deleted_at": nil})
+}
+
+func (r *MongoProjectRepository) ListByMember(userID string, teamIDs []string) ([]*models.Project, error) {
+	if len(teamIDs) == 0 {
+		return r.find(bson.M{"member_ids": userID, "deleted_at": nil})
+	}
+	return r.find(bson.M{
+		"$or":        bson.A{bson.M{"member_ids": userID}, bson.M{"team_ids": bson.M{"$in": teamIDs}}},
+		"deleted_at": nil,
+	})
+}
+
+func (r *MongoProjectRepository) GetTrashed(id string) (*models.Project, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var project models.Project
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *MongoProjectRepository) ListTrash() ([]*models.Project, error) {
+	return r.find(bson.M{"deleted_at": bson.M{"$ne": nil}})
+}
+
+func (r *MongoProjectRepository) ListTrashedBefore(cutoff time.Time) ([]*models.Project, error) {
+	return r.find(bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": cutoff}})
+}
+
+func (r *MongoProjectRepository) find(filter bson.M) ([]*models.Project, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var projects []*models.Project
+	if err = cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MongoRecurringTaskRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRecurringTaskRepository(db *mongo.Database) *MongoRecurringTaskRepository {
+	return &MongoRecurringTaskRepository{
+		collection: db.Collection("recurring_tasks"),
+	}
+}
+
+// EnsureIndexes creates the indexes the recurring task queries rely on,
+// including the scheduler's lookup of due runs.
+func (r *MongoRecurringTaskRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "project_id", Value: 1}}},
+		{Keys: bson.D{{Key: "paused", Value: 1}, {Key: "next_run_at", Value: 1}}},
+	})
+	return err
+}
+
+func (r *MongoRecurringTaskRepository) Create(recurring *models.RecurringTask) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, recurring)
+	return err
+}
+
+func (r *MongoRecurringTaskRepository) GetByID(id string) (*models.RecurringTask, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var recurring models.RecurringTask
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&recurring)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("recurring task not found")
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *MongoRecurringTaskRepository) Update(recurring *models.RecurringTask) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": recurring.ID}, recurring)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("recurring task not found")
+	}
+	return nil
+}
+
+func (r *MongoRecurringTaskRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("recurring task not found")
+	}
+	return nil
+}
+
+func (r *MongoRecurringTaskRepository) ListByProject(projectID string) ([]*models.RecurringTask, error) {
+	return r.find(bson.M{"project_id": projectID})
+}
+
+func (r *MongoRecurringTaskRepository) ListDue(now time.Time) ([]*models.RecurringTask, error) {
+	return r.find(bson.M{"paused": false, "next_run_at": bson.M{"$lte": now}})
+}
+
+// ClaimRun only matches while next_run_at still holds the expected run, so
+// of several concurrent claims exactly one modifies the document.
+func (r *MongoRecurringTaskRepository) ClaimRun(id string, expected time.Time, next *time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"last_run_at": expected, "updated_at": time.Now()}}
+	if next != nil {
+		update["$set"].(bson.M)["next_run_at"] = *next
+	} else {
+		update["$unset"] = bson.M{"next_run_at": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "paused": false, "next_run_at": expected}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MongoRecurringTaskRepository) find(filter bson.M) ([]*models.RecurringTask, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var recurring []*models.RecurringTask
+	if err = cursor.All(ctx, &recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoSnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSnapshotRepository(db *mongo.Database) *MongoSnapshotRepository {
+	return &MongoSnapshotRepository{
+		collection: db.Collection("sprint_snapshots"),
+	}
+}
+
+// EnsureIndexes creates the indexes the snapshot queries rely on. There is
+// one snapshot per sprint and day.
+func (r *MongoSnapshotRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "sprint_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *MongoSnapshotRepository) Upsert(snapshot *models.SprintSnapshot) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"sprint_id": snapshot.SprintID, "date": snapshot.Date},
+		snapshot,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *MongoSnapshotRepository) ListBySprint(sprintID string) ([]*models.SprintSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"sprint_id": sprintID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []*models.SprintSnapshot
+	if err = cursor.All(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
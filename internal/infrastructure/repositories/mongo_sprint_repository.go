@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoSprintRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSprintRepository(db *mongo.Database) *MongoSprintRepository {
+	return &MongoSprintRepository{
+		collection: db.Collection("sprints"),
+	}
+}
+
+// EnsureIndexes creates the indexes the sprint queries rely on.
+func (r *MongoSprintRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "start_date", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+	})
+	return err
+}
+
+func (r *MongoSprintRepository) Create(sprint *models.Sprint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, sprint)
+	return err
+}
+
+func (r *MongoSprintRepository) GetByID(id string) (*models.Sprint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sprint models.Sprint
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&sprint)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("sprint not found")
+		}
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *MongoSprintRepository) Update(sprint *models.Sprint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": sprint.ID}, sprint)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("sprint not found")
+	}
+	return nil
+}
+
+func (r *MongoSprintRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("sprint not found")
+	}
+	return nil
+}
+
+func (r *MongoSprintRepository) ListByProject(projectID string) ([]*models.Sprint, error) {
+	return r.find(bson.M{"project_id": projectID})
+}
+
+func (r *MongoSprintRepository) ListByStatus(status models.SprintStatus) ([]*models.Sprint, error) {
+	return r.find(bson.M{"status": status})
+}
+
+func (r *MongoSprintRepository) find(filter bson.M) ([]*models.Sprint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_date", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sprints []*models.Sprint
+	if err = cursor.All(ctx, &sprints); err != nil {
+		return nil, err
+	}
+	return sprints, nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"regexp"
+
+	"go-project-manager-backend/internal/domain/query"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// compileFilter translates a query expression into a MongoDB filter document.
+func compileFilter(expr query.Expr) bson.M {
+	switch e := expr.(type) {
+	case nil:
+		return bson.M{}
+	case query.And:
+		return bson.M{"$and": bson.A{compileFilter(e.Left), compileFilter(e.Right)}}
+	case query.Or:
+		return bson.M{"$or": bson.A{compileFilter(e.Left), compileFilter(e.Right)}}
+	case query.Not:
+		return bson.M{"$nor": bson.A{compileFilter(e.Expr)}}
+	case query.Comparison:
+		return compileComparison(e)
+	}
+	return bson.M{"_id": bson.M{"$exists": false}}
+}
+
+func compileComparison(c query.Comparison) bson.M {
+	key := c.Field.Key
+
+	var value any
+	if len(c.Values) > 0 {
+		value = literal(c.Field, c.Values[0])
+	}
+
+	switch c.Op {
+	case query.OpEqual:
+		return bson.M{key: value}
+	case query.OpNotEqual:
+		return bson.M{key: bson.M{"$ne": value}}
+	case query.OpGreater:
+		return bson.M{key: bson.M{"$gt": value}}
+	case query.OpGreaterOrEqual:
+		return bson.M{key: bson.M{"$gte": value}}
+	case query.OpLess:
+		return bson.M{key: bson.M{"$lt": value}}
+	case query.OpLessOrEqual:
+		return bson.M{key: bson.M{"$lte": value}}
+	case query.OpContains:
+		return bson.M{key: primitive.Regex{Pattern: regexp.QuoteMeta(c.Values[0].Text), Options: "i"}}
+	case query.OpNotContains:
+		return bson.M{key: bson.M{"$not": primitive.Regex{Pattern: regexp.QuoteMeta(c.Values[0].Text), Options: "i"}}}
+	case query.OpIn:
+		return bson.M{key: bson.M{"$in": literals(c.Field, c.Values)}}
+	case query.OpNotIn:
+		return bson.M{key: bson.M{"$nin": literals(c.Field, c.Values)}}
+	case query.OpEmpty:
+		return bson.M{key: bson.M{"$in": emptyValues(c.Field)}}
+	case query.OpNotEmpty:
+		return bson.M{key: bson.M{"$nin": emptyValues(c.Field)}}
+	}
+	return bson.M{"_id": bson.M{"$exists": false}}
+}
+
+func literal(field query.Field, v query.Value) any {
+	switch field.Kind {
+	case query.KindTime:
+		return v.Time
+	case query.KindNumber:
+		return v.Number
+	case query.KindDecimal:
+		return v.Decimal
+	}
+	return v.Text
+}
+
+func literals(field query.Field, values []query.Value) bson.A {
+	result := make(bson.A, len(values))
+	for i, v := range values {
+		result[i] = literal(field, v)
+	}
+	return result
+}
+
+// emptyValues lists what an unset field of the given kind may hold: missing
+// or null, plus the empty string or empty array where those apply.
+func emptyValues(field query.Field) bson.A {
+	switch field.Kind {
+	case query.KindString, query.KindText:
+		return bson.A{nil, ""}
+	case query.KindList:
+		return bson.A{nil, bson.A{}}
+	}
+	return bson.A{nil}
+}
+
+// compileSort translates ORDER BY terms into a MongoDB sort document.
+func compileSort(terms []query.OrderTerm) bson.D {
+	sort := bson.D{}
+	for _, term := range terms {
+		direction := 1
+		if term.Descending {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: term.Field.Key, Value: direction})
+	}
+	return sort
+}
@@ -6,13 +6,19 @@ import (
 	"time"
 
 	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/query"
+	"go-project-manager-backend/internal/domain/services"
+	"go-project-manager-backend/internal/domain/textsearch"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoTaskRepository struct {
 	collection *mongo.Collection
+	// session is set on the copies of the repository bound to a transaction.
+	session mongo.SessionContext
 }
 
 func NewMongoTaskRepository(db *mongo.Database) *MongoTaskRepository {
@@ -21,8 +27,69 @@ func NewMongoTaskRepository(db *mongo.Database) *MongoTaskRepository {
 	}
 }
 
+// context bounds a call to the database, within the repository's
+// transaction if it has one.
+func (r *MongoTaskRepository) context() (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if r.session != nil {
+		parent = r.session
+	}
+	return context.WithTimeout(parent, 5*time.Second)
+}
+
+// WithTransaction runs fn against a copy of the repository bound to a
+// transaction, committed if fn succeeds and aborted otherwise. The driver
+// retries fn on transient errors, so it must be safe to run again.
+// Transactions need MongoDB to run as a replica set.
+func (r *MongoTaskRepository) WithTransaction(fn func(repository services.TaskRepository) error) error {
+	if r.session != nil {
+		return fn(r)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(&MongoTaskRepository{collection: r.collection, session: sc})
+	})
+	return err
+}
+
+// EnsureIndexes creates the indexes the task queries rely on.
+func (r *MongoTaskRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "priority", Value: -1}}},
+		{Keys: bson.D{{Key: "assignee_id", Value: 1}}},
+		{Keys: bson.D{{Key: "sprint_id", Value: 1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+		{Keys: bson.D{{Key: "due_date", Value: 1}}},
+		{Keys: bson.D{{Key: "label_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "previous_keys", Value: 1}}},
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "rank", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("task_text").
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "description", Value: 5}}),
+		},
+	})
+	return err
+}
+
 func (r *MongoTaskRepository) Create(task *models.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
 	task.CreatedAt = time.Now()
@@ -39,11 +106,11 @@ func (r *MongoTaskRepository) Create(task *models.Task) error {
 }
 
 func (r *MongoTaskRepository) GetByID(id string) (*models.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
 	var task models.Task
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&task)
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&task)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.New("task not found")
@@ -53,16 +120,58 @@ func (r *MongoTaskRepository) GetByID(id string) (*models.Task, error) {
 	return &task, nil
 }
 
+func (r *MongoTaskRepository) GetByKey(key string) (*models.Task, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var task models.Task
+	err := r.collection.FindOne(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"key": key},
+			bson.M{"previous_keys": key},
+		},
+		"deleted_at": nil,
+	}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *MongoTaskRepository) ListByIDs(ids []string) ([]*models.Task, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*models.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Update replaces a task but keeps its stored rank, so that an edit made from
+// a stale copy cannot undo a concurrent reorder; only SetRank changes ranks.
 func (r *MongoTaskRepository) Update(task *models.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
 	task.UpdatedAt = time.Now()
 
-	result, err := r.collection.ReplaceOne(
+	result, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": task.ID},
-		task,
+		mongo.Pipeline{{{Key: "$replaceWith", Value: bson.M{
+			"$mergeObjects": bson.A{bson.M{"$literal": task}, bson.M{"rank": "$rank"}},
+		}}}},
 	)
 	if err != nil {
 		return err
@@ -74,7 +183,7 @@ func (r *MongoTaskRepository) Update(task *models.Task) error {
 }
 
 func (r *MongoTaskRepository) Delete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
@@ -87,11 +196,112 @@ func (r *MongoTaskRepository) Delete(id string) error {
 	return nil
 }
 
+func (r *MongoTaskRepository) RemoveLabel(labelID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"label_ids": labelID},
+		bson.M{"$pull": bson.M{"label_ids": labelID}},
+	)
+	return err
+}
+
+func (r *MongoTaskRepository) RemoveTeam(teamID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"team_id": teamID},
+		bson.M{"$unset": bson.M{"team_id": ""}},
+	)
+	return err
+}
+
+func (r *MongoTaskRepository) RemoveCustomField(fieldID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	key := "custom_fields." + fieldID
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{key: bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{key: ""}},
+	)
+	return err
+}
+
+func (r *MongoTaskRepository) LastRank(projectID string) (string, error) {
+	return r.findRank(bson.M{"project_id": projectID, "rank": bson.M{"$exists": true}, "deleted_at": nil}, -1)
+}
+
+func (r *MongoTaskRepository) AdjacentRank(projectID, rank string, before bool) (string, error) {
+	if before {
+		return r.findRank(bson.M{"project_id": projectID, "rank": bson.M{"$lt": rank}, "deleted_at": nil}, -1)
+	}
+	return r.findRank(bson.M{"project_id": projectID, "rank": bson.M{"$gt": rank}, "deleted_at": nil}, 1)
+}
+
+// findRank returns the first rank matching filter in the given sort direction.
+func (r *MongoTaskRepository) findRank(filter bson.M, direction int) (string, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var task models.Task
+	opts := options.FindOne().SetSort(bson.D{{Key: "rank", Value: direction}}).SetProjection(bson.M{"rank": 1})
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		return "", err
+	}
+	return task.Rank, nil
+}
+
+func (r *MongoTaskRepository) SetRank(taskID, expected, rank string) (bool, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	filter := bson.M{"_id": taskID, "rank": expected}
+	if expected == "" {
+		filter["rank"] = nil
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"rank": rank}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (r *MongoTaskRepository) SetPosition(taskID, expected string, status models.TaskStatus, rank string) (bool, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	filter := bson.M{"_id": taskID, "rank": expected}
+	if expected == "" {
+		filter["rank"] = nil
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"status":     status,
+		"rank":       rank,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
 func (r *MongoTaskRepository) List() ([]*models.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": nil})
 	if err != nil {
 		return nil, err
 	}
@@ -105,10 +315,10 @@ func (r *MongoTaskRepository) List() ([]*models.Task, error) {
 }
 
 func (r *MongoTaskRepository) ListByProject(projectID string) ([]*models.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectID})
+	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectID, "deleted_at": nil})
 	if err != nil {
 		return nil, err
 	}
@@ -122,10 +332,10 @@ func (r *MongoTaskRepository) ListByProject(projectID string) ([]*models.Task, e
 }
 
 func (r *MongoTaskRepository) ListByAssignee(assigneeID string) ([]*models.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{"assignee_id": assigneeID})
+	cursor, err := r.collection.Find(ctx, bson.M{"assignee_id": assigneeID, "deleted_at": nil})
 	if err != nil {
 		return nil, err
 	}
@@ -139,10 +349,27 @@ func (r *MongoTaskRepository) ListByAssignee(assigneeID string) ([]*models.Task,
 }
 
 func (r *MongoTaskRepository) ListBySprint(sprintID string) ([]*models.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{"sprint_id": sprintID})
+	cursor, err := r.collection.Find(ctx, bson.M{"sprint_id": sprintID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*models.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *MongoTaskRepository) ListByParent(parentID string) ([]*models.Task, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"parent_id": parentID, "deleted_at": nil})
 	if err != nil {
 		return nil, err
 	}
@@ -156,12 +383,13 @@ func (r *MongoTaskRepository) ListBySprint(sprintID string) ([]*models.Task, err
 }
 
 func (r *MongoTaskRepository) ListBacklog(projectID string) ([]*models.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := r.context()
 	defer cancel()
 
 	cursor, err := r.collection.Find(ctx, bson.M{
 		"project_id": projectID,
 		"sprint_id":  nil,
+		"deleted_at": nil,
 	})
 	if err != nil {
 		return nil, err
@@ -174,3 +402,76 @@ func (r *MongoTaskRepository) ListBacklog(projectID string) ([]*models.Task, err
 	}
 	return tasks, nil
 }
+
+func (r *MongoTaskRepository) ListByQuery(q *query.Query) ([]*models.Task, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	opts := options.Find()
+	if len(q.OrderBy) > 0 {
+		opts.SetSort(compileSort(q.OrderBy))
+	}
+
+	filter := compileFilter(q.Where)
+	filter["deleted_at"] = nil
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*models.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *MongoTaskRepository) SearchText(text string, projectIDs []string) ([]textsearch.Match, error) {
+	filter := bson.M{"$text": bson.M{"$search": text}, "deleted_at": nil}
+	if projectIDs != nil {
+		filter["project_id"] = bson.M{"$in": projectIDs}
+	}
+	return textSearch(r.collection, filter)
+}
+
+func (r *MongoTaskRepository) GetTrashed(id string) (*models.Task, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var task models.Task
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *MongoTaskRepository) ListTrash(projectID string) ([]*models.Task, error) {
+	return r.findTrashed(bson.M{"project_id": projectID, "deleted_at": bson.M{"$ne": nil}})
+}
+
+func (r *MongoTaskRepository) ListTrashedBefore(cutoff time.Time) ([]*models.Task, error) {
+	return r.findTrashed(bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": cutoff}})
+}
+
+func (r *MongoTaskRepository) findTrashed(filter bson.M) ([]*models.Task, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []*models.Task
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoTeamRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoTeamRepository(db *mongo.Database) *MongoTeamRepository {
+	return &MongoTeamRepository{
+		collection: db.Collection("teams"),
+	}
+}
+
+// EnsureIndexes creates the indexes the team queries rely on.
+func (r *MongoTeamRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "member_ids", Value: 1}},
+	})
+	return err
+}
+
+func (r *MongoTeamRepository) Create(team *models.Team) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, team)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("team already exists")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoTeamRepository) GetByID(id string) (*models.Team, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var team models.Team
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("team not found")
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *MongoTeamRepository) Update(team *models.Team) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": team.ID}, team)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("team not found")
+	}
+	return nil
+}
+
+func (r *MongoTeamRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("team not found")
+	}
+	return nil
+}
+
+func (r *MongoTeamRepository) List() ([]*models.Team, error) {
+	return r.find(bson.M{})
+}
+
+func (r *MongoTeamRepository) ListByMember(userID string) ([]*models.Team, error) {
+	return r.find(bson.M{"member_ids": userID})
+}
+
+func (r *MongoTeamRepository) find(filter bson.M) ([]*models.Team, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	teams := make([]*models.Team, 0)
+	if err = cursor.All(ctx, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-project-manager-backend/internal/domain/textsearch"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// textSearch runs a $text filter and returns the matching IDs ordered by the
+// text score Mongo assigns them.
+func textSearch(collection *mongo.Collection, filter bson.M) ([]textsearch.Match, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1, "score": score}).
+		SetSort(bson.M{"score": score})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID    string  `bson:"_id"`
+		Score float64 `bson:"score"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	matches := make([]textsearch.Match, len(results))
+	for i, result := range results {
+		matches[i] = textsearch.Match{ID: result.ID, Score: result.Score}
+	}
+	return matches, nil
+}
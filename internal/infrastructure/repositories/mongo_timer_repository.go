@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoTimerRepository keys timers by user, so the unique _id index keeps a
+// single timer per user.
+type MongoTimerRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoTimerRepository(db *mongo.Database) *MongoTimerRepository {
+	return &MongoTimerRepository{
+		collection: db.Collection("timers"),
+	}
+}
+
+func (r *MongoTimerRepository) Start(timer *models.Timer) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, timer)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *MongoTimerRepository) Get(userID string) (*models.Timer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var timer models.Timer
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&timer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &timer, nil
+}
+
+func (r *MongoTimerRepository) Stop(userID string) (*models.Timer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var timer models.Timer
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"_id": userID}).Decode(&timer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &timer, nil
+}
@@ -113,3 +113,26 @@ func (r *MongoUserRepository) List() ([]*models.User, error) {
 	}
 	return users, nil
 }
+
+// ListByOrganization returns the users of an organization. The default
+// workspace, "", has the users stored without one.
+func (r *MongoUserRepository) ListByOrganization(organizationID string) ([]*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"organization_id": organizationID}
+	if organizationID == "" {
+		filter = bson.M{"organization_id": nil}
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*models.User
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-project-manager-backend/internal/domain/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoWorklogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoWorklogRepository(db *mongo.Database) *MongoWorklogRepository {
+	return &MongoWorklogRepository{
+		collection: db.Collection("worklogs"),
+	}
+}
+
+// EnsureIndexes creates the indexes the worklog queries rely on: by task, and
+// by user or project over a period.
+func (r *MongoWorklogRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "start", Value: 1}}},
+		{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "start", Value: 1}}},
+	})
+	return err
+}
+
+func (r *MongoWorklogRepository) Create(worklog *models.Worklog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, worklog)
+	return err
+}
+
+func (r *MongoWorklogRepository) GetByID(id string) (*models.Worklog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var worklog models.Worklog
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&worklog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("worklog not found")
+		}
+		return nil, err
+	}
+	return &worklog, nil
+}
+
+func (r *MongoWorklogRepository) Update(worklog *models.Worklog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": worklog.ID}, worklog)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("worklog not found")
+	}
+	return nil
+}
+
+func (r *MongoWorklogRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("worklog not found")
+	}
+	return nil
+}
+
+func (r *MongoWorklogRepository) ListByTask(taskID string) ([]*models.Worklog, error) {
+	return r.find(bson.M{"task_id": taskID})
+}
+
+func (r *MongoWorklogRepository) ListByUser(userID string, from, to time.Time) ([]*models.Worklog, error) {
+	return r.find(bson.M{"user_id": userID, "start": bson.M{"$gte": from, "$lt": to}})
+}
+
+func (r *MongoWorklogRepository) ListByProject(projectID string, from, to time.Time) ([]*models.Worklog, error) {
+	return r.find(bson.M{"project_id": projectID, "start": bson.M{"$gte": from, "$lt": to}})
+}
+
+func (r *MongoWorklogRepository) find(filter bson.M) ([]*models.Worklog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var worklogs []*models.Worklog
+	if err = cursor.All(ctx, &worklogs); err != nil {
+		return nil, err
+	}
+	return worklogs, nil
+}
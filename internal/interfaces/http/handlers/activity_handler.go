@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type ActivityHandler struct {
+	activityService *services.ActivityService
+	projectService  *services.ProjectService
+}
+
+func NewActivityHandler(activityService *services.ActivityService, projectService *services.ProjectService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		projectService:  projectService,
+	}
+}
+
+// TaskHistory returns the recorded changes of a task, oldest first.
+func (h *ActivityHandler) TaskHistory(w http.ResponseWriter, req *http.Request) {
+	task, activities, err := h.activityService.TaskHistory(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), task.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activities)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type BoardHandler struct {
+	boardService   *services.BoardService
+	projectService *services.ProjectService
+}
+
+func NewBoardHandler(boardService *services.BoardService, projectService *services.ProjectService) *BoardHandler {
+	return &BoardHandler{
+		boardService:   boardService,
+		projectService: projectService,
+	}
+}
+
+type ConfigureBoardRequest struct {
+	Columns []models.BoardColumn `json:"columns"`
+}
+
+type MoveCardRequest struct {
+	TaskID           string `json:"task_id"`
+	Column           string `json:"column"`
+	Before           string `json:"before"`
+	After            string `json:"after"`
+	OverrideBlockers bool   `json:"override_blockers"`
+}
+
+// GetBoard returns a project's board, split into swimlanes with
+// ?swimlanes=assignee, priority or epic.
+func (h *BoardHandler) GetBoard(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	grouping := services.SwimlaneGrouping(req.URL.Query().Get("swimlanes"))
+	board, err := h.boardService.GetBoard(projectID, grouping)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
+
+func (h *BoardHandler) ConfigureBoard(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	var boardRequest ConfigureBoardRequest
+	if err := json.NewDecoder(req.Body).Decode(&boardRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.boardService.ConfigureBoard(projectID, boardRequest.Columns)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *BoardHandler) MoveCard(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	var moveRequest MoveCardRequest
+	if err := json.NewDecoder(req.Body).Decode(&moveRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if moveRequest.TaskID == "" || moveRequest.Column == "" {
+		http.Error(w, "Task ID and column required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.boardService.MoveCard(projectID, moveRequest.TaskID, services.CardMove{
+		Column:           moveRequest.Column,
+		BeforeID:         moveRequest.Before,
+		AfterID:          moveRequest.After,
+		OverrideBlockers: moveRequest.OverrideBlockers,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWIPLimitExceeded), errors.Is(err, services.ErrTaskBlocked), errors.Is(err, services.ErrChecklistIncomplete), errors.Is(err, services.ErrRankConflict), errors.Is(err, services.ErrProjectArchived):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type BulkHandler struct {
+	bulkService *services.BulkService
+	taskService *services.TaskService
+}
+
+func NewBulkHandler(bulkService *services.BulkService, taskService *services.TaskService) *BulkHandler {
+	return &BulkHandler{
+		bulkService: bulkService,
+		taskService: taskService,
+	}
+}
+
+// BulkTaskRequest picks tasks with either task_ids, holding IDs or keys, or a
+// query in the filter language.
+type BulkTaskRequest struct {
+	TaskIDs          []string               `json:"task_ids"`
+	Query            string                 `json:"query"`
+	Operations       []BulkOperationRequest `json:"operations"`
+	Atomic           bool                   `json:"atomic"`
+	OverrideBlockers bool                   `json:"override_blockers"`
+}
+
+// BulkOperationRequest is one operation of a bulk request. Fields holds the
+// changes of an update, as in PUT /tasks.
+type BulkOperationRequest struct {
+	Op       services.BulkAction `json:"op"`
+	Fields   *UpdateTaskRequest  `json:"fields"`
+	Status   models.TaskStatus   `json:"status"`
+	SprintID string              `json:"sprint_id"`
+	LabelID  string              `json:"label_id"`
+}
+
+// ApplyBulk applies operations to many tasks and reports the outcome for each.
+// An atomic request that fails for any task changes none of them and answers
+// 409 Conflict.
+func (h *BulkHandler) ApplyBulk(w http.ResponseWriter, req *http.Request) {
+	var bulkRequest BulkTaskRequest
+	if err := json.NewDecoder(req.Body).Decode(&bulkRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.bulkService.Apply(callerFromRequest(req), h.request(bulkRequest))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.RolledBack {
+		w.WriteHeader(http.StatusConflict)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *BulkHandler) request(r BulkTaskRequest) services.BulkRequest {
+	request := services.BulkRequest{
+		TaskIDs:          r.TaskIDs,
+		Query:            r.Query,
+		Atomic:           r.Atomic,
+		OverrideBlockers: r.OverrideBlockers,
+	}
+	for _, op := range r.Operations {
+		operation := services.BulkOperation{
+			Action:   op.Op,
+			Status:   op.Status,
+			SprintID: op.SprintID,
+			LabelID:  op.LabelID,
+		}
+		if op.Fields != nil {
+			fields := *op.Fields
+			operation.Update = func(task *models.Task) error {
+				return applyTaskUpdate(h.taskService, task, fields)
+			}
+		}
+		request.Operations = append(request.Operations, operation)
+	}
+	return request
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"go-project-manager-backend/internal/interfaces/http/middleware"
+	"net/http"
+)
+
+// callerFromRequest builds the service caller from the claims AuthMiddleware
+// stored in the request context.
+func callerFromRequest(req *http.Request) services.Caller {
+	return services.Caller{
+		UserID:   middleware.GetUserIDFromContext(req.Context()),
+		Role:     models.Role(middleware.GetRoleFromContext(req.Context())),
+		TenantID: middleware.GetTenantIDFromContext(req.Context()),
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type ChecklistHandler struct {
+	checklistService *services.ChecklistService
+	taskService      *services.TaskService
+	projectService   *services.ProjectService
+}
+
+func NewChecklistHandler(checklistService *services.ChecklistService, taskService *services.TaskService, projectService *services.ProjectService) *ChecklistHandler {
+	return &ChecklistHandler{
+		checklistService: checklistService,
+		taskService:      taskService,
+		projectService:   projectService,
+	}
+}
+
+type ChecklistItemRequest struct {
+	Text     string `json:"text"`
+	Required bool   `json:"required"`
+}
+
+type MoveChecklistItemRequest struct {
+	Position int `json:"position"`
+}
+
+type ChecklistPolicyRequest struct {
+	RequireChecklist bool `json:"require_checklist"`
+}
+
+func (h *ChecklistHandler) AddItem(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	var itemRequest ChecklistItemRequest
+	if err := json.NewDecoder(req.Body).Decode(&itemRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.checklistService.AddItem(task.ID, itemRequest.input())
+	if err != nil {
+		writeChecklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *ChecklistHandler) UpdateItem(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	var itemRequest ChecklistItemRequest
+	if err := json.NewDecoder(req.Body).Decode(&itemRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.checklistService.UpdateItem(task.ID, req.PathValue("itemId"), itemRequest.input())
+	if err != nil {
+		writeChecklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *ChecklistHandler) DeleteItem(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	task, err := h.checklistService.DeleteItem(task.ID, req.PathValue("itemId"))
+	if err != nil {
+		writeChecklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *ChecklistHandler) MoveItem(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	var moveRequest MoveChecklistItemRequest
+	if err := json.NewDecoder(req.Body).Decode(&moveRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.checklistService.MoveItem(task.ID, req.PathValue("itemId"), moveRequest.Position)
+	if err != nil {
+		writeChecklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *ChecklistHandler) CheckItem(w http.ResponseWriter, req *http.Request) {
+	h.setChecked(w, req, true)
+}
+
+func (h *ChecklistHandler) UncheckItem(w http.ResponseWriter, req *http.Request) {
+	h.setChecked(w, req, false)
+}
+
+// SetChecklistPolicy sets whether the tasks of a project need their required
+// checklist items checked before they are done.
+func (h *ChecklistHandler) SetChecklistPolicy(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	caller := callerFromRequest(req)
+	if err := h.projectService.CheckAccess(caller, projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var policyRequest ChecklistPolicyRequest
+	if err := json.NewDecoder(req.Body).Decode(&policyRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.checklistService.SetChecklistPolicy(projectID, policyRequest.RequireChecklist)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *ChecklistHandler) setChecked(w http.ResponseWriter, req *http.Request, checked bool) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	task, err := h.checklistService.CheckItem(task.ID, req.PathValue("itemId"), callerFromRequest(req).UserID, checked)
+	if err != nil {
+		writeChecklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *ChecklistHandler) accessibleTask(w http.ResponseWriter, req *http.Request) (*models.Task, bool) {
+	task, err := h.taskService.GetTask(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), task.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	return task, true
+}
+
+func (r ChecklistItemRequest) input() services.ChecklistItemInput {
+	return services.ChecklistItemInput{Text: r.Text, Required: r.Required}
+}
+
+func writeChecklistError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrChecklistItemNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, services.ErrProjectArchived) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
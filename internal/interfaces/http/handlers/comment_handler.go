@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+	taskService    *services.TaskService
+	projectService *services.ProjectService
+}
+
+func NewCommentHandler(commentService *services.CommentService, taskService *services.TaskService, projectService *services.ProjectService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+		taskService:    taskService,
+		projectService: projectService,
+	}
+}
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+func (h *CommentHandler) AddComment(w http.ResponseWriter, req *http.Request) {
+	taskID := req.URL.Query().Get("task_id")
+	if taskID == "" {
+		http.Error(w, "Task ID required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := h.accessibleTask(w, req, taskID); !ok {
+		return
+	}
+
+	var commentRequest CommentRequest
+	if err := json.NewDecoder(req.Body).Decode(&commentRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.commentService.AddComment(taskID, callerFromRequest(req).UserID, commentRequest.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Comment ID required", http.StatusBadRequest)
+		return
+	}
+	if !h.checkCommentAccess(w, req, id) {
+		return
+	}
+
+	var commentRequest CommentRequest
+	if err := json.NewDecoder(req.Body).Decode(&commentRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(callerFromRequest(req), id, commentRequest.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Comment ID required", http.StatusBadRequest)
+		return
+	}
+	if !h.checkCommentAccess(w, req, id) {
+		return
+	}
+
+	err := h.commentService.DeleteComment(callerFromRequest(req), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CommentHandler) ListComments(w http.ResponseWriter, req *http.Request) {
+	taskID := req.URL.Query().Get("task_id")
+	if taskID == "" {
+		http.Error(w, "Task ID required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := h.accessibleTask(w, req, taskID); !ok {
+		return
+	}
+
+	comments, err := h.commentService.ListComments(taskID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+// checkCommentAccess checks that the comment exists and that the caller can
+// reach the project of its task.
+func (h *CommentHandler) checkCommentAccess(w http.ResponseWriter, req *http.Request, id string) bool {
+	comment, err := h.commentService.GetComment(id)
+	if err != nil {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return false
+	}
+	_, ok := h.accessibleTask(w, req, comment.TaskID)
+	return ok
+}
+
+func (h *CommentHandler) accessibleTask(w http.ResponseWriter, req *http.Request, idOrKey string) (*models.Task, bool) {
+	task, err := h.taskService.GetTask(idOrKey)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), task.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	return task, true
+}
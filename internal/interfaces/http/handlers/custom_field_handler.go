@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldHandler struct {
+	customFieldService *services.CustomFieldService
+	projectService     *services.ProjectService
+}
+
+func NewCustomFieldHandler(customFieldService *services.CustomFieldService, projectService *services.ProjectService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldService: customFieldService,
+		projectService:     projectService,
+	}
+}
+
+type CustomFieldRequest struct {
+	Name     string                 `json:"name"`
+	Type     models.CustomFieldType `json:"type"`
+	Required bool                   `json:"required"`
+	Options  []string               `json:"options"`
+	Default  any                    `json:"default"`
+}
+
+// CreateField adds a custom field to a project. Only admins and project
+// managers define fields.
+func (h *CustomFieldHandler) CreateField(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+	if !h.checkProjectAdmin(w, req, projectID) {
+		return
+	}
+
+	var fieldRequest CustomFieldRequest
+	if err := json.NewDecoder(req.Body).Decode(&fieldRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	field, err := h.customFieldService.CreateField(projectID, fieldRequest.definition())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(field)
+}
+
+func (h *CustomFieldHandler) ListFields(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	fields, err := h.customFieldService.ListFields(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fields)
+}
+
+func (h *CustomFieldHandler) UpdateField(w http.ResponseWriter, req *http.Request) {
+	field, err := h.customFieldService.GetField(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Custom field not found", http.StatusNotFound)
+		return
+	}
+	if !h.checkProjectAdmin(w, req, field.ProjectID) {
+		return
+	}
+
+	var fieldRequest CustomFieldRequest
+	if err := json.NewDecoder(req.Body).Decode(&fieldRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	field, err = h.customFieldService.UpdateField(field.ID, fieldRequest.definition())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(field)
+}
+
+// DeleteField deletes a custom field along with its values on every task.
+func (h *CustomFieldHandler) DeleteField(w http.ResponseWriter, req *http.Request) {
+	field, err := h.customFieldService.GetField(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Custom field not found", http.StatusNotFound)
+		return
+	}
+	if !h.checkProjectAdmin(w, req, field.ProjectID) {
+		return
+	}
+
+	if err := h.customFieldService.DeleteField(field.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CustomFieldHandler) checkProjectAdmin(w http.ResponseWriter, req *http.Request, projectID string) bool {
+	caller := callerFromRequest(req)
+	if err := h.projectService.CheckAccess(caller, projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return false
+	}
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (r CustomFieldRequest) definition() services.CustomFieldDefinition {
+	return services.CustomFieldDefinition{
+		Name:     r.Name,
+		Type:     r.Type,
+		Required: r.Required,
+		Options:  r.Options,
+		Default:  r.Default,
+	}
+}
+
+// customFieldFilters reads the custom field filters of a list request, given
+// as cf.<field name or ID>=value.
+func customFieldFilters(params map[string][]string) map[string]string {
+	var filters map[string]string
+	for name, values := range params {
+		field, ok := strings.CutPrefix(name, "cf.")
+		if !ok || field == "" || len(values) == 0 {
+			continue
+		}
+		if filters == nil {
+			filters = make(map[string]string)
+		}
+		filters[field] = values[0]
+	}
+	return filters
+}
+
+// customFieldCells renders the values of custom fields as CSV cells, one per
+// field; multi-select options are separated by semicolons.
+func customFieldCells(fields []*models.CustomField, values map[string]models.CustomFieldValue) []string {
+	cells := make([]string, len(fields))
+	for i, field := range fields {
+		value, ok := values[field.ID]
+		if !ok {
+			continue
+		}
+		switch {
+		case value.Number != nil:
+			cells[i] = strconv.FormatFloat(*value.Number, 'f', -1, 64)
+		case value.Date != nil:
+			cells[i] = value.Date.Format(time.DateOnly)
+		case value.Options != nil:
+			cells[i] = strings.Join(value.Options, ";")
+		default:
+			cells[i] = value.Text
+		}
+	}
+	return cells
+}
+
+func customFieldNames(fields []*models.CustomField) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type DependencyHandler struct {
+	dependencyService *services.DependencyService
+	taskService       *services.TaskService
+	projectService    *services.ProjectService
+}
+
+func NewDependencyHandler(dependencyService *services.DependencyService, taskService *services.TaskService, projectService *services.ProjectService) *DependencyHandler {
+	return &DependencyHandler{
+		dependencyService: dependencyService,
+		taskService:       taskService,
+		projectService:    projectService,
+	}
+}
+
+func (h *DependencyHandler) LinkTasks(w http.ResponseWriter, req *http.Request) {
+	taskID := req.URL.Query().Get("task_id")
+	targetID := req.URL.Query().Get("target_id")
+	linkType := models.LinkType(req.URL.Query().Get("type"))
+	if taskID == "" || targetID == "" || linkType == "" {
+		http.Error(w, "Task ID, target ID and link type required", http.StatusBadRequest)
+		return
+	}
+	if !h.checkLinkAccess(w, req, taskID, targetID) {
+		return
+	}
+
+	task, err := h.dependencyService.LinkTasks(taskID, linkType, targetID)
+	if err != nil {
+		if errors.Is(err, services.ErrDependencyCycle) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *DependencyHandler) UnlinkTasks(w http.ResponseWriter, req *http.Request) {
+	taskID := req.URL.Query().Get("task_id")
+	targetID := req.URL.Query().Get("target_id")
+	if taskID == "" || targetID == "" {
+		http.Error(w, "Task ID and target ID required", http.StatusBadRequest)
+		return
+	}
+	if !h.checkLinkAccess(w, req, taskID, targetID) {
+		return
+	}
+
+	err := h.dependencyService.UnlinkTasks(taskID, targetID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkLinkAccess checks that the caller can reach the projects of both
+// linked tasks.
+func (h *DependencyHandler) checkLinkAccess(w http.ResponseWriter, req *http.Request, taskIDs ...string) bool {
+	caller := callerFromRequest(req)
+	for _, id := range taskIDs {
+		task, err := h.taskService.GetTask(id)
+		if err != nil {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return false
+		}
+		if err := h.projectService.CheckAccess(caller, task.ProjectID); err != nil {
+			writeProjectAccessError(w, err)
+			return false
+		}
+	}
+	return true
+}
+
+// GetGraph returns a project's dependency graph as JSON, or as Graphviz DOT
+// with ?format=dot.
+func (h *DependencyHandler) GetGraph(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	graph, err := h.dependencyService.Graph(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch req.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(graph)
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		w.Write([]byte(graph.DOT()))
+	default:
+		http.Error(w, "Format must be json or dot", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type FilterHandler struct {
+	filterService *services.FilterService
+}
+
+func NewFilterHandler(filterService *services.FilterService) *FilterHandler {
+	return &FilterHandler{
+		filterService: filterService,
+	}
+}
+
+type SaveFilterRequest struct {
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	ProjectID string `json:"project_id"`
+}
+
+func (h *FilterHandler) Search(w http.ResponseWriter, req *http.Request) {
+	tasks, err := h.filterService.Search(callerFromRequest(req), req.URL.Query().Get("q"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+func (h *FilterHandler) CreateFilter(w http.ResponseWriter, req *http.Request) {
+	var filterRequest SaveFilterRequest
+	if err := json.NewDecoder(req.Body).Decode(&filterRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := h.filterService.SaveFilter(callerFromRequest(req), filterRequest.Name, filterRequest.Query, filterRequest.ProjectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(filter)
+}
+
+func (h *FilterHandler) UpdateFilter(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Filter ID required", http.StatusBadRequest)
+		return
+	}
+
+	var filterRequest SaveFilterRequest
+	if err := json.NewDecoder(req.Body).Decode(&filterRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := h.filterService.UpdateFilter(callerFromRequest(req), id, filterRequest.Name, filterRequest.Query, filterRequest.ProjectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(filter)
+}
+
+func (h *FilterHandler) DeleteFilter(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Filter ID required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.filterService.DeleteFilter(callerFromRequest(req), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FilterHandler) ListFilters(w http.ResponseWriter, req *http.Request) {
+	filters, err := h.filterService.ListFilters(callerFromRequest(req))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(filters)
+}
+
+func (h *FilterHandler) RunFilter(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Filter ID required", http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.filterService.RunFilter(callerFromRequest(req), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type FlowHandler struct {
+	flowService        *services.FlowService
+	customFieldService *services.CustomFieldService
+	projectService     *services.ProjectService
+}
+
+func NewFlowHandler(flowService *services.FlowService, customFieldService *services.CustomFieldService, projectService *services.ProjectService) *FlowHandler {
+	return &FlowHandler{
+		flowService:        flowService,
+		customFieldService: customFieldService,
+		projectService:     projectService,
+	}
+}
+
+// GetTaskFlows returns the cycle time, lead time and time in each status of
+// a project's tasks, as JSON or, with ?format=csv, as CSV.
+func (h *FlowHandler) GetTaskFlows(w http.ResponseWriter, req *http.Request) {
+	projectID, filter, ok := h.flowRequest(w, req)
+	if !ok {
+		return
+	}
+
+	flows, err := h.flowService.TaskFlows(projectID, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.URL.Query().Get("format") != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(flows)
+		return
+	}
+
+	fields, err := h.customFieldService.ListFields(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	header := []string{"task_id", "key", "title", "type", "assignee_id", "status", "created_at", "started_at", "done_at", "cycle_time_hours", "lead_time_hours"}
+	for _, status := range services.FlowStatuses {
+		header = append(header, string(status)+"_hours")
+	}
+	rows := [][]string{append(header, customFieldNames(fields)...)}
+	for _, flow := range flows {
+		row := []string{
+			flow.TaskID, flow.Key, flow.Title, string(flow.Type), flow.AssigneeID, string(flow.Status),
+			flow.CreatedAt.Format(time.RFC3339), csvTime(flow.StartedAt), csvTime(flow.DoneAt),
+			csvHours(flow.CycleTime), csvHours(flow.LeadTime),
+		}
+		for _, status := range services.FlowStatuses {
+			hours := flow.TimeInStatus[status]
+			row = append(row, csvHours(&hours))
+		}
+		rows = append(rows, append(row, customFieldCells(fields, flow.CustomFields)...))
+	}
+	writeCSV(w, "task-flow.csv", rows)
+}
+
+// GetThroughput returns the tasks done per week, as JSON or CSV.
+func (h *FlowHandler) GetThroughput(w http.ResponseWriter, req *http.Request) {
+	projectID, filter, ok := h.flowRequest(w, req)
+	if !ok {
+		return
+	}
+
+	weeks, err := h.flowService.Throughput(projectID, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.URL.Query().Get("format") != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(weeks)
+		return
+	}
+
+	rows := [][]string{{"week_start", "tasks", "points"}}
+	for _, week := range weeks {
+		rows = append(rows, []string{week.WeekStart.Format(time.DateOnly), strconv.Itoa(week.Tasks), strconv.Itoa(week.Points)})
+	}
+	writeCSV(w, "throughput.csv", rows)
+}
+
+// GetCumulativeFlow returns the daily task count per status, as JSON or CSV.
+func (h *FlowHandler) GetCumulativeFlow(w http.ResponseWriter, req *http.Request) {
+	projectID, filter, ok := h.flowRequest(w, req)
+	if !ok {
+		return
+	}
+
+	days, err := h.flowService.CumulativeFlow(projectID, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.URL.Query().Get("format") != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(days)
+		return
+	}
+
+	header := []string{"date"}
+	for _, status := range services.FlowStatuses {
+		header = append(header, string(status))
+	}
+	rows := [][]string{header}
+	for _, d := range days {
+		row := []string{d.Date.Format(time.DateOnly)}
+		for _, status := range services.FlowStatuses {
+			row = append(row, strconv.Itoa(d.Counts[status]))
+		}
+		rows = append(rows, row)
+	}
+	writeCSV(w, "cumulative-flow.csv", rows)
+}
+
+// flowRequest checks access to the project in the path and reads the flow
+// filter from the query, writing the error response if either fails.
+func (h *FlowHandler) flowRequest(w http.ResponseWriter, req *http.Request) (string, services.FlowFilter, bool) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return "", services.FlowFilter{}, false
+	}
+
+	filter, err := flowFilterFromQuery(req.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", services.FlowFilter{}, false
+	}
+	return projectID, filter, true
+}
+
+// flowFilterFromQuery reads label_id, assignee_id, type and a from/to range
+// of calendar dates, both inclusive.
+func flowFilterFromQuery(params url.Values) (services.FlowFilter, error) {
+	filter := services.FlowFilter{
+		LabelID:    params.Get("label_id"),
+		AssigneeID: params.Get("assignee_id"),
+		Type:       models.TaskType(params.Get("type")),
+	}
+
+	if raw := params.Get("from"); raw != "" {
+		from, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = from
+	}
+	if raw := params.Get("to"); raw != "" {
+		to, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.To = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return filter, nil
+}
+
+func writeCSV(w http.ResponseWriter, filename string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	csv.NewWriter(w).WriteAll(rows)
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvHours(hours *float64) string {
+	if hours == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*hours, 'f', 2, 64)
+}
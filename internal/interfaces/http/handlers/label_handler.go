@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type LabelHandler struct {
+	labelService   *services.LabelService
+	projectService *services.ProjectService
+}
+
+func NewLabelHandler(labelService *services.LabelService, projectService *services.ProjectService) *LabelHandler {
+	return &LabelHandler{
+		labelService:   labelService,
+		projectService: projectService,
+	}
+}
+
+type LabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (h *LabelHandler) CreateLabel(w http.ResponseWriter, req *http.Request) {
+	projectID := req.URL.Query().Get("project_id")
+	if projectID == "" {
+		http.Error(w, "Project ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	var labelRequest LabelRequest
+	if err := json.NewDecoder(req.Body).Decode(&labelRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	label, err := h.labelService.CreateLabel(projectID, labelRequest.Name, labelRequest.Color)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(label)
+}
+
+func (h *LabelHandler) UpdateLabel(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Label ID required", http.StatusBadRequest)
+		return
+	}
+
+	label, err := h.labelService.GetLabel(id)
+	if err != nil {
+		http.Error(w, "Label not found", http.StatusNotFound)
+		return
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), label.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	var labelRequest LabelRequest
+	if err := json.NewDecoder(req.Body).Decode(&labelRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	label, err = h.labelService.UpdateLabel(id, labelRequest.Name, labelRequest.Color)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(label)
+}
+
+func (h *LabelHandler) DeleteLabel(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Label ID required", http.StatusBadRequest)
+		return
+	}
+
+	label, err := h.labelService.GetLabel(id)
+	if err != nil {
+		http.Error(w, "Label not found", http.StatusNotFound)
+		return
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), label.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	err = h.labelService.DeleteLabel(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LabelHandler) ListLabels(w http.ResponseWriter, req *http.Request) {
+	projectID := req.URL.Query().Get("project_id")
+	if projectID == "" {
+		http.Error(w, "Project ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	labels, err := h.labelService.ListLabels(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(labels)
+}
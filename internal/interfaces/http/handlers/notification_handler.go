@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"strconv"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+	emailService        *services.EmailService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService, emailService *services.EmailService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		emailService:        emailService,
+	}
+}
+
+type NotificationsResponse struct {
+	Notifications []*models.Notification `json:"notifications"`
+	UnreadCount   int                    `json:"unread_count"`
+}
+
+// ListNotifications returns the caller's latest notifications, 50 unless
+// ?limit= says otherwise, only the unread ones with unread=true.
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	limit := 50
+	if raw := query.Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 || v > 200 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = v
+	}
+
+	notifications, unread, err := h.notificationService.ListNotifications(callerFromRequest(req).UserID, query.Get("unread") == "true", limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(NotificationsResponse{Notifications: notifications, UnreadCount: unread})
+}
+
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, req *http.Request) {
+	unread, err := h.notificationService.UnreadCount(callerFromRequest(req).UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"unread_count": unread})
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, req *http.Request) {
+	if err := h.notificationService.MarkRead(callerFromRequest(req).UserID, req.PathValue("id")); err != nil {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, req *http.Request) {
+	marked, err := h.notificationService.MarkAllRead(callerFromRequest(req).UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"marked": marked})
+}
+
+// GetPreferences returns whether each notification event is on for the
+// caller.
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, req *http.Request) {
+	preferences, err := h.notificationService.Preferences(callerFromRequest(req).UserID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preferences)
+}
+
+// SetPreferences turns the notification events in the body, such as
+// {"status_changed": false}, on or off for the caller.
+func (h *NotificationHandler) SetPreferences(w http.ResponseWriter, req *http.Request) {
+	var changes map[models.NotificationEvent]bool
+	if err := json.NewDecoder(req.Body).Decode(&changes); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preferences, err := h.notificationService.SetPreferences(callerFromRequest(req).UserID, changes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preferences)
+}
+
+// GetEmailSettings returns how the caller gets notifications by email.
+func (h *NotificationHandler) GetEmailSettings(w http.ResponseWriter, req *http.Request) {
+	settings, err := h.emailService.EmailSettings(callerFromRequest(req).UserID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// SetEmailSettings replaces how the caller gets notifications by email:
+// delivery (off, instant, daily or weekly), locale, timezone, digest_hour,
+// digest_day and quiet_start / quiet_end.
+func (h *NotificationHandler) SetEmailSettings(w http.ResponseWriter, req *http.Request) {
+	var settings models.EmailSettings
+	if err := json.NewDecoder(req.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.emailService.SetEmailSettings(callerFromRequest(req).UserID, &settings)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
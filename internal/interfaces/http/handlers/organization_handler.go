@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"slices"
+)
+
+type OrganizationHandler struct {
+	organizationService *services.OrganizationService
+	operatorIDs         []string
+}
+
+// NewOrganizationHandler creates the handler. operatorIDs are the default
+// workspace users that run the deployment; the grant comes from configuration,
+// never from a user's role.
+func NewOrganizationHandler(organizationService *services.OrganizationService, operatorIDs []string) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+		operatorIDs:         operatorIDs,
+	}
+}
+
+func (h *OrganizationHandler) isOperator(caller services.Caller) bool {
+	return caller.TenantID == "" && slices.Contains(h.operatorIDs, caller.UserID)
+}
+
+type CreateOrganizationRequest struct {
+	Name  string          `json:"name"`
+	Admin RegisterRequest `json:"admin"`
+}
+
+// CreateOrganization creates an organization and its first admin. Only the
+// operators of the deployment create them.
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, req *http.Request) {
+	if !h.isOperator(callerFromRequest(req)) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var organizationRequest CreateOrganizationRequest
+	if err := json.NewDecoder(req.Body).Decode(&organizationRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	admin := organizationRequest.Admin
+	organization, user, err := h.organizationService.CreateOrganization(organizationRequest.Name, admin.Name, admin.Email, admin.Password)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"organization": organization,
+		"admin":        user,
+	})
+}
+
+func (h *OrganizationHandler) ListOrganizations(w http.ResponseWriter, req *http.Request) {
+	if !h.isOperator(callerFromRequest(req)) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	organizations, err := h.organizationService.ListOrganizations()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organizations)
+}
+
+// GetCurrentOrganization returns the organization of the caller, or 404 in the
+// default workspace.
+func (h *OrganizationHandler) GetCurrentOrganization(w http.ResponseWriter, req *http.Request) {
+	tenantID := callerFromRequest(req).TenantID
+	if tenantID == "" {
+		http.Error(w, "Default workspace has no organization", http.StatusNotFound)
+		return
+	}
+
+	organization, err := h.organizationService.GetOrganization(tenantID)
+	if err != nil {
+		http.Error(w, "Organization not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organization)
+}
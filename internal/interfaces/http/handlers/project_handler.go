@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type ProjectHandler struct {
+	projectService *services.ProjectService
+}
+
+func NewProjectHandler(projectService *services.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+	}
+}
+
+type CreateProjectRequest struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (h *ProjectHandler) CreateProject(w http.ResponseWriter, req *http.Request) {
+	var projectRequest CreateProjectRequest
+	if err := json.NewDecoder(req.Body).Decode(&projectRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	caller := callerFromRequest(req)
+	project, err := h.projectService.CreateProject(projectRequest.Name, projectRequest.Key, projectRequest.Description, caller.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *ProjectHandler) GetProject(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Project ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), id); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	project, err := h.projectService.GetProject(id)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+// ListProjects lists the caller's projects, leaving out archived ones unless
+// include_archived=true.
+func (h *ProjectHandler) ListProjects(w http.ResponseWriter, req *http.Request) {
+	includeArchived := req.URL.Query().Get("include_archived") == "true"
+	projects, err := h.projectService.ListProjects(callerFromRequest(req), includeArchived)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projects)
+}
+
+// ArchiveProject makes a project read-only. Only admins and the project's
+// owner archive projects.
+func (h *ProjectHandler) ArchiveProject(w http.ResponseWriter, req *http.Request) {
+	project, ok := h.managedProject(w, req)
+	if !ok {
+		return
+	}
+
+	project, err := h.projectService.ArchiveProject(callerFromRequest(req).UserID, project.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *ProjectHandler) UnarchiveProject(w http.ResponseWriter, req *http.Request) {
+	project, ok := h.managedProject(w, req)
+	if !ok {
+		return
+	}
+
+	project, err := h.projectService.UnarchiveProject(project.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *ProjectHandler) AddMember(w http.ResponseWriter, req *http.Request) {
+	projectID := req.URL.Query().Get("project_id")
+	userID := req.URL.Query().Get("user_id")
+	if projectID == "" || userID == "" {
+		http.Error(w, "Project ID and User ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	err := h.projectService.AddMember(projectID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, req *http.Request) {
+	projectID := req.URL.Query().Get("project_id")
+	userID := req.URL.Query().Get("user_id")
+	if projectID == "" || userID == "" {
+		http.Error(w, "Project ID and User ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	err := h.projectService.RemoveMember(projectID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type ProjectTeamRequest struct {
+	TeamID string `json:"team_id"`
+}
+
+// AddTeam grants a team membership of a project. Only admins and the
+// project's owner grant it.
+func (h *ProjectHandler) AddTeam(w http.ResponseWriter, req *http.Request) {
+	project, ok := h.managedProject(w, req)
+	if !ok {
+		return
+	}
+
+	var teamRequest ProjectTeamRequest
+	if err := json.NewDecoder(req.Body).Decode(&teamRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if teamRequest.TeamID == "" {
+		http.Error(w, "Team ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.AddTeam(project.ID, teamRequest.TeamID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) RemoveTeam(w http.ResponseWriter, req *http.Request) {
+	project, ok := h.managedProject(w, req)
+	if !ok {
+		return
+	}
+
+	if err := h.projectService.RemoveTeam(project.ID, req.PathValue("teamId")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// managedProject returns the project of the request path if the caller may
+// manage it.
+func (h *ProjectHandler) managedProject(w http.ResponseWriter, req *http.Request) (*models.Project, bool) {
+	projectID := req.PathValue("id")
+
+	caller := callerFromRequest(req)
+	if err := h.projectService.CheckAccess(caller, projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	project, err := h.projectService.GetProject(projectID)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return nil, false
+	}
+	if !canManageProject(caller, project) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return project, true
+}
+
+// canManageProject reports whether the caller may archive, delete or restore
+// a project: admins and the project's owner may.
+func canManageProject(caller services.Caller, project *models.Project) bool {
+	return caller.IsAdmin() || project.OwnerID == caller.UserID
+}
+
+// writeProjectAccessError reports a failed CheckAccess as 403 or 404.
+func writeProjectAccessError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrProjectAccessDenied) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, "Project not found", http.StatusNotFound)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RecurringTaskHandler struct {
+	recurringTaskService *services.RecurringTaskService
+	projectService       *services.ProjectService
+}
+
+func NewRecurringTaskHandler(recurringTaskService *services.RecurringTaskService, projectService *services.ProjectService) *RecurringTaskHandler {
+	return &RecurringTaskHandler{
+		recurringTaskService: recurringTaskService,
+		projectService:       projectService,
+	}
+}
+
+type CreateRecurringTaskRequest struct {
+	ProjectID               string              `json:"project_id"`
+	Title                   string              `json:"title"`
+	Description             string              `json:"description"`
+	AssigneeID              string              `json:"assignee_id"`
+	Type                    models.TaskType     `json:"type"`
+	Priority                models.TaskPriority `json:"priority"`
+	StoryPoints             *int                `json:"story_points"`
+	OriginalEstimateMinutes *int                `json:"original_estimate_minutes"`
+	LabelIDs                []string            `json:"label_ids"`
+	RRule                   string              `json:"rrule"`
+	StartAt                 string              `json:"start_at"`
+	Timezone                string              `json:"timezone"`
+}
+
+// CreateRecurringTask schedules a task from an RRULE. start_at is an RFC 3339
+// timestamp, or a local date-time such as "2025-01-06T09:00" in the timezone.
+func (h *RecurringTaskHandler) CreateRecurringTask(w http.ResponseWriter, req *http.Request) {
+	var recurringRequest CreateRecurringTaskRequest
+	if err := json.NewDecoder(req.Body).Decode(&recurringRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	caller := callerFromRequest(req)
+	if err := h.projectService.CheckAccess(caller, recurringRequest.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	startAt, err := parseStartAt(recurringRequest.StartAt, recurringRequest.Timezone)
+	if err != nil {
+		http.Error(w, "Invalid start_at", http.StatusBadRequest)
+		return
+	}
+
+	details := services.TaskDetails{
+		Type:                    recurringRequest.Type,
+		Priority:                recurringRequest.Priority,
+		StoryPoints:             recurringRequest.StoryPoints,
+		OriginalEstimateMinutes: recurringRequest.OriginalEstimateMinutes,
+		LabelIDs:                recurringRequest.LabelIDs,
+	}
+	schedule := services.TaskSchedule{
+		RRule:    recurringRequest.RRule,
+		StartAt:  startAt,
+		Timezone: recurringRequest.Timezone,
+	}
+
+	recurring, err := h.recurringTaskService.CreateRecurringTask(caller.UserID, recurringRequest.ProjectID,
+		recurringRequest.Title, recurringRequest.Description, recurringRequest.AssigneeID, details, schedule)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (h *RecurringTaskHandler) ListRecurringTasks(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	recurring, err := h.recurringTaskService.ListRecurringTasks(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (h *RecurringTaskHandler) GetRecurringTask(w http.ResponseWriter, req *http.Request) {
+	recurring, ok := h.accessibleRecurringTask(w, req)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (h *RecurringTaskHandler) DeleteRecurringTask(w http.ResponseWriter, req *http.Request) {
+	recurring, ok := h.accessibleRecurringTask(w, req)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringTaskService.DeleteRecurringTask(recurring.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PreviewOccurrences returns when the next ?count= tasks, 10 by default, will
+// be created.
+func (h *RecurringTaskHandler) PreviewOccurrences(w http.ResponseWriter, req *http.Request) {
+	recurring, ok := h.accessibleRecurringTask(w, req)
+	if !ok {
+		return
+	}
+
+	count := 10
+	if value := req.URL.Query().Get("count"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid count", http.StatusBadRequest)
+			return
+		}
+		count = n
+	}
+
+	occurrences, err := h.recurringTaskService.PreviewOccurrences(recurring.ID, count)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(occurrences)
+}
+
+func (h *RecurringTaskHandler) PauseRecurringTask(w http.ResponseWriter, req *http.Request) {
+	recurring, ok := h.accessibleRecurringTask(w, req)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.recurringTaskService.PauseRecurringTask(recurring.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (h *RecurringTaskHandler) ResumeRecurringTask(w http.ResponseWriter, req *http.Request) {
+	recurring, ok := h.accessibleRecurringTask(w, req)
+	if !ok {
+		return
+	}
+
+	recurring, err := h.recurringTaskService.ResumeRecurringTask(recurring.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (h *RecurringTaskHandler) accessibleRecurringTask(w http.ResponseWriter, req *http.Request) (*models.RecurringTask, bool) {
+	recurring, err := h.recurringTaskService.GetRecurringTask(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Recurring task not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), recurring.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	return recurring, true
+}
+
+// parseStartAt reads an RFC 3339 timestamp, or a local date-time in timezone.
+// An empty value means now.
+func parseStartAt(value, timezone string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation("2006-01-02T15:04", value, loc)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"strconv"
+)
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) SearchTasks(w http.ResponseWriter, req *http.Request) {
+	text := req.URL.Query().Get("q")
+	if text == "" {
+		http.Error(w, "Search text required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if raw := req.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	results, err := h.searchService.Search(callerFromRequest(req), text, req.URL.Query().Get("project_id"), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectAccessDenied) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"time"
+)
+
+type SprintHandler struct {
+	sprintService   *services.SprintService
+	burndownService *services.BurndownService
+	taskService     *services.TaskService
+	projectService  *services.ProjectService
+}
+
+func NewSprintHandler(sprintService *services.SprintService, burndownService *services.BurndownService, taskService *services.TaskService, projectService *services.ProjectService) *SprintHandler {
+	return &SprintHandler{
+		sprintService:   sprintService,
+		burndownService: burndownService,
+		taskService:     taskService,
+		projectService:  projectService,
+	}
+}
+
+type CreateSprintRequest struct {
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+func (h *SprintHandler) CreateSprint(w http.ResponseWriter, req *http.Request) {
+	projectID := req.URL.Query().Get("project_id")
+	if projectID == "" {
+		http.Error(w, "Project ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	var sprintRequest CreateSprintRequest
+	if err := json.NewDecoder(req.Body).Decode(&sprintRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	startDate, err := parseSprintDate(sprintRequest.StartDate)
+	if err != nil {
+		http.Error(w, "Invalid start date", http.StatusBadRequest)
+		return
+	}
+	endDate, err := parseSprintDate(sprintRequest.EndDate)
+	if err != nil {
+		http.Error(w, "Invalid end date", http.StatusBadRequest)
+		return
+	}
+
+	sprint, err := h.sprintService.CreateSprint(projectID, sprintRequest.Name, startDate, endDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *SprintHandler) GetSprint(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *SprintHandler) ListSprints(w http.ResponseWriter, req *http.Request) {
+	projectID := req.URL.Query().Get("project_id")
+	if projectID == "" {
+		http.Error(w, "Project ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	sprints, err := h.sprintService.ListSprints(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprints)
+}
+
+func (h *SprintHandler) StartSprint(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.sprintService.StartSprint(sprint.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+// DeleteSprint deletes a sprint that has not started, moving its tasks to the
+// backlog.
+func (h *SprintHandler) DeleteSprint(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	if err := h.sprintService.DeleteSprint(sprint.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CloseSprint closes a sprint, moving its unfinished tasks to the backlog.
+func (h *SprintHandler) CloseSprint(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.sprintService.CloseSprint(sprint.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *SprintHandler) ListTasks(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.taskService.ListTasksBySprint(sprint.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+func (h *SprintHandler) GetBurndown(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	burndown, err := h.burndownService.Burndown(sprint.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(burndown)
+}
+
+// RebuildBurndown recomputes a sprint's stored snapshots from the task
+// activity history.
+func (h *SprintHandler) RebuildBurndown(w http.ResponseWriter, req *http.Request) {
+	sprint, ok := h.accessibleSprint(w, req)
+	if !ok {
+		return
+	}
+
+	burndown, err := h.burndownService.RebuildBurndown(sprint.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(burndown)
+}
+
+// accessibleSprint loads the sprint named in the path and checks that the
+// caller can access its project, writing the error response if not.
+func (h *SprintHandler) accessibleSprint(w http.ResponseWriter, req *http.Request) (*models.Sprint, bool) {
+	sprint, err := h.sprintService.GetSprint(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Sprint not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), sprint.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	return sprint, true
+}
+
+// parseSprintDate reads an RFC 3339 timestamp or a calendar date, which
+// means the start of that day in UTC.
+func parseSprintDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
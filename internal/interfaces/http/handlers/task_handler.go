@@ -2,33 +2,79 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"go-project-manager-backend/internal/domain/models"
 	"go-project-manager-backend/internal/domain/services"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type TaskHandler struct {
-	taskService *services.TaskService
+	taskService        *services.TaskService
+	customFieldService *services.CustomFieldService
+	projectService     *services.ProjectService
 }
 
-func NewTaskHandler(taskService *services.TaskService) *TaskHandler {
+func NewTaskHandler(taskService *services.TaskService, customFieldService *services.CustomFieldService, projectService *services.ProjectService) *TaskHandler {
 	return &TaskHandler{
-		taskService: taskService,
+		taskService:        taskService,
+		customFieldService: customFieldService,
+		projectService:     projectService,
 	}
 }
 
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	ProjectID   string `json:"project_id"`
-	AssigneeID  string `json:"assignee_id"`
+	Title                    string                 `json:"title"`
+	Description              string                 `json:"description"`
+	ProjectID                string                 `json:"project_id"`
+	AssigneeID               string                 `json:"assignee_id"`
+	TeamID                   string                 `json:"team_id"`
+	Type                     models.TaskType        `json:"type"`
+	ParentID                 string                 `json:"parent_id"`
+	Priority                 models.TaskPriority    `json:"priority"`
+	StoryPoints              *int                   `json:"story_points"`
+	DueDate                  string                 `json:"due_date"`
+	DueTimezone              string                 `json:"due_timezone"`
+	LabelIDs                 []string               `json:"label_ids"`
+	OriginalEstimateMinutes  *int                   `json:"original_estimate_minutes"`
+	RemainingEstimateMinutes *int                   `json:"remaining_estimate_minutes"`
+	Checklist                []ChecklistItemRequest `json:"checklist"`
+	CustomFields             map[string]any         `json:"custom_fields"`
 }
 
 type UpdateTaskRequest struct {
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Status      models.TaskStatus `json:"status,omitempty"`
-	AssigneeID  string            `json:"assignee_id"`
+	Title                    string              `json:"title"`
+	Description              string              `json:"description"`
+	Status                   models.TaskStatus   `json:"status,omitempty"`
+	AssigneeID               string              `json:"assignee_id"`
+	TeamID                   *string             `json:"team_id"`
+	Type                     models.TaskType     `json:"type"`
+	ParentID                 *string             `json:"parent_id"`
+	Priority                 models.TaskPriority `json:"priority"`
+	StoryPoints              *int                `json:"story_points"`
+	DueDate                  *string             `json:"due_date"`
+	DueTimezone              string              `json:"due_timezone"`
+	LabelIDs                 []string            `json:"label_ids"`
+	OverrideBlockers         bool                `json:"override_blockers"`
+	OriginalEstimateMinutes  *int                `json:"original_estimate_minutes"`
+	RemainingEstimateMinutes *int                `json:"remaining_estimate_minutes"`
+	CustomFields             map[string]any      `json:"custom_fields"`
+}
+
+type CloneTaskRequest struct {
+	ProjectID        string `json:"project_id"`
+	IncludeSubtasks  bool   `json:"include_subtasks"`
+	IncludeChecklist bool   `json:"include_checklist"`
+}
+
+// AssignToSprintResponse is returned instead of an empty response when the
+// assignment takes the assignee past their capacity.
+type AssignToSprintResponse struct {
+	Warning  string                   `json:"warning"`
+	Workload *services.MemberWorkload `json:"workload"`
 }
 
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, req *http.Request) {
@@ -38,8 +84,40 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	task, err := h.taskService.CreateTask(taskRequest.Title, taskRequest.Description, taskRequest.ProjectID, taskRequest.AssigneeID)
+	dueDate, err := services.ParseDueDate(taskRequest.DueDate, taskRequest.DueTimezone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	details := services.TaskDetails{
+		TeamID:                   taskRequest.TeamID,
+		Type:                     taskRequest.Type,
+		ParentID:                 taskRequest.ParentID,
+		Priority:                 taskRequest.Priority,
+		StoryPoints:              taskRequest.StoryPoints,
+		DueDate:                  dueDate,
+		DueTimezone:              taskRequest.DueTimezone,
+		LabelIDs:                 taskRequest.LabelIDs,
+		OriginalEstimateMinutes:  taskRequest.OriginalEstimateMinutes,
+		RemainingEstimateMinutes: taskRequest.RemainingEstimateMinutes,
+		CustomFields:             taskRequest.CustomFields,
+	}
+	for _, item := range taskRequest.Checklist {
+		details.Checklist = append(details.Checklist, item.input())
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), taskRequest.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	task, err := h.taskService.CreateTask(taskRequest.Title, taskRequest.Description, taskRequest.ProjectID, taskRequest.AssigneeID, details)
 	if err != nil {
+		if errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -56,9 +134,8 @@ func (h *TaskHandler) GetTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	task, err := h.taskService.GetTask(id)
-	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+	task, ok := h.accessibleTask(w, req, id)
+	if !ok {
 		return
 	}
 
@@ -73,9 +150,8 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	task, err := h.taskService.GetTask(id)
-	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+	task, ok := h.accessibleTask(w, req, id)
+	if !ok {
 		return
 	}
 
@@ -85,22 +161,18 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if updateRequest.Title != "" {
-		task.Title = updateRequest.Title
-	}
-	if updateRequest.Description != "" {
-		task.Description = updateRequest.Description
-	}
-	if updateRequest.Status != "" {
-		task.Status = updateRequest.Status
-	}
-	if updateRequest.AssigneeID != "" {
-		task.AssigneeID = updateRequest.AssigneeID
+	if err := applyTaskUpdate(h.taskService, task, updateRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	err = h.taskService.UpdateTask(task)
+	err := h.taskService.UpdateTask(task, services.UpdateOptions{OverrideBlockers: updateRequest.OverrideBlockers})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, services.ErrTaskBlocked) || errors.Is(err, services.ErrChecklistIncomplete) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -108,6 +180,59 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, req *http.Request) {
 	json.NewEncoder(w).Encode(task)
 }
 
+// applyTaskUpdate copies the fields set in an update request onto a task.
+func applyTaskUpdate(taskService *services.TaskService, task *models.Task, r UpdateTaskRequest) error {
+	if r.Title != "" {
+		task.Title = r.Title
+	}
+	if r.Description != "" {
+		task.Description = r.Description
+	}
+	if r.Status != "" {
+		task.Status = r.Status
+	}
+	if r.AssigneeID != "" {
+		task.AssigneeID = r.AssigneeID
+	}
+	if r.TeamID != nil {
+		task.TeamID = *r.TeamID
+	}
+	if r.Type != "" {
+		task.Type = r.Type
+	}
+	if r.ParentID != nil {
+		task.ParentID = *r.ParentID
+	}
+	if r.Priority != models.PriorityNone {
+		task.Priority = r.Priority
+	}
+	if r.StoryPoints != nil {
+		task.StoryPoints = r.StoryPoints
+	}
+	if r.DueTimezone != "" {
+		task.DueTimezone = r.DueTimezone
+	}
+	if r.DueDate != nil {
+		dueDate, err := services.ParseDueDate(*r.DueDate, task.DueTimezone)
+		if err != nil {
+			return err
+		}
+		task.DueDate = dueDate
+	}
+	if r.LabelIDs != nil {
+		task.LabelIDs = r.LabelIDs
+	}
+	if r.OriginalEstimateMinutes != nil {
+		task.OriginalEstimateMinutes = r.OriginalEstimateMinutes
+	}
+	if r.RemainingEstimateMinutes != nil {
+		task.RemainingEstimateMinutes = r.RemainingEstimateMinutes
+	}
+	return taskService.SetCustomFields(task, r.CustomFields)
+}
+
+// DeleteTask moves a task to the trash, or, for admins passing
+// permanent=true, deletes it for good.
 func (h *TaskHandler) DeleteTask(w http.ResponseWriter, req *http.Request) {
 	id := req.URL.Query().Get("id")
 	if id == "" {
@@ -115,8 +240,35 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err := h.taskService.DeleteTask(id)
+	caller := callerFromRequest(req)
+	if req.URL.Query().Get("permanent") == "true" {
+		if !caller.IsAdmin() {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if err := h.taskService.PurgeTask(id); err != nil {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if _, ok := h.accessibleTask(w, req, id); !ok {
+		return
+	}
+
+	policy := services.OrphanPolicy(req.URL.Query().Get("children"))
+	err := h.taskService.DeleteTask(caller.UserID, id, policy, req.URL.Query().Get("parent_id"))
 	if err != nil {
+		if errors.Is(err, services.ErrTaskHasChildren) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if policy != services.OrphansRejected {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Task not found", http.StatusNotFound)
 		return
 	}
@@ -124,6 +276,22 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *TaskHandler) ListChildren(w http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+	if _, ok := h.accessibleTask(w, req, id); !ok {
+		return
+	}
+
+	children, err := h.taskService.ListChildren(id)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(children)
+}
+
 func (h *TaskHandler) AssignToSprint(w http.ResponseWriter, req *http.Request) {
 	taskID := req.URL.Query().Get("task_id")
 	sprintID := req.URL.Query().Get("sprint_id")
@@ -131,13 +299,28 @@ func (h *TaskHandler) AssignToSprint(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, "Task ID and Sprint ID required", http.StatusBadRequest)
 		return
 	}
+	if _, ok := h.accessibleTask(w, req, taskID); !ok {
+		return
+	}
 
-	err := h.taskService.AssignToSprint(taskID, sprintID)
+	warning, err := h.taskService.AssignToSprint(taskID, sprintID)
 	if err != nil {
+		if errors.Is(err, services.ErrCapacityExceeded) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	if warning != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AssignToSprintResponse{
+			Warning:  "assignee is over capacity for this sprint",
+			Workload: warning,
+		})
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -147,6 +330,9 @@ func (h *TaskHandler) MoveToBacklog(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, "Task ID required", http.StatusBadRequest)
 		return
 	}
+	if _, ok := h.accessibleTask(w, req, taskID); !ok {
+		return
+	}
 
 	err := h.taskService.MoveToBacklog(taskID)
 	if err != nil {
@@ -157,29 +343,206 @@ func (h *TaskHandler) MoveToBacklog(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RankTask moves a task in its project's order, just before the task given
+// by before or just after the one given by after.
+func (h *TaskHandler) RankTask(w http.ResponseWriter, req *http.Request) {
+	taskID := req.URL.Query().Get("task_id")
+	if taskID == "" {
+		http.Error(w, "Task ID required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := h.accessibleTask(w, req, taskID); !ok {
+		return
+	}
+
+	task, err := h.taskService.RankTask(taskID, req.URL.Query().Get("before"), req.URL.Query().Get("after"))
+	if err != nil {
+		if errors.Is(err, services.ErrRankConflict) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *TaskHandler) MoveToProject(w http.ResponseWriter, req *http.Request) {
+	taskID := req.URL.Query().Get("task_id")
+	projectID := req.URL.Query().Get("project_id")
+	if taskID == "" || projectID == "" {
+		http.Error(w, "Task ID and Project ID required", http.StatusBadRequest)
+		return
+	}
+	if !h.checkTransferAccess(w, req, taskID, projectID) {
+		return
+	}
+
+	task, err := h.taskService.MoveToProject(taskID, projectID, statusMap(req.URL.Query()))
+	if err != nil {
+		if errors.Is(err, services.ErrRankConflict) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// CloneTask copies a task, into the project given in the body or else its
+// own.
+func (h *TaskHandler) CloneTask(w http.ResponseWriter, req *http.Request) {
+	var cloneRequest CloneTaskRequest
+	if err := json.NewDecoder(req.Body).Decode(&cloneRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !h.checkTransferAccess(w, req, req.PathValue("id"), cloneRequest.ProjectID) {
+		return
+	}
+
+	task, err := h.taskService.CloneTask(req.PathValue("id"), services.CloneOptions{
+		ProjectID:        cloneRequest.ProjectID,
+		IncludeSubtasks:  cloneRequest.IncludeSubtasks,
+		IncludeChecklist: cloneRequest.IncludeChecklist,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(task)
+}
+
+// checkTransferAccess checks that the caller can reach both the project of
+// the task and projectID, the one it moves or is copied to, if any.
+func (h *TaskHandler) checkTransferAccess(w http.ResponseWriter, req *http.Request, taskID, projectID string) bool {
+	if _, ok := h.accessibleTask(w, req, taskID); !ok {
+		return false
+	}
+	if projectID == "" {
+		return true
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return false
+	}
+	return true
+}
+
+// accessibleTask loads a task by ID or key, answering 404 if there is none
+// and 403 if the caller cannot reach its project.
+func (h *TaskHandler) accessibleTask(w http.ResponseWriter, req *http.Request, idOrKey string) (*models.Task, bool) {
+	task, err := h.taskService.GetTask(idOrKey)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), task.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	return task, true
+}
+
+// statusMap reads the statuses to give moved tasks, given as
+// status.<status>=<new status>.
+func statusMap(params url.Values) map[models.TaskStatus]models.TaskStatus {
+	var statuses map[models.TaskStatus]models.TaskStatus
+	for name, values := range params {
+		from, ok := strings.CutPrefix(name, "status.")
+		if !ok || from == "" || len(values) == 0 {
+			continue
+		}
+		if statuses == nil {
+			statuses = make(map[models.TaskStatus]models.TaskStatus)
+		}
+		statuses[models.TaskStatus(from)] = models.TaskStatus(values[0])
+	}
+	return statuses
+}
+
 func (h *TaskHandler) ListTasks(w http.ResponseWriter, req *http.Request) {
 	projectID := req.URL.Query().Get("project_id")
 	if projectID == "" {
 		http.Error(w, "Project ID required", http.StatusBadRequest)
 		return
 	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	filter, err := taskFilterFromQuery(req.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	tasks, err := h.taskService.ListTasksByProject(projectID)
+	tasks, err := h.taskService.ListTasks(projectID, filter)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCustomField) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if req.URL.Query().Get("format") == "csv" {
+		h.writeTasksCSV(w, projectID, tasks)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tasks)
 }
 
+// writeTasksCSV exports tasks as CSV, with a column for each custom field of
+// their project.
+func (h *TaskHandler) writeTasksCSV(w http.ResponseWriter, projectID string, tasks []*models.Task) {
+	fields, err := h.customFieldService.ListFields(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	header := []string{"task_id", "key", "title", "type", "status", "priority", "assignee_id", "sprint_id", "story_points", "due_date", "created_at", "updated_at"}
+	rows := [][]string{append(header, customFieldNames(fields)...)}
+	for _, task := range tasks {
+		sprintID, points := "", ""
+		if task.SprintID != nil {
+			sprintID = *task.SprintID
+		}
+		if task.StoryPoints != nil {
+			points = strconv.Itoa(*task.StoryPoints)
+		}
+		row := []string{
+			task.ID, task.Key, task.Title, string(task.Type), string(task.Status), task.Priority.String(), task.AssigneeID,
+			sprintID, points, csvTime(task.DueDate), task.CreatedAt.Format(time.RFC3339), task.UpdatedAt.Format(time.RFC3339),
+		}
+		rows = append(rows, append(row, customFieldCells(fields, task.CustomFields)...))
+	}
+	writeCSV(w, "tasks.csv", rows)
+}
+
 func (h *TaskHandler) ListBacklog(w http.ResponseWriter, req *http.Request) {
 	projectID := req.URL.Query().Get("project_id")
 	if projectID == "" {
 		http.Error(w, "Project ID required", http.StatusBadRequest)
 		return
 	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
 
 	tasks, err := h.taskService.ListBacklog(projectID)
 	if err != nil {
@@ -190,3 +553,41 @@ func (h *TaskHandler) ListBacklog(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tasks)
 }
+
+// taskFilterFromQuery reads the optional list filters: status, assignee_id,
+// team_id, priority, story_points, label_id, due_before / due_after (dates or
+// RFC 3339 timestamps, read in the zone given by timezone) and cf.<custom
+// field>.
+func taskFilterFromQuery(params url.Values) (services.TaskFilter, error) {
+	filter := services.TaskFilter{
+		Status:       models.TaskStatus(params.Get("status")),
+		AssigneeID:   params.Get("assignee_id"),
+		TeamID:       params.Get("team_id"),
+		LabelID:      params.Get("label_id"),
+		CustomFields: customFieldFilters(params),
+	}
+
+	if raw := params.Get("priority"); raw != "" {
+		priority, err := models.ParsePriority(raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.Priority = priority
+	}
+	if raw := params.Get("story_points"); raw != "" {
+		points, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, errors.New("invalid story_points")
+		}
+		filter.StoryPoints = &points
+	}
+
+	var err error
+	if filter.DueBefore, err = services.ParseDueDate(params.Get("due_before"), params.Get("timezone")); err != nil {
+		return filter, err
+	}
+	if filter.DueAfter, err = services.ParseDueDate(params.Get("due_after"), params.Get("timezone")); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"slices"
+)
+
+type TeamHandler struct {
+	teamService     *services.TeamService
+	workloadService *services.WorkloadService
+	velocityService *services.VelocityService
+	projectService  *services.ProjectService
+}
+
+func NewTeamHandler(teamService *services.TeamService, workloadService *services.WorkloadService, velocityService *services.VelocityService, projectService *services.ProjectService) *TeamHandler {
+	return &TeamHandler{
+		teamService:     teamService,
+		workloadService: workloadService,
+		velocityService: velocityService,
+		projectService:  projectService,
+	}
+}
+
+type TeamRequest struct {
+	Name             string   `json:"name"`
+	LeadID           string   `json:"lead_id"`
+	MemberIDs        []string `json:"member_ids"`
+	DefaultProjectID string   `json:"default_project_id"`
+}
+
+type TeamMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (r TeamRequest) input() services.TeamInput {
+	return services.TeamInput{
+		Name:             r.Name,
+		LeadID:           r.LeadID,
+		MemberIDs:        r.MemberIDs,
+		DefaultProjectID: r.DefaultProjectID,
+	}
+}
+
+// CreateTeam creates a team. Only admins and project managers create teams.
+func (h *TeamHandler) CreateTeam(w http.ResponseWriter, req *http.Request) {
+	caller := callerFromRequest(req)
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var teamRequest TeamRequest
+	if err := json.NewDecoder(req.Body).Decode(&teamRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamService.CreateTeam(teamRequest.input())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(team)
+}
+
+func (h *TeamHandler) GetTeam(w http.ResponseWriter, req *http.Request) {
+	team, err := h.teamService.GetTeam(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(team)
+}
+
+func (h *TeamHandler) ListTeams(w http.ResponseWriter, req *http.Request) {
+	teams, err := h.teamService.ListTeams()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(teams)
+}
+
+// UpdateTeam replaces a team's name, lead, members and default project.
+// Admins, project managers and the team's lead update teams.
+func (h *TeamHandler) UpdateTeam(w http.ResponseWriter, req *http.Request) {
+	team, ok := h.managedTeam(w, req)
+	if !ok {
+		return
+	}
+
+	var teamRequest TeamRequest
+	if err := json.NewDecoder(req.Body).Decode(&teamRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamService.UpdateTeam(team.ID, teamRequest.input())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(team)
+}
+
+// DeleteTeam deletes a team, unassigning its tasks and revoking its project
+// grants. Only admins and project managers delete teams.
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, req *http.Request) {
+	caller := callerFromRequest(req)
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(req.PathValue("id")); err != nil {
+		if errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TeamHandler) AddMember(w http.ResponseWriter, req *http.Request) {
+	team, ok := h.managedTeam(w, req)
+	if !ok {
+		return
+	}
+
+	var memberRequest TeamMemberRequest
+	if err := json.NewDecoder(req.Body).Decode(&memberRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if memberRequest.UserID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamService.AddMember(team.ID, memberRequest.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(team)
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, req *http.Request) {
+	team, ok := h.managedTeam(w, req)
+	if !ok {
+		return
+	}
+
+	team, err := h.teamService.RemoveMember(team.ID, req.PathValue("userId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(team)
+}
+
+// GetWorkload returns the workload of each member of a team in the sprint
+// given with ?sprint_id=, or else in the active sprint of the team's default
+// project, along with the team's tasks nobody has taken yet.
+func (h *TeamHandler) GetWorkload(w http.ResponseWriter, req *http.Request) {
+	workload, err := h.workloadService.TeamWorkload(req.PathValue("id"), req.URL.Query().Get("sprint_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), workload.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(workload)
+}
+
+// GetVelocity returns the velocity of a team over the last closed sprints it
+// had work in, five unless ?sprints= says otherwise. Members of the team,
+// admins and project managers see it.
+func (h *TeamHandler) GetVelocity(w http.ResponseWriter, req *http.Request) {
+	team, err := h.teamService.GetTeam(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+
+	caller := callerFromRequest(req)
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager && !slices.Contains(team.MemberIDs, caller.UserID) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	n, ok := sprintCount(w, req)
+	if !ok {
+		return
+	}
+
+	velocity, err := h.velocityService.TeamVelocity(team.ID, n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(velocity)
+}
+
+// managedTeam returns the team of the request path if the caller may manage
+// it: admins, project managers and the team's lead may.
+func (h *TeamHandler) managedTeam(w http.ResponseWriter, req *http.Request) (*models.Team, bool) {
+	team, err := h.teamService.GetTeam(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	caller := callerFromRequest(req)
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager && team.LeadID != caller.UserID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return team, true
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"time"
+)
+
+type TemplateHandler struct {
+	templateService *services.TemplateService
+	projectService  *services.ProjectService
+}
+
+func NewTemplateHandler(templateService *services.TemplateService, projectService *services.ProjectService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+		projectService:  projectService,
+	}
+}
+
+type CreateTaskFromTemplateRequest struct {
+	ProjectID  string            `json:"project_id"`
+	AssigneeID string            `json:"assignee_id"`
+	Variables  map[string]string `json:"variables"`
+}
+
+type CreateProjectFromTemplateRequest struct {
+	Name      string            `json:"name"`
+	Key       string            `json:"key"`
+	Variables map[string]string `json:"variables"`
+	StartDate string            `json:"start_date"`
+}
+
+func (h *TemplateHandler) CreateTaskTemplate(w http.ResponseWriter, req *http.Request) {
+	var template models.TaskTemplate
+	if err := json.NewDecoder(req.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.templateService.CreateTaskTemplate(callerFromRequest(req), &template)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *TemplateHandler) GetTaskTemplate(w http.ResponseWriter, req *http.Request) {
+	template, err := h.templateService.GetTaskTemplate(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplateHandler) ListTaskTemplates(w http.ResponseWriter, req *http.Request) {
+	templates, err := h.templateService.ListTaskTemplates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *TemplateHandler) UpdateTaskTemplate(w http.ResponseWriter, req *http.Request) {
+	var template models.TaskTemplate
+	if err := json.NewDecoder(req.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.templateService.UpdateTaskTemplate(callerFromRequest(req), req.PathValue("id"), &template)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *TemplateHandler) DeleteTaskTemplate(w http.ResponseWriter, req *http.Request) {
+	if err := h.templateService.DeleteTaskTemplate(callerFromRequest(req), req.PathValue("id")); err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateTaskFromTemplate creates a task, with its subtasks, from a task
+// template in a project the caller can access.
+func (h *TemplateHandler) CreateTaskFromTemplate(w http.ResponseWriter, req *http.Request) {
+	var templateRequest CreateTaskFromTemplateRequest
+	if err := json.NewDecoder(req.Body).Decode(&templateRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), templateRequest.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	task, err := h.templateService.CreateTaskFromTemplate(req.PathValue("id"), templateRequest.ProjectID, templateRequest.AssigneeID, templateRequest.Variables)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *TemplateHandler) CreateProjectTemplate(w http.ResponseWriter, req *http.Request) {
+	var template models.ProjectTemplate
+	if err := json.NewDecoder(req.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.templateService.CreateProjectTemplate(callerFromRequest(req), &template)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *TemplateHandler) GetProjectTemplate(w http.ResponseWriter, req *http.Request) {
+	template, err := h.templateService.GetProjectTemplate(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplateHandler) ListProjectTemplates(w http.ResponseWriter, req *http.Request) {
+	templates, err := h.templateService.ListProjectTemplates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *TemplateHandler) UpdateProjectTemplate(w http.ResponseWriter, req *http.Request) {
+	var template models.ProjectTemplate
+	if err := json.NewDecoder(req.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.templateService.UpdateProjectTemplate(callerFromRequest(req), req.PathValue("id"), &template)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *TemplateHandler) DeleteProjectTemplate(w http.ResponseWriter, req *http.Request) {
+	if err := h.templateService.DeleteProjectTemplate(callerFromRequest(req), req.PathValue("id")); err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateProjectFromTemplate creates a project owned by the caller, with
+// everything its template sets up, in one call.
+func (h *TemplateHandler) CreateProjectFromTemplate(w http.ResponseWriter, req *http.Request) {
+	var templateRequest CreateProjectFromTemplateRequest
+	if err := json.NewDecoder(req.Body).Decode(&templateRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var startDate time.Time
+	if templateRequest.StartDate != "" {
+		var err error
+		if startDate, err = parseSprintDate(templateRequest.StartDate); err != nil {
+			http.Error(w, "Invalid start_date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.templateService.CreateProjectFromTemplate(callerFromRequest(req), req.PathValue("id"),
+		templateRequest.Name, templateRequest.Key, templateRequest.Variables, startDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func writeTemplateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrNotTemplateOwner) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
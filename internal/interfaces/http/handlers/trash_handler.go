@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type TrashHandler struct {
+	trashService   *services.TrashService
+	projectService *services.ProjectService
+}
+
+func NewTrashHandler(trashService *services.TrashService, projectService *services.ProjectService) *TrashHandler {
+	return &TrashHandler{
+		trashService:   trashService,
+		projectService: projectService,
+	}
+}
+
+// DeleteProject moves a project and its tasks to the trash. Only admins and
+// the project's owner delete projects, and only admins passing
+// permanent=true delete them for good.
+func (h *TrashHandler) DeleteProject(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	caller := callerFromRequest(req)
+	if req.URL.Query().Get("permanent") == "true" {
+		if !caller.IsAdmin() {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if err := h.trashService.PurgeProject(projectID); err != nil {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.projectService.CheckAccess(caller, projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+	project, err := h.projectService.GetProject(projectID)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if !canManageProject(caller, project) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := h.trashService.TrashProject(caller.UserID, project.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTaskTrash lists the tasks deleted from a project.
+func (h *TrashHandler) ListTaskTrash(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	tasks, err := h.trashService.ListTaskTrash(projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+func (h *TrashHandler) ListProjectTrash(w http.ResponseWriter, req *http.Request) {
+	projects, err := h.trashService.ListProjectTrash(callerFromRequest(req))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projects)
+}
+
+func (h *TrashHandler) RestoreTask(w http.ResponseWriter, req *http.Request) {
+	task, err := h.trashService.GetTrashedTask(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Task not found in trash", http.StatusNotFound)
+		return
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), task.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	task, err = h.trashService.RestoreTask(task.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrTrashedWithOther) || errors.Is(err, services.ErrProjectArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// RestoreProject brings a project back from the trash. Only admins and the
+// project's owner restore projects.
+func (h *TrashHandler) RestoreProject(w http.ResponseWriter, req *http.Request) {
+	project, err := h.trashService.GetTrashedProject(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Project not found in trash", http.StatusNotFound)
+		return
+	}
+	if !canManageProject(callerFromRequest(req), project) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	project, err = h.trashService.RestoreProject(project.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type UnsubscribeHandler struct {
+	unsubscribeService *services.UnsubscribeService
+}
+
+func NewUnsubscribeHandler(unsubscribeService *services.UnsubscribeService) *UnsubscribeHandler {
+	return &UnsubscribeHandler{
+		unsubscribeService: unsubscribeService,
+	}
+}
+
+// ConfirmUnsubscribe shows the page an email's unsubscribe link opens, for
+// the signed ?token= of the link, with a form to confirm.
+func (h *UnsubscribeHandler) ConfirmUnsubscribe(w http.ResponseWriter, req *http.Request) {
+	page, err := h.unsubscribeService.ConfirmUnsubscribe(req.URL.Query().Get("token"))
+	if err != nil {
+		http.Error(w, "Invalid unsubscribe link", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// Unsubscribe turns notification emails off for the user of the signed
+// ?token=. It answers both the confirmation form and the one-click POST of
+// mail clients (RFC 8058).
+func (h *UnsubscribeHandler) Unsubscribe(w http.ResponseWriter, req *http.Request) {
+	page, err := h.unsubscribeService.Unsubscribe(req.URL.Query().Get("token"))
+	if err != nil {
+		http.Error(w, "Invalid unsubscribe link", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
@@ -6,6 +6,7 @@ import (
 	"go-project-manager-backend/internal/domain/services"
 	"go-project-manager-backend/pkg/jwt"
 	"net/http"
+	"slices"
 )
 
 type UserHandler struct {
@@ -30,7 +31,26 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// Register signs a user up into the default workspace as a developer or a
+// project manager. Asking for the admin role is refused with 403: admins are
+// added by another admin through CreateUser, or created along with their
+// organization.
 func (h *UserHandler) Register(w http.ResponseWriter, req *http.Request) {
+	h.register(w, req, models.Developer, models.ProjectManager)
+}
+
+// CreateUser adds a user to the caller's organization. Only its admins add
+// users; anyone else registers into the default workspace.
+func (h *UserHandler) CreateUser(w http.ResponseWriter, req *http.Request) {
+	if !callerFromRequest(req).IsAdmin() {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	h.register(w, req, models.Admin, models.Developer, models.ProjectManager)
+}
+
+// register creates the user in the request body when its role is one of roles.
+func (h *UserHandler) register(w http.ResponseWriter, req *http.Request, roles ...models.Role) {
 	var registerRequest RegisterRequest
 	if err := json.NewDecoder(req.Body).Decode(&registerRequest); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -38,7 +58,11 @@ func (h *UserHandler) Register(w http.ResponseWriter, req *http.Request) {
 	}
 
 	role := models.Role(registerRequest.Role)
-	if role != models.Admin && role != models.Developer && role != models.ProjectManager {
+	if role == models.Admin && !slices.Contains(roles, role) {
+		http.Error(w, "Admins cannot register themselves: an existing admin adds them through POST /users", http.StatusForbidden)
+		return
+	}
+	if !slices.Contains(roles, role) {
 		http.Error(w, "Invalid role", http.StatusBadRequest)
 		return
 	}
@@ -66,16 +90,17 @@ func (h *UserHandler) Login(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	token, err := jwt.GenerateToken(user.ID, user.Email, string(user.Role))
+	token, err := jwt.GenerateToken(user.ID, user.Email, string(user.Role), user.OrganizationID)
 	if err != nil {
 		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
 		return
 	}
 
 	response := map[string]string{
-		"token":   token,
-		"user_id": user.ID,
-		"role":    string(user.Role),
+		"token":           token,
+		"user_id":         user.ID,
+		"role":            string(user.Role),
+		"organization_id": user.OrganizationID,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
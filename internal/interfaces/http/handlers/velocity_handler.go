@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"strconv"
+)
+
+type VelocityHandler struct {
+	velocityService *services.VelocityService
+	projectService  *services.ProjectService
+}
+
+func NewVelocityHandler(velocityService *services.VelocityService, projectService *services.ProjectService) *VelocityHandler {
+	return &VelocityHandler{
+		velocityService: velocityService,
+		projectService:  projectService,
+	}
+}
+
+// GetVelocity returns the velocity of a project over its last closed
+// sprints, five unless ?sprints= says otherwise.
+func (h *VelocityHandler) GetVelocity(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	n, ok := sprintCount(w, req)
+	if !ok {
+		return
+	}
+
+	velocity, err := h.velocityService.Velocity(projectID, n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(velocity)
+}
+
+// GetForecast forecasts when a project's backlog, or the epic given with
+// ?epic_id=, will be done. ?unit=tasks forecasts task counts instead of
+// story points, and ?sprints= sets how many closed sprints are sampled.
+func (h *VelocityHandler) GetForecast(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	n, ok := sprintCount(w, req)
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+	forecast, err := h.velocityService.Forecast(projectID, query.Get("epic_id"), services.ForecastUnit(query.Get("unit")), n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forecast)
+}
+
+func sprintCount(w http.ResponseWriter, req *http.Request) (int, bool) {
+	n := 5
+	if raw := req.URL.Query().Get("sprints"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			http.Error(w, "Invalid sprint count", http.StatusBadRequest)
+			return 0, false
+		}
+		n = v
+	}
+	return n, true
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+)
+
+type WorkloadHandler struct {
+	workloadService *services.WorkloadService
+	projectService  *services.ProjectService
+}
+
+func NewWorkloadHandler(workloadService *services.WorkloadService, projectService *services.ProjectService) *WorkloadHandler {
+	return &WorkloadHandler{
+		workloadService: workloadService,
+		projectService:  projectService,
+	}
+}
+
+type CapacityPolicyRequest struct {
+	BlockOverCapacity bool `json:"block_over_capacity"`
+}
+
+// GetWorkload returns the workload of each member of a project in its active
+// sprint, or in the sprint given with ?sprint_id=.
+func (h *WorkloadHandler) GetWorkload(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	workload, err := h.workloadService.Workload(projectID, req.URL.Query().Get("sprint_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(workload)
+}
+
+func (h *WorkloadHandler) SetCapacityPolicy(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	var policyRequest CapacityPolicyRequest
+	if err := json.NewDecoder(req.Body).Decode(&policyRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.workloadService.SetCapacityPolicy(projectID, policyRequest.BlockOverCapacity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+// SetCapacity replaces a user's capacity. Users set their own; admins and
+// project managers can set anyone's.
+func (h *WorkloadHandler) SetCapacity(w http.ResponseWriter, req *http.Request) {
+	userID := req.PathValue("id")
+
+	caller := callerFromRequest(req)
+	if caller.UserID != userID && !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var capacity *models.UserCapacity
+	if err := json.NewDecoder(req.Body).Decode(&capacity); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.workloadService.SetCapacity(userID, capacity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
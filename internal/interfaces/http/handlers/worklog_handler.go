@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-project-manager-backend/internal/domain/models"
+	"go-project-manager-backend/internal/domain/services"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type WorklogHandler struct {
+	worklogService *services.WorklogService
+	taskService    *services.TaskService
+	projectService *services.ProjectService
+}
+
+func NewWorklogHandler(worklogService *services.WorklogService, taskService *services.TaskService, projectService *services.ProjectService) *WorklogHandler {
+	return &WorklogHandler{
+		worklogService: worklogService,
+		taskService:    taskService,
+		projectService: projectService,
+	}
+}
+
+type WorklogRequest struct {
+	Start   string `json:"start"`
+	Minutes int    `json:"minutes"`
+	Note    string `json:"note"`
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note"`
+}
+
+type TimesheetLockRequest struct {
+	LockedUntil *string `json:"locked_until"`
+}
+
+// LogWork records time the caller spent on a task. The start defaults to now
+// less the logged minutes.
+func (h *WorklogHandler) LogWork(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	var worklogRequest WorklogRequest
+	if err := json.NewDecoder(req.Body).Decode(&worklogRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now().Add(-time.Duration(worklogRequest.Minutes) * time.Minute)
+	if worklogRequest.Start != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, worklogRequest.Start); err != nil {
+			http.Error(w, "Invalid start", http.StatusBadRequest)
+			return
+		}
+	}
+
+	worklog, err := h.worklogService.LogWork(callerFromRequest(req).UserID, task.ID, start, worklogRequest.Minutes, worklogRequest.Note)
+	if err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(worklog)
+}
+
+func (h *WorklogHandler) ListWorklogs(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	worklogs, err := h.worklogService.ListWorklogs(task.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worklogs)
+}
+
+func (h *WorklogHandler) UpdateWorklog(w http.ResponseWriter, req *http.Request) {
+	var worklogRequest WorklogRequest
+	if err := json.NewDecoder(req.Body).Decode(&worklogRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var start time.Time
+	if worklogRequest.Start != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, worklogRequest.Start); err != nil {
+			http.Error(w, "Invalid start", http.StatusBadRequest)
+			return
+		}
+	}
+
+	worklog, err := h.worklogService.UpdateWorklog(callerFromRequest(req), req.PathValue("id"), start, worklogRequest.Minutes, worklogRequest.Note)
+	if err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worklog)
+}
+
+func (h *WorklogHandler) DeleteWorklog(w http.ResponseWriter, req *http.Request) {
+	if err := h.worklogService.DeleteWorklog(callerFromRequest(req), req.PathValue("id")); err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WorklogHandler) StartTimer(w http.ResponseWriter, req *http.Request) {
+	task, ok := h.accessibleTask(w, req)
+	if !ok {
+		return
+	}
+
+	var timerRequest StartTimerRequest
+	if req.ContentLength != 0 {
+		if err := json.NewDecoder(req.Body).Decode(&timerRequest); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	timer, err := h.worklogService.StartTimer(callerFromRequest(req).UserID, task.ID, timerRequest.Note)
+	if err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(timer)
+}
+
+// StopTimer stops the caller's timer and returns the worklog it recorded.
+func (h *WorklogHandler) StopTimer(w http.ResponseWriter, req *http.Request) {
+	worklog, err := h.worklogService.StopTimer(callerFromRequest(req).UserID)
+	if err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worklog)
+}
+
+// DiscardTimer stops the caller's timer without logging its time.
+func (h *WorklogHandler) DiscardTimer(w http.ResponseWriter, req *http.Request) {
+	if err := h.worklogService.DiscardTimer(callerFromRequest(req).UserID); err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WorklogHandler) GetTimer(w http.ResponseWriter, req *http.Request) {
+	timer, err := h.worklogService.GetTimer(callerFromRequest(req).UserID)
+	if err != nil {
+		writeWorklogError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(timer)
+}
+
+// GetUserTimesheet returns a user's timesheet for the week of ?week=, this
+// week by default. Users see their own; admins and project managers can see
+// anyone's.
+func (h *WorklogHandler) GetUserTimesheet(w http.ResponseWriter, req *http.Request) {
+	userID := req.PathValue("id")
+
+	caller := callerFromRequest(req)
+	if caller.UserID != userID && !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	week, ok := timesheetWeek(w, req)
+	if !ok {
+		return
+	}
+
+	timesheet, err := h.worklogService.UserTimesheet(userID, week)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeTimesheet(w, req, timesheet, "timesheet-"+userID)
+}
+
+// GetProjectTimesheet returns the time logged on a project in the week of
+// ?week=, by task and user.
+func (h *WorklogHandler) GetProjectTimesheet(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	if err := h.projectService.CheckAccess(callerFromRequest(req), projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+
+	week, ok := timesheetWeek(w, req)
+	if !ok {
+		return
+	}
+
+	timesheet, err := h.worklogService.ProjectTimesheet(projectID, week)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeTimesheet(w, req, timesheet, "timesheet-"+projectID)
+}
+
+// LockTimesheets locks a project's worklogs before locked_until; a null
+// locked_until unlocks them. Only admins and project managers can lock.
+func (h *WorklogHandler) LockTimesheets(w http.ResponseWriter, req *http.Request) {
+	projectID := req.PathValue("id")
+
+	caller := callerFromRequest(req)
+	if err := h.projectService.CheckAccess(caller, projectID); err != nil {
+		writeProjectAccessError(w, err)
+		return
+	}
+	if !caller.IsAdmin() && caller.Role != models.ProjectManager {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var lockRequest TimesheetLockRequest
+	if err := json.NewDecoder(req.Body).Decode(&lockRequest); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var until *time.Time
+	if lockRequest.LockedUntil != nil {
+		t, err := parseSprintDate(*lockRequest.LockedUntil)
+		if err != nil {
+			http.Error(w, "Invalid locked_until", http.StatusBadRequest)
+			return
+		}
+		until = &t
+	}
+
+	project, err := h.worklogService.LockTimesheets(projectID, until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *WorklogHandler) accessibleTask(w http.ResponseWriter, req *http.Request) (*models.Task, bool) {
+	task, err := h.taskService.GetTask(req.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err := h.projectService.CheckAccess(callerFromRequest(req), task.ProjectID); err != nil {
+		writeProjectAccessError(w, err)
+		return nil, false
+	}
+	return task, true
+}
+
+func timesheetWeek(w http.ResponseWriter, req *http.Request) (time.Time, bool) {
+	value := req.URL.Query().Get("week")
+	if value == "" {
+		return time.Now(), true
+	}
+	week, err := parseSprintDate(value)
+	if err != nil {
+		http.Error(w, "Invalid week", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return week, true
+}
+
+// writeTimesheet writes a timesheet as JSON or, with ?format=csv, as CSV with
+// one column of minutes per day.
+func writeTimesheet(w http.ResponseWriter, req *http.Request, timesheet *services.Timesheet, name string) {
+	if req.URL.Query().Get("format") != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(timesheet)
+		return
+	}
+
+	header := []string{"task_id", "task_key", "task_title", "user_id"}
+	for d := range timesheet.DayMinutes {
+		header = append(header, timesheet.From.AddDate(0, 0, d).Format(time.DateOnly))
+	}
+	rows := [][]string{append(header, "total")}
+	for _, row := range timesheet.Rows {
+		line := []string{row.TaskID, row.TaskKey, row.TaskTitle, row.UserID}
+		for _, minutes := range row.DayMinutes {
+			line = append(line, strconv.Itoa(minutes))
+		}
+		rows = append(rows, append(line, strconv.Itoa(row.TotalMinutes)))
+	}
+	total := []string{"", "", "total", ""}
+	for _, minutes := range timesheet.DayMinutes {
+		total = append(total, strconv.Itoa(minutes))
+	}
+	rows = append(rows, append(total, strconv.Itoa(timesheet.TotalMinutes)))
+	writeCSV(w, name+"-"+timesheet.From.Format(time.DateOnly)+".csv", rows)
+}
+
+func writeWorklogError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotWorklogOwner):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, services.ErrTimesheetLocked), errors.Is(err, services.ErrTimerRunning), errors.Is(err, services.ErrProjectArchived):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrNoTimer):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -30,6 +30,7 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
 		ctx = context.WithValue(ctx, "role", claims.Role)
+		ctx = context.WithValue(ctx, "tenant_id", claims.TenantID)
 		r = r.WithContext(ctx)
 
 		next(w, r)
@@ -50,6 +51,15 @@ func GetRoleFromContext(ctx context.Context) string {
 	return ""
 }
 
+// GetTenantIDFromContext returns the caller's organization, "" for the default
+// workspace.
+func GetTenantIDFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value("tenant_id").(string); ok {
+		return tenantID
+	}
+	return ""
+}
+
 func RequireRole(requiredRole string) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
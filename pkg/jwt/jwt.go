@@ -12,11 +12,13 @@ import (
 )
 
 // Claims representa los datos del JWT
+// TenantID es la organización del usuario; vacío para el espacio por defecto
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	Exp    int64  `json:"exp"`
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Exp      int64  `json:"exp"`
 }
 
 func getSecretKey() []byte {
@@ -27,12 +29,13 @@ func getSecretKey() []byte {
 	return []byte(secret)
 }
 
-func GenerateToken(userID, email, role string) (string, error) {
+func GenerateToken(userID, email, role, tenantID string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
-		Exp:    time.Now().Add(24 * time.Hour).Unix(), // expires in 24 hours
+		UserID:   userID,
+		Email:    email,
+		Role:     role,
+		TenantID: tenantID,
+		Exp:      time.Now().Add(24 * time.Hour).Unix(), // expires in 24 hours
 	}
 
 	header := map[string]string{